@@ -2,28 +2,37 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"log"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	"wallet/internal/app"
+	"wallet/internal/auth"
+	"wallet/internal/cli"
 	"wallet/internal/config"
-	"wallet/internal/domain"
 	"wallet/internal/handler"
-	"wallet/internal/infrastructure/cache"
+	"wallet/internal/health"
 	postgresRepo "wallet/internal/infrastructure/postgres"
-	"wallet/internal/infrastructure/redis"
-	"wallet/internal/usecase"
+	"wallet/internal/logging"
+	"wallet/internal/middleware"
+	"wallet/internal/worker"
 
 	"wallet/docs" // Import the generated docs
 
 	"github.com/getsentry/sentry-go"
 	"github.com/gofiber/fiber/v3"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
+// readinessDrainDelay is how long the server keeps serving after it starts
+// failing readiness, so in-flight routing decisions can catch up.
+const readinessDrainDelay = 5 * time.Second
+
+// replicaCheckInterval is how often the read replica's health and lag are checked.
+const replicaCheckInterval = 5 * time.Second
+
 // @title Wallet App API
 // @version 1.0
 // @description This is a sample wallet API.
@@ -36,8 +45,8 @@ func main() {
 		log.Fatal("cannot load configuration:", err)
 	}
 
-	// 2. Initialize Logger
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	// 2. Initialize Logger (request-scoped values such as the request ID are added from the context)
+	logger := slog.New(logging.NewContextHandler(slog.NewJSONHandler(os.Stdout, nil)))
 	slog.SetDefault(logger)
 
 	// 3. Initialize Sentry
@@ -46,55 +55,207 @@ func main() {
 	}
 	defer sentry.Flush(2 * time.Second)
 
-	// 4. Connect to Database
-	db, err := gorm.Open(postgres.Open(cfg.DBSource), &gorm.Config{})
+	// 4. Connect to Database and Redis, and wire the use cases
+	container, err := app.New(cfg, logger)
 	if err != nil {
 		slog.Error("Cannot connect to database", "error", err)
 		sentry.CaptureException(err)
 		os.Exit(1)
 	}
-	db.AutoMigrate(&domain.User{}, &domain.Wallet{})
+	db := container.DB
 
-	// 5. Dependency Injection (Wiring)
-	postgresUserRepo := postgresRepo.NewPostgresUserRepository(db)
-	cacheRepo, err := redis.NewRedisCacheRepository(cfg.RedisAddr)
-	if err != nil {
-		slog.Error("Cannot connect to Redis", "error", err)
-		sentry.CaptureException(err)
-		os.Exit(1)
+	// `api migrate <command>` manages the schema and exits without serving.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := cli.RunMigrate(context.Background(), container.Migrator, db, os.Args[2:], os.Stdout); err != nil {
+			slog.Error("Migration failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cfg.MigrateOnStartup {
+		if err := container.Migrator.Up(context.Background()); err != nil {
+			slog.Error("Cannot apply migrations", "error", err)
+			sentry.CaptureException(err)
+			os.Exit(1)
+		}
+		if err := postgresRepo.VerifySchema(context.Background(), db); err != nil {
+			slog.Error("Database schema does not match the models", "error", err)
+			sentry.CaptureException(err)
+		}
+	}
+
+	// Background jobs stop once the server has shut down.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go container.Cluster.MonitorReplica(workersCtx, replicaCheckInterval)
+
+	if cfg.ReconciliationEnabled {
+		at, err := timeOfDay(cfg.ReconciliationTime)
+		if err != nil {
+			slog.Error("Invalid RECONCILIATION_TIME, expected HH:MM", "error", err)
+			os.Exit(1)
+		}
+		go worker.NewReconciliationWorker(container.ReconciliationUsecase, at, logger).Start(workersCtx)
+	}
+	if cfg.InterestEnabled {
+		at, err := timeOfDay(cfg.InterestTime)
+		if err != nil {
+			slog.Error("Invalid INTEREST_TIME, expected HH:MM", "error", err)
+			os.Exit(1)
+		}
+		go worker.NewInterestWorker(container.InterestUsecase, at, logger).Start(workersCtx)
+	}
+	go worker.NewTransferBatchWorker(container.TransferBatchUsecase, cfg.TransferBatchPollInterval, logger).Start(workersCtx)
+	go worker.NewScheduledTransferWorker(container.ScheduleUsecase, cfg.ScheduledTransferPollInterval, logger).Start(workersCtx)
+	go worker.NewPaymentRequestExpiryWorker(container.PaymentRequestUsecase, cfg.PaymentRequestExpiryInterval, logger).Start(workersCtx)
+	go worker.NewAnalyticsWorker(container.AnalyticsUsecase, cfg.AnalyticsPollInterval, logger).Start(workersCtx)
+	go worker.NewFeeCreditWorker(container.FeeUsecase, cfg.FeeCreditInterval, logger).Start(workersCtx)
+	if container.RiskRules != nil {
+		go container.RiskRules.Watch(workersCtx, cfg.RiskRulesReloadInterval)
+	}
+	if container.Sanctions != nil {
+		go container.Sanctions.Watch(workersCtx, cfg.SanctionsReloadInterval)
 	}
-	// Wrap the postgres repo with the cache decorator
-	userRepo := cache.NewCachedUserRepository(cacheRepo, postgresUserRepo)
 
-	walletRepo := postgresRepo.NewPostgresWalletRepository(db)
-	txnRepo := postgresRepo.NewPostgresTxnRepository(db)
+	// Readiness probes dependencies with a short timeout and reuses the result briefly.
+	checker := health.NewChecker(2*time.Second, time.Second)
+	checker.Register("postgres", func(ctx context.Context) error { return postgresRepo.Ping(ctx, db) })
+	if container.Cache != nil {
+		checker.RegisterOptional("redis", container.Cache.Ping)
+	}
+	if container.Replica != nil {
+		// Reads fall back to the primary, so a lagging replica doesn't make us not ready.
+		checker.RegisterOptional("postgres_replica", container.Cluster.PingReplica)
+	}
+
+	userHandler := handler.NewUserHandler(container.UserUsecase)
+	walletHandler := handler.NewWalletHandler(container.WalletUsecase, container.RecipientUsecase, logger)
+	interestHandler := handler.NewInterestHandler(container.InterestUsecase, logger)
+	healthHandler := handler.NewHealthHandler(checker)
 
-	userUsecase := usecase.NewUserUsecase(userRepo, walletRepo, txnRepo)
-	walletUsecase := usecase.NewWalletUsecase(walletRepo, txnRepo, logger)
+	signingKey := []byte(cfg.StatementSigningKey)
+	if len(signingKey) == 0 {
+		slog.Warn("STATEMENT_SIGNING_KEY is not set, statements won't verify after a restart or on other instances")
+		signingKey = make([]byte, 32)
+		if _, err := rand.Read(signingKey); err != nil {
+			slog.Error("Cannot generate a statement signing key", "error", err)
+			os.Exit(1)
+		}
+	}
+	statementHandler := handler.NewStatementHandler(container.StatementUsecase, signingKey, logger)
 
-	userHandler := handler.NewUserHandler(userUsecase)
-	walletHandler := handler.NewWalletHandler(walletUsecase, logger)
+	operatorTokens, err := config.ParseOperatorTokens(cfg.OperatorTokens)
+	if err != nil {
+		slog.Error("Invalid OPERATOR_TOKENS", "error", err)
+		os.Exit(1)
+	}
+	if len(operatorTokens) == 0 {
+		slog.Warn("OPERATOR_TOKENS is not set, the admin routes refuse every request")
+	}
+	var userTokens *auth.UserTokens
+	if cfg.UserTokenKey != "" {
+		userTokens = auth.NewUserTokens([]byte(cfg.UserTokenKey))
+	} else {
+		slog.Warn("USER_TOKEN_KEY is not set, the user routes refuse every request")
+	}
+	transferBatchHandler := handler.NewTransferBatchHandler(container.TransferBatchUsecase, logger)
+	scheduleHandler := handler.NewScheduledTransferHandler(container.ScheduleUsecase, logger)
+	paymentRequestHandler := handler.NewPaymentRequestHandler(container.PaymentRequestUsecase, logger)
+	memberHandler := handler.NewWalletMemberHandler(container.MemberUsecase, logger)
+	pocketHandler := handler.NewPocketHandler(container.PocketUsecase, logger)
+	analyticsHandler := handler.NewAnalyticsHandler(container.AnalyticsUsecase, logger)
+	reviewHandler := handler.NewTransferReviewHandler(container.ReviewUsecase, logger)
+	screeningHandler := handler.NewScreeningHandler(container.ScreeningUsecase, logger)
+	adjustmentHandler := handler.NewAdjustmentHandler(container.AdjustmentUsecase, logger)
 
 	// 6. Setup Web Server (Fiber)
-	app := fiber.New()
+	server := fiber.New()
+
+	// Correlate every log line and Sentry event of a request through its X-Request-ID.
+	server.Use(middleware.RequestID())
+	server.Use(middleware.AccessLog(logger))
+	server.Use(middleware.ReadYourWrites())
+
+	// Kubernetes probes
+	server.Get("/health/live", healthHandler.Live)
+	server.Get("/health/ready", healthHandler.Ready)
 
 	// Swagger documentation endpoints
-	app.Get("/swagger/doc.json", func(c fiber.Ctx) error {
+	server.Get("/swagger/doc.json", func(c fiber.Ctx) error {
 		return c.JSON(docs.SwaggerInfo)
 	})
 
 	// Simple Swagger UI redirect
-	app.Get("/swagger", func(c fiber.Ctx) error {
+	server.Get("/swagger", func(c fiber.Ctx) error {
 		swaggerURL := "https://petstore.swagger.io/?url=" + c.BaseURL() + "/swagger/doc.json"
 		return c.Redirect().To(swaggerURL)
 	})
 
-	api := app.Group("/api")
+	api := server.Group("/api")
 	v1 := api.Group("/v1")
 
+	// Signing up and checking a statement are open to anyone.
 	v1.Post("/users", userHandler.CreateUser)
-	v1.Post("/wallets/recharge", walletHandler.Recharge)
-	v1.Post("/wallets/transfer", walletHandler.Transfer)
+	v1.Post("/statements/verify", statementHandler.VerifyStatement)
+
+	// Operator endpoints, attributed to the operator whose token is presented.
+	admin := v1.Group("/admin", middleware.Operator(operatorTokens))
+	admin.Get("/transfer-reviews", reviewHandler.List)
+	admin.Get("/transfer-reviews/:id", reviewHandler.Get)
+	admin.Post("/transfer-reviews/:id/approve", reviewHandler.Approve)
+	admin.Post("/transfer-reviews/:id/reject", reviewHandler.Reject)
+	admin.Get("/screening-hits", screeningHandler.List)
+	admin.Get("/screening-hits/:id", screeningHandler.Get)
+	admin.Post("/screening-hits/:id/confirm", screeningHandler.Confirm)
+	admin.Post("/screening-hits/:id/clear", screeningHandler.Clear)
+	admin.Post("/adjustments", adjustmentHandler.Request)
+	admin.Get("/adjustments", adjustmentHandler.List)
+	admin.Get("/adjustments/:id", adjustmentHandler.Get)
+	admin.Post("/adjustments/:id/approve", adjustmentHandler.Approve)
+	admin.Post("/adjustments/:id/reject", adjustmentHandler.Reject)
+
+	// Every other route acts as the user its token authenticates and is
+	// refused without one; see WalletMember. It is registered last, so it
+	// doesn't apply to the routes above.
+	users := v1.Group("", middleware.User(userTokens), middleware.RequireUser())
+	users.Post("/users/:id/aliases", userHandler.AddAlias)
+	users.Post("/users/:id/aliases/:alias_id/verify", userHandler.VerifyAlias)
+	users.Get("/users/:id/aliases", userHandler.ListAliases)
+	users.Delete("/users/:id/aliases/:alias_id", userHandler.RemoveAlias)
+	users.Get("/users/:id/insights", analyticsHandler.GetInsights)
+	users.Put("/movements/:id/category", analyticsHandler.Categorize)
+	users.Get("/recipients/lookup", walletHandler.LookupRecipient)
+	users.Get("/wallets/:id", walletHandler.GetWallet)
+	users.Get("/wallets/:id/statements", statementHandler.GetStatement)
+	users.Get("/wallets/:id/fees", walletHandler.PreviewFee)
+	users.Get("/wallets/:id/interest", interestHandler.GetSummary)
+	users.Post("/wallets/:id/members", memberHandler.Invite)
+	users.Get("/wallets/:id/members", memberHandler.List)
+	users.Post("/wallets/:id/members/:user_id/accept", memberHandler.Accept)
+	users.Delete("/wallets/:id/members/:user_id", memberHandler.Remove)
+	users.Post("/wallets/:id/pockets", pocketHandler.Create)
+	users.Get("/wallets/:id/pockets", pocketHandler.List)
+	users.Get("/pockets/:id", pocketHandler.Get)
+	users.Get("/pockets/:id/movements", pocketHandler.ListMovements)
+	users.Post("/pockets/:id/deposit", pocketHandler.Deposit)
+	users.Post("/pockets/:id/withdraw", pocketHandler.Withdraw)
+	users.Delete("/pockets/:id", pocketHandler.Close)
+	users.Post("/wallets/recharge", walletHandler.Recharge)
+	users.Post("/wallets/transfer", walletHandler.Transfer)
+	users.Post("/wallets/transfers/batch", transferBatchHandler.Submit)
+	users.Get("/wallets/transfers/batch/:id", transferBatchHandler.Get)
+	users.Get("/wallets/:id/scheduled-transfers", scheduleHandler.ListByWallet)
+	users.Post("/scheduled-transfers", scheduleHandler.Create)
+	users.Get("/scheduled-transfers/:id", scheduleHandler.Get)
+	users.Patch("/scheduled-transfers/:id", scheduleHandler.Update)
+	users.Delete("/scheduled-transfers/:id", scheduleHandler.Cancel)
+	users.Get("/scheduled-transfers/:id/executions", scheduleHandler.ListExecutions)
+	users.Get("/wallets/:id/payment-requests", paymentRequestHandler.ListOpen)
+	users.Post("/payment-requests", paymentRequestHandler.Create)
+	users.Get("/payment-requests/:id", paymentRequestHandler.Get)
+	users.Post("/payment-requests/:id/accept", paymentRequestHandler.Accept)
+	users.Post("/payment-requests/:id/decline", paymentRequestHandler.Decline)
 
 	// 7. Start Server with Graceful Shutdown
 	port := cfg.ServerPort
@@ -109,7 +270,7 @@ func main() {
 	// Start server in a goroutine
 	go func() {
 		slog.Info("Starting server", "port", port)
-		if err := app.Listen(":" + port); err != nil {
+		if err := server.Listen(":" + port); err != nil {
 			slog.Error("Failed to start server", "error", err)
 			sentry.CaptureException(err)
 		}
@@ -119,22 +280,34 @@ func main() {
 	<-c
 	slog.Info("Shutting down server gracefully...")
 
+	// Report not-ready first and give the load balancer time to stop routing to us.
+	checker.SetShuttingDown()
+	time.Sleep(readinessDrainDelay)
+
 	// Create a context with timeout for graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
 	// Attempt graceful shutdown
-	if err := app.ShutdownWithContext(ctx); err != nil {
+	if err := server.ShutdownWithContext(ctx); err != nil {
 		slog.Error("Server forced to shutdown", "error", err)
 		sentry.CaptureException(err)
 	}
 
 	// Close database connections
-	if sqlDB, err := db.DB(); err == nil {
-		if err := sqlDB.Close(); err != nil {
-			slog.Error("Failed to close database connection", "error", err)
-		}
+	stopWorkers()
+	if err := container.Close(); err != nil {
+		slog.Error("Failed to close database connection", "error", err)
 	}
 
 	slog.Info("Server shutdown complete")
 }
+
+// timeOfDay parses an HH:MM time of day into its offset from midnight.
+func timeOfDay(s string) (time.Duration, error) {
+	at, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute, nil
+}
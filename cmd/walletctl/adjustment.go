@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"wallet/internal/cli"
+	"wallet/internal/domain"
+)
+
+var adjustmentHeader = []string{"ID", "WALLET ID", "AMOUNT", "STATUS", "REQUESTED BY", "REVIEWED BY", "MOVEMENT", "CREATED", "REASON", "NOTE"}
+
+func runAdjustment(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: walletctl adjustment list [--status S] | adjustment approve|reject <id> [--note N]")
+	}
+
+	switch args[0] {
+	case "list":
+		return listAdjustments(ctx, e, args[1:])
+	case "approve":
+		return decideAdjustment(ctx, e, "adjustment approve", args[1:], e.AdjustmentUsecase.Approve)
+	case "reject":
+		return decideAdjustment(ctx, e, "adjustment reject", args[1:], e.AdjustmentUsecase.Reject)
+	default:
+		return fmt.Errorf("unknown adjustment command %q", args[0])
+	}
+}
+
+func listAdjustments(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("adjustment list")
+	status := fs.String("status", string(domain.AdjustmentPending), "pending, approved, rejected or failed; empty for all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	requests, err := e.AdjustmentUsecase.List(ctx, domain.AdjustmentRequestStatus(*status))
+	if err != nil {
+		return err
+	}
+	return printAdjustments(e, requests)
+}
+
+func decideAdjustment(ctx context.Context, e *env, name string, args []string, decide func(ctx context.Context, id, note string) (*domain.AdjustmentRequest, error)) error {
+	fs := newFlagSet(name)
+	note := fs.String("note", "", "reason for the decision")
+	id, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	request, err := decide(ctx, id, *note)
+	if err != nil {
+		return err
+	}
+	if err := printAdjustments(e, []domain.AdjustmentRequest{*request}); err != nil {
+		return err
+	}
+	if request.Status == domain.AdjustmentFailed {
+		return fmt.Errorf("adjustment failed: %s", request.Note)
+	}
+	return nil
+}
+
+func printAdjustments(e *env, requests []domain.AdjustmentRequest) error {
+	table := cli.Table{Header: adjustmentHeader}
+	for _, r := range requests {
+		movement := ""
+		if r.MovementID != nil {
+			movement = *r.MovementID
+		}
+		table.Rows = append(table.Rows, []string{
+			r.ID, r.WalletID, formatAmount(r.Amount), string(r.Status), r.RequestedBy, r.ReviewedBy,
+			movement, formatTime(r.CreatedAt), r.Reason, r.Note,
+		})
+	}
+	return cli.Print(e.out, e.format, requests, table)
+}
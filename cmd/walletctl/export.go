@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// exportPageSize is how many rows are read per query, so exports of large
+// tables neither hold a long-running transaction nor load everything at once.
+const exportPageSize = 500
+
+// Export formats. JSON exports hold one object per line (JSON Lines).
+const (
+	exportJSON = "json"
+	exportCSV  = "csv"
+)
+
+// exporter writes exported records one at a time.
+type exporter interface {
+	write(record interface{}, row []string) error
+	flush() error
+}
+
+type jsonExporter struct{ enc *json.Encoder }
+
+func (x jsonExporter) write(record interface{}, _ []string) error { return x.enc.Encode(record) }
+func (x jsonExporter) flush() error                               { return nil }
+
+type csvExporter struct{ w *csv.Writer }
+
+func (x csvExporter) write(_ interface{}, row []string) error { return x.w.Write(row) }
+func (x csvExporter) flush() error {
+	x.w.Flush()
+	return x.w.Error()
+}
+
+func newExporter(out io.Writer, format string, header []string) (exporter, error) {
+	switch format {
+	case exportJSON:
+		return jsonExporter{enc: json.NewEncoder(out)}, nil
+	case exportCSV:
+		w := csv.NewWriter(out)
+		if err := w.Write(header); err != nil {
+			return nil, err
+		}
+		return csvExporter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+func runExport(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: walletctl export users|wallets|movements [flags]")
+	}
+
+	fs := newFlagSet("export " + args[0])
+	format := fs.String("format", exportJSON, "json (one object per line) or csv")
+	walletID := fs.String("wallet", "", "wallet whose movements are exported")
+	from, to := timeRange(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "users":
+		x, err := newExporter(e.out, *format, userHeader)
+		if err != nil {
+			return err
+		}
+		return exportUsers(ctx, e, x)
+	case "wallets":
+		x, err := newExporter(e.out, *format, walletHeader)
+		if err != nil {
+			return err
+		}
+		return exportWallets(ctx, e, x)
+	case "movements":
+		if *walletID == "" {
+			return errors.New("--wallet is required")
+		}
+		start, end, err := parseTimeRange(*from, *to)
+		if err != nil {
+			return err
+		}
+		x, err := newExporter(e.out, *format, movementHeader)
+		if err != nil {
+			return err
+		}
+		movements, err := e.WalletUsecase.ListMovements(ctx, *walletID, start, end)
+		if err != nil {
+			return err
+		}
+		for i := range movements {
+			if err := x.write(movements[i], movementRow(&movements[i])); err != nil {
+				return err
+			}
+		}
+		return x.flush()
+	default:
+		return fmt.Errorf("unknown export %q", args[0])
+	}
+}
+
+func exportUsers(ctx context.Context, e *env, x exporter) error {
+	afterID := ""
+	for {
+		users, err := e.UserRepo.List(ctx, afterID, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for i := range users {
+			record := newUserRecord(&users[i])
+			if err := x.write(record, record.row()); err != nil {
+				return err
+			}
+		}
+		if len(users) < exportPageSize {
+			return x.flush()
+		}
+		afterID = users[len(users)-1].ID
+	}
+}
+
+func exportWallets(ctx context.Context, e *env, x exporter) error {
+	afterID := ""
+	for {
+		wallets, err := e.WalletRepo.List(ctx, afterID, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for i := range wallets {
+			if err := x.write(wallets[i], walletRow(&wallets[i])); err != nil {
+				return err
+			}
+		}
+		if len(wallets) < exportPageSize {
+			return x.flush()
+		}
+		afterID = wallets[len(wallets)-1].ID
+	}
+}
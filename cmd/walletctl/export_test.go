@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"wallet/internal/app"
+	"wallet/internal/domain"
+)
+
+// pagedUserRepo lists its users ordered by ID and records the pages asked for.
+type pagedUserRepo struct {
+	domain.UserRepository
+	users []domain.User
+	pages []string // afterID of every List call
+}
+
+func (r *pagedUserRepo) List(ctx context.Context, afterID string, limit int) ([]domain.User, error) {
+	r.pages = append(r.pages, afterID)
+	start, _ := slices.BinarySearchFunc(r.users, afterID, func(u domain.User, id string) int { return strings.Compare(u.ID, id) })
+	if start < len(r.users) && r.users[start].ID == afterID {
+		start++
+	}
+	return r.users[start:min(start+limit, len(r.users))], nil
+}
+
+func newPagedUserRepo(n int) *pagedUserRepo {
+	r := &pagedUserRepo{}
+	for i := range n {
+		r.users = append(r.users, domain.User{ID: fmt.Sprintf("u%04d", i), Username: fmt.Sprintf("user%d", i), Tier: "standard"})
+	}
+	return r
+}
+
+// Exports read the table a page at a time and write every row once.
+func TestExportUsersPagesThroughTheTable(t *testing.T) {
+	repo := newPagedUserRepo(exportPageSize + 1)
+	var out bytes.Buffer
+	e := &env{Container: &app.Container{UserRepo: repo}, out: &out}
+
+	if err := runExport(context.Background(), e, []string{"users", "--format", "csv"}); err != nil {
+		t.Fatalf("export users: %v", err)
+	}
+
+	if want := []string{"", "u0499"}; !slices.Equal(repo.pages, want) {
+		t.Errorf("pages read after = %q, want %q", repo.pages, want)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	if len(records) != exportPageSize+2 {
+		t.Fatalf("CSV has %d records, want a header and %d rows", len(records), exportPageSize+1)
+	}
+	if !slices.Equal(records[0], userHeader) {
+		t.Errorf("header = %q, want %q", records[0], userHeader)
+	}
+	if last := records[len(records)-1]; last[0] != "u0500" || last[1] != "user500" {
+		t.Errorf("last row = %q, want user u0500", last)
+	}
+}
+
+func TestExportUsersAsJSONLines(t *testing.T) {
+	var out bytes.Buffer
+	e := &env{Container: &app.Container{UserRepo: newPagedUserRepo(3)}, out: &out}
+
+	if err := runExport(context.Background(), e, []string{"users"}); err != nil {
+		t.Fatalf("export users: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want one per user:\n%s", len(lines), out.String())
+	}
+	var record userRecord
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("decode %q: %v", lines[1], err)
+	}
+	if record.ID != "u0001" || record.Tier != "standard" {
+		t.Errorf("second line = %+v, want user u0001", record)
+	}
+}
+
+func TestExportRejectsBadArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown format", []string{"users", "--format", "xml"}, `unknown export format "xml"`},
+		{"unknown table", []string{"merchants"}, `unknown export "merchants"`},
+		{"movements of no wallet", []string{"movements"}, "--wallet is required"},
+		{"bad time range", []string{"movements", "--wallet", "w1", "--from", "yesterday"}, "invalid --from"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &env{Container: &app.Container{UserRepo: newPagedUserRepo(0)}, out: &bytes.Buffer{}}
+			err := runExport(context.Background(), e, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("runExport(%q) = %v, want an error containing %q", tt.args, err, tt.want)
+			}
+		})
+	}
+}
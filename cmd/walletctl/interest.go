@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"wallet/internal/cli"
+)
+
+func runInterest(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: walletctl interest run [--through D] | interest show <id>")
+	}
+
+	switch args[0] {
+	case "run":
+		return runInterestAccrual(ctx, e, args[1:])
+	case "show":
+		return showInterest(ctx, e, args[1:])
+	default:
+		return fmt.Errorf("unknown interest command %q", args[0])
+	}
+}
+
+func runInterestAccrual(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("interest run")
+	through := fs.String("through", "", "last day to accrue (default yesterday)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	day := time.Now().UTC().AddDate(0, 0, -1)
+	if *through != "" {
+		var err error
+		if day, err = time.Parse(time.DateOnly, *through); err != nil {
+			return fmt.Errorf("invalid --through: %w", err)
+		}
+	}
+
+	report, err := e.InterestUsecase.Run(ctx, day)
+	if err != nil {
+		return err
+	}
+	return cli.Print(e.out, e.format, report, cli.Table{
+		Header: []string{"FROM", "THROUGH", "ACCRUED", "WALLETS PAID", "PAID"},
+		Rows: [][]string{{
+			report.From.Format(time.DateOnly), report.Through.Format(time.DateOnly),
+			fmt.Sprint(report.Accrued), fmt.Sprint(report.Posted), formatAmount(report.Paid),
+		}},
+	})
+}
+
+func showInterest(ctx context.Context, e *env, args []string) error {
+	id, err := parseArgs(newFlagSet("interest show"), args)
+	if err != nil {
+		return err
+	}
+
+	summary, err := e.InterestUsecase.Summary(ctx, id)
+	if err != nil {
+		return err
+	}
+	through := ""
+	if summary.AccruedThrough != nil {
+		through = summary.AccruedThrough.Format(time.DateOnly)
+	}
+	return cli.Print(e.out, e.format, summary, cli.Table{
+		Header: []string{"WALLET ID", "PRODUCT", "RATE %", "PENDING", "ACCRUED THROUGH"},
+		Rows: [][]string{{
+			summary.WalletID, summary.Product, fmt.Sprintf("%.4f", summary.InterestRate),
+			formatAmount(summary.Pending), through,
+		}},
+	})
+}
@@ -0,0 +1,192 @@
+// Command walletctl performs operator tasks against the wallet database
+// without going through the HTTP API. Every operation goes through the same
+// use cases as the API, so business rules, caching and auditing apply.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"os/user"
+	"strings"
+	"syscall"
+	"time"
+	"wallet/internal/app"
+	"wallet/internal/cli"
+	"wallet/internal/config"
+	"wallet/internal/domain"
+	"wallet/internal/logging"
+
+	"github.com/getsentry/sentry-go"
+)
+
+const usage = `usage: walletctl [--output table|json] <command> [arguments]
+
+Changes are attributed to the system account running walletctl.
+
+commands:
+  user create --username U --name N --dni D     create a user and its wallet
+  user tier <id> --tier T --reason R            move a user to another fee tier
+  wallet show <id>                              show a wallet
+  wallet movements <id> [--from T] [--to T]     list the movements of a wallet
+  wallet credit <id> --amount A --reason R      request to credit a wallet (manual adjustment)
+  wallet debit <id> --amount A --reason R       request to debit a wallet (manual adjustment)
+  wallet freeze <id> --reason R                 freeze a wallet
+  wallet unfreeze <id> --reason R               unfreeze a wallet
+  wallet product <id> --product P --reason R    change the product (and interest rate) of a wallet
+  wallet merchant <id> --name N --category C    mark a wallet as a merchant's for insights
+  export users|wallets [--format json|csv]      export every user or wallet
+  export movements --wallet <id> [--format json|csv] [--from T] [--to T]
+  reconcile                                     check balances against the ledger
+  interest run [--through D]                    accrue interest through a day and pay ended months
+  interest show <id>                            show the pending interest of a wallet
+  review list [--status S]                      list the transfers held by the risk rules (default pending)
+  review approve <id> [--note N]                make a held transfer
+  review reject <id> [--note N]                 reject a held transfer
+  screening list [--status S]                   list the users matching the sanctions list (default pending)
+  screening confirm <id> [--note N]             confirm a sanctions match, blocking the user
+  screening clear <id> [--note N]               clear a sanctions match as a false positive
+  adjustment list [--status S]                  list the adjustments requested (default pending)
+  adjustment approve <id> [--note N]            apply an adjustment another operator requested
+  adjustment reject <id> [--note N]             reject an adjustment another operator requested
+  migrate <command>                             manage the schema (see walletctl migrate)
+
+Times are RFC 3339, e.g. 2024-01-31T00:00:00Z; days are 2024-01-31.`
+
+// env is what every command runs with.
+type env struct {
+	*app.Container
+	out    io.Writer
+	format string
+}
+
+type command func(ctx context.Context, e *env, args []string) error
+
+var commands = map[string]command{
+	"user":       runUser,
+	"wallet":     runWallet,
+	"export":     runExport,
+	"reconcile":  runReconcile,
+	"interest":   runInterest,
+	"review":     runReview,
+	"screening":  runScreening,
+	"adjustment": runAdjustment,
+	"migrate":    runMigrate,
+}
+
+func main() {
+	flag.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	format := flag.String("output", cli.FormatTable, "output format: table or json")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	run, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s\n", flag.Arg(0), usage)
+		os.Exit(2)
+	}
+	// The operator is the account the system logged in, not a name passed on
+	// the command line or in the environment, so that the operator who
+	// requests an adjustment can't approve it as someone else.
+	account, err := user.Current()
+	if err != nil || account.Username == "" {
+		fmt.Fprintln(os.Stderr, "cannot tell which system account runs walletctl:", err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("cannot load configuration:", err)
+	}
+
+	// Logs go to stderr so they don't mix with the command output.
+	logger := slog.New(logging.NewContextHandler(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	slog.SetDefault(logger)
+
+	if err := sentry.Init(sentry.ClientOptions{Dsn: cfg.SentryDSN}); err != nil {
+		slog.Error("Sentry initialization failed", "error", err)
+	}
+	defer sentry.Flush(2 * time.Second)
+
+	container, err := app.New(cfg, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cannot connect to database:", err)
+		os.Exit(1)
+	}
+	defer container.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = domain.WithVerifiedActor(ctx, domain.OperatorActor(account.Username))
+
+	err = run(ctx, &env{Container: container, out: os.Stdout, format: *format}, flag.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "walletctl:", err)
+		os.Exit(1)
+	}
+}
+
+func runMigrate(ctx context.Context, e *env, args []string) error {
+	return cli.RunMigrate(ctx, e.Migrator, e.DB, args, e.out)
+}
+
+// parseArgs parses the flags of a subcommand that takes one positional
+// argument, which may come before or after the flags.
+func parseArgs(fs *flag.FlagSet, args []string) (string, error) {
+	var positional string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		positional, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if positional == "" {
+		positional = fs.Arg(0)
+	}
+	if positional == "" {
+		return "", errors.New("missing ID")
+	}
+	return positional, nil
+}
+
+// timeRange registers the --from and --to flags, defaulting to all time.
+func timeRange(fs *flag.FlagSet) (from, to *string) {
+	return fs.String("from", "", "start of the range (inclusive)"), fs.String("to", "", "end of the range (exclusive)")
+}
+
+func parseTimeRange(from, to string) (time.Time, time.Time, error) {
+	start, end := time.Time{}, time.Now().Add(time.Second)
+	var err error
+	if from != "" {
+		if start, err = time.Parse(time.RFC3339, from); err != nil {
+			return start, end, fmt.Errorf("invalid --from: %w", err)
+		}
+	}
+	if to != "" {
+		if end, err = time.Parse(time.RFC3339, to); err != nil {
+			return start, end, fmt.Errorf("invalid --to: %w", err)
+		}
+	}
+	return start, end, nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
+
+func formatAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
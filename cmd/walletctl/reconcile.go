@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"wallet/internal/cli"
+	"wallet/internal/domain"
+	"wallet/internal/worker"
+)
+
+var discrepancyHeader = []string{"KIND", "WALLET ID", "CURRENCY", "LEDGER", "BALANCE", "DIFFERENCE"}
+
+func runReconcile(ctx context.Context, e *env, args []string) error {
+	if err := newFlagSet("reconcile").Parse(args); err != nil {
+		return err
+	}
+
+	report, err := worker.Reconcile(ctx, e.ReconciliationUsecase, domain.ReconciliationManual, nil)
+	if err != nil {
+		return err
+	}
+
+	table := cli.Table{Header: discrepancyHeader}
+	for i := range report.Discrepancies {
+		d := &report.Discrepancies[i]
+		walletID := ""
+		if d.WalletID != nil {
+			walletID = *d.WalletID
+		}
+		table.Rows = append(table.Rows, []string{
+			string(d.Kind), walletID, d.Currency,
+			formatAmount(d.Expected), formatAmount(d.Actual), formatAmount(d.Difference()),
+		})
+	}
+	if err := cli.Print(e.out, e.format, report, table); err != nil {
+		return err
+	}
+	run := report.Run
+	if e.format == cli.FormatTable {
+		fmt.Fprintf(e.out, "\nrun %s: %d wallets checked, %d discrepancies\n", run.ID, run.WalletsChecked, run.Discrepancies)
+	}
+	// Fail so scripts notice without parsing the output.
+	if run.Discrepancies > 0 {
+		return fmt.Errorf("%d discrepancies found", run.Discrepancies)
+	}
+	return nil
+}
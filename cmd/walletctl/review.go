@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"wallet/internal/cli"
+	"wallet/internal/domain"
+)
+
+var reviewHeader = []string{"ID", "FROM", "TO", "AMOUNT", "SCORE", "STATUS", "ACTOR", "CREATED", "REASONS"}
+
+func runReview(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: walletctl review list [--status S] | review approve|reject <id> [--note N]")
+	}
+
+	switch args[0] {
+	case "list":
+		return listReviews(ctx, e, args[1:])
+	case "approve":
+		return decideReview(ctx, e, "review approve", args[1:], e.ReviewUsecase.Approve)
+	case "reject":
+		return decideReview(ctx, e, "review reject", args[1:], e.ReviewUsecase.Reject)
+	default:
+		return fmt.Errorf("unknown review command %q", args[0])
+	}
+}
+
+func listReviews(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("review list")
+	status := fs.String("status", string(domain.ReviewPending), "pending, approved, rejected or failed; empty for all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	reviews, err := e.ReviewUsecase.List(ctx, domain.TransferReviewStatus(*status))
+	if err != nil {
+		return err
+	}
+	return printReviews(e, reviews)
+}
+
+func decideReview(ctx context.Context, e *env, name string, args []string, decide func(ctx context.Context, id, note string) (*domain.TransferReview, error)) error {
+	fs := newFlagSet(name)
+	note := fs.String("note", "", "reason for the decision")
+	id, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	review, err := decide(ctx, id, *note)
+	if err != nil {
+		return err
+	}
+	if err := printReviews(e, []domain.TransferReview{*review}); err != nil {
+		return err
+	}
+	if review.Status == domain.ReviewFailed {
+		return fmt.Errorf("transfer failed: %s", review.Note)
+	}
+	return nil
+}
+
+func printReviews(e *env, reviews []domain.TransferReview) error {
+	table := cli.Table{Header: reviewHeader}
+	for _, r := range reviews {
+		table.Rows = append(table.Rows, []string{
+			r.ID, r.FromWalletID, r.ToWalletID, formatAmount(r.Amount), fmt.Sprint(r.Score),
+			string(r.Status), r.Actor, formatTime(r.CreatedAt), r.Reasons,
+		})
+	}
+	return cli.Print(e.out, e.format, reviews, table)
+}
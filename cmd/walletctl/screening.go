@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"wallet/internal/cli"
+	"wallet/internal/domain"
+)
+
+var screeningHeader = []string{"ID", "USER", "NAME", "ENTRY", "ENTRY NAME", "SCORE", "STATUS", "SOURCE", "CREATED"}
+
+func runScreening(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: walletctl screening list [--status S] | screening confirm|clear <id> [--note N]")
+	}
+
+	switch args[0] {
+	case "list":
+		return listHits(ctx, e, args[1:])
+	case "confirm":
+		return decideHit(ctx, e, "screening confirm", args[1:], e.ScreeningUsecase.Confirm)
+	case "clear":
+		return decideHit(ctx, e, "screening clear", args[1:], e.ScreeningUsecase.Clear)
+	default:
+		return fmt.Errorf("unknown screening command %q", args[0])
+	}
+}
+
+func listHits(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("screening list")
+	status := fs.String("status", string(domain.HitPending), "pending, confirmed or cleared; empty for all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	hits, err := e.ScreeningUsecase.List(ctx, domain.ScreeningHitStatus(*status))
+	if err != nil {
+		return err
+	}
+	return printHits(e, hits)
+}
+
+func decideHit(ctx context.Context, e *env, name string, args []string, decide func(ctx context.Context, id, note string) (*domain.ScreeningHit, error)) error {
+	fs := newFlagSet(name)
+	note := fs.String("note", "", "reason for the decision")
+	id, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	hit, err := decide(ctx, id, *note)
+	if err != nil {
+		return err
+	}
+	return printHits(e, []domain.ScreeningHit{*hit})
+}
+
+func printHits(e *env, hits []domain.ScreeningHit) error {
+	table := cli.Table{Header: screeningHeader}
+	for _, h := range hits {
+		table.Rows = append(table.Rows, []string{
+			h.ID, h.UserID, h.ScreenedName, h.EntryID, h.EntryName, fmt.Sprint(h.Score),
+			string(h.Status), string(h.Source), formatTime(h.CreatedAt),
+		})
+	}
+	return cli.Print(e.out, e.format, hits, table)
+}
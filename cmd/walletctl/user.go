@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"wallet/internal/cli"
+	"wallet/internal/domain"
+)
+
+// userRecord is how users are printed and exported.
+type userRecord struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+	DNI       string `json:"dni"`
+	Tier      string `json:"tier"`
+	CreatedAt string `json:"created_at"`
+}
+
+func newUserRecord(user *domain.User) userRecord {
+	return userRecord{
+		ID:        user.ID,
+		Username:  user.Username,
+		Name:      user.Name,
+		DNI:       user.DNI,
+		Tier:      user.Tier,
+		CreatedAt: formatTime(user.CreatedAt),
+	}
+}
+
+var userHeader = []string{"ID", "USERNAME", "NAME", "DNI", "TIER", "CREATED AT"}
+
+func (r userRecord) row() []string {
+	return []string{r.ID, r.Username, r.Name, r.DNI, r.Tier, r.CreatedAt}
+}
+
+func runUser(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: walletctl user create --username U --name N --dni D | user tier <id> --tier T --reason R")
+	}
+
+	switch args[0] {
+	case "create":
+		return createUser(ctx, e, args[1:])
+	case "tier":
+		return setUserTier(ctx, e, args[1:])
+	default:
+		return fmt.Errorf("unknown user command %q", args[0])
+	}
+}
+
+func createUser(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("user create")
+	username := fs.String("username", "", "unique username")
+	name := fs.String("name", "", "full name")
+	dni := fs.String("dni", "", "national identity document")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" || *name == "" || *dni == "" {
+		return errors.New("--username, --name and --dni are required")
+	}
+
+	user, err := e.UserUsecase.Create(ctx, *username, *name, *dni)
+	if err != nil {
+		return fmt.Errorf("create user: %w", err)
+	}
+	record := newUserRecord(user)
+	return cli.Print(e.out, e.format, record, cli.Table{Header: userHeader, Rows: [][]string{record.row()}})
+}
+
+func setUserTier(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("user tier")
+	tier := fs.String("tier", "", "tier the user moves to, e.g. standard or premium")
+	reason := fs.String("reason", "", "why the tier changes (recorded in the audit log)")
+	id, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	user, err := e.UserUsecase.SetTier(ctx, id, *tier, *reason)
+	if err != nil {
+		return err
+	}
+	record := newUserRecord(user)
+	return cli.Print(e.out, e.format, record, cli.Table{Header: userHeader, Rows: [][]string{record.row()}})
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"wallet/internal/cli"
+	"wallet/internal/domain"
+)
+
+var walletHeader = []string{"ID", "USER ID", "CURRENCY", "BALANCE", "POCKETED", "OVERDRAFT LIMIT", "STATUS", "PRODUCT", "VERSION", "UPDATED AT"}
+
+func walletRow(w *domain.Wallet) []string {
+	return []string{
+		w.ID, w.UserID, w.Currency, formatAmount(w.Balance), formatAmount(w.Pocketed), formatAmount(w.OverdraftLimit),
+		string(w.Status), w.Product, fmt.Sprint(w.Version), formatTime(w.UpdatedAt),
+	}
+}
+
+var movementHeader = []string{"ID", "WALLET ID", "TYPE", "AMOUNT", "BALANCE AFTER", "COUNTERPARTY", "REFERENCE", "ACTOR", "CREATED AT"}
+
+func movementRow(m *domain.Movement) []string {
+	counterparty := ""
+	if m.CounterpartyWalletID != nil {
+		counterparty = *m.CounterpartyWalletID
+	}
+	return []string{
+		m.ID, m.WalletID, string(m.Type), formatAmount(m.Amount), formatAmount(m.BalanceAfter),
+		counterparty, m.Reference, m.Actor, formatTime(m.CreatedAt),
+	}
+}
+
+func runWallet(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: walletctl wallet show|movements|credit|debit|freeze|unfreeze|product|merchant <id> [flags]")
+	}
+
+	switch args[0] {
+	case "show":
+		return showWallet(ctx, e, args[1:])
+	case "movements":
+		return listMovements(ctx, e, args[1:])
+	case "credit":
+		return adjustWallet(ctx, e, args[1:], 1)
+	case "debit":
+		return adjustWallet(ctx, e, args[1:], -1)
+	case "freeze":
+		return changeWalletStatus(ctx, e, args[1:], e.WalletUsecase.Freeze)
+	case "unfreeze":
+		return changeWalletStatus(ctx, e, args[1:], e.WalletUsecase.Unfreeze)
+	case "product":
+		return setWalletProduct(ctx, e, args[1:])
+	case "merchant":
+		return setWalletMerchant(ctx, e, args[1:])
+	default:
+		return fmt.Errorf("unknown wallet command %q", args[0])
+	}
+}
+
+func showWallet(ctx context.Context, e *env, args []string) error {
+	id, err := parseArgs(newFlagSet("wallet show"), args)
+	if err != nil {
+		return err
+	}
+	return printWallet(ctx, e, id)
+}
+
+func printWallet(ctx context.Context, e *env, id string) error {
+	wallet, err := e.WalletUsecase.GetWallet(ctx, id)
+	if err != nil {
+		return err
+	}
+	return cli.Print(e.out, e.format, wallet, cli.Table{Header: walletHeader, Rows: [][]string{walletRow(wallet)}})
+}
+
+func listMovements(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("wallet movements")
+	from, to := timeRange(fs)
+	id, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	start, end, err := parseTimeRange(*from, *to)
+	if err != nil {
+		return err
+	}
+
+	movements, err := e.WalletUsecase.ListMovements(ctx, id, start, end)
+	if err != nil {
+		return err
+	}
+	table := cli.Table{Header: movementHeader}
+	for i := range movements {
+		table.Rows = append(table.Rows, movementRow(&movements[i]))
+	}
+	return cli.Print(e.out, e.format, movements, table)
+}
+
+// adjustWallet requests to credit (sign 1) or debit (sign -1) a wallet; the
+// adjustment applies once another operator approves it (see adjustment.go).
+func adjustWallet(ctx context.Context, e *env, args []string, sign float64) error {
+	fs := newFlagSet("wallet adjust")
+	amount := fs.Float64("amount", 0, "positive amount to credit or debit")
+	reason := fs.String("reason", "", "why the adjustment is made (recorded in the audit log)")
+	id, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *amount <= 0 {
+		return errors.New("--amount must be positive")
+	}
+
+	request, err := e.AdjustmentUsecase.Request(ctx, id, sign**amount, *reason)
+	if err != nil {
+		return err
+	}
+	return printAdjustments(e, []domain.AdjustmentRequest{*request})
+}
+
+func changeWalletStatus(ctx context.Context, e *env, args []string, change func(ctx context.Context, walletID, reason string) error) error {
+	fs := newFlagSet("wallet status")
+	reason := fs.String("reason", "", "why the status changes (recorded in the audit log)")
+	id, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := change(ctx, id, *reason); err != nil {
+		return err
+	}
+	return printWallet(ctx, e, id)
+}
+
+func setWalletProduct(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("wallet product")
+	product := fs.String("product", "", "product the wallet moves to, e.g. current or savings")
+	reason := fs.String("reason", "", "why the product changes (recorded in the audit log)")
+	id, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := e.WalletUsecase.SetProduct(ctx, id, *product, *reason); err != nil {
+		return err
+	}
+	return printWallet(ctx, e, id)
+}
+
+func setWalletMerchant(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("wallet merchant")
+	name := fs.String("name", "", "name the merchant shows up with in insights")
+	category := fs.String("category", "", "category of the transfers with the merchant, e.g. groceries")
+	id, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	merchant, err := e.AnalyticsUsecase.SetMerchant(ctx, id, *name, *category)
+	if err != nil {
+		return err
+	}
+	return cli.Print(e.out, e.format, merchant, cli.Table{
+		Header: []string{"WALLET ID", "NAME", "CATEGORY", "UPDATED AT"},
+		Rows:   [][]string{{merchant.WalletID, merchant.Name, merchant.Category, formatTime(merchant.UpdatedAt)}},
+	})
+}
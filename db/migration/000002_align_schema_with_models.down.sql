@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS "idx_wallets_user_id";
+
+ALTER TABLE "wallets"
+    ALTER COLUMN "balance" TYPE decimal,
+    ALTER COLUMN "currency" DROP DEFAULT,
+    ALTER COLUMN "currency" TYPE varchar;
+
+ALTER TABLE "users"
+    ALTER COLUMN "dni" TYPE varchar,
+    ALTER COLUMN "name" TYPE varchar,
+    ALTER COLUMN "username" TYPE varchar;
@@ -0,0 +1,12 @@
+-- Bring the tables in line with the GORM models in internal/domain.
+ALTER TABLE "users"
+    ALTER COLUMN "username" TYPE varchar(255),
+    ALTER COLUMN "name" TYPE varchar(255),
+    ALTER COLUMN "dni" TYPE varchar(255);
+
+ALTER TABLE "wallets"
+    ALTER COLUMN "currency" TYPE varchar(3),
+    ALTER COLUMN "currency" SET DEFAULT 'USD',
+    ALTER COLUMN "balance" TYPE decimal(15,2);
+
+CREATE INDEX IF NOT EXISTS "idx_wallets_user_id" ON "wallets" ("user_id");
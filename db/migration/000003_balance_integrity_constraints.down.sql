@@ -0,0 +1,9 @@
+ALTER TABLE "wallets"
+    DROP CONSTRAINT IF EXISTS "fk_wallets_currencies",
+    DROP CONSTRAINT IF EXISTS "wallets_user_id_currency_key",
+    DROP CONSTRAINT IF EXISTS "wallets_balance_within_overdraft",
+    DROP CONSTRAINT IF EXISTS "wallets_overdraft_limit_non_negative";
+
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "overdraft_limit";
+
+DROP TABLE IF EXISTS "currencies";
@@ -0,0 +1,25 @@
+CREATE TABLE "currencies" (
+    "code" varchar(3) PRIMARY KEY,
+    "name" varchar(255) NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+INSERT INTO "currencies" ("code", "name") VALUES
+    ('USD', 'US Dollar'),
+    ('EUR', 'Euro'),
+    ('GBP', 'Pound Sterling'),
+    ('COP', 'Colombian Peso'),
+    ('MXN', 'Mexican Peso');
+
+-- Keep existing wallets valid even if they use a currency not seeded above.
+INSERT INTO "currencies" ("code", "name")
+SELECT DISTINCT "currency", "currency" FROM "wallets"
+ON CONFLICT ("code") DO NOTHING;
+
+ALTER TABLE "wallets" ADD COLUMN "overdraft_limit" decimal(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE "wallets"
+    ADD CONSTRAINT "wallets_overdraft_limit_non_negative" CHECK ("overdraft_limit" >= 0),
+    ADD CONSTRAINT "wallets_balance_within_overdraft" CHECK ("balance" >= -"overdraft_limit"),
+    ADD CONSTRAINT "wallets_user_id_currency_key" UNIQUE ("user_id", "currency"),
+    ADD CONSTRAINT "fk_wallets_currencies" FOREIGN KEY ("currency") REFERENCES "currencies"("code");
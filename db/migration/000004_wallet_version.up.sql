@@ -0,0 +1,2 @@
+-- Incremented on every update; used for optimistic concurrency control.
+ALTER TABLE "wallets" ADD COLUMN "version" bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "audit_entries";
+DROP TABLE IF EXISTS "movements";
+
+ALTER TABLE "wallets" DROP CONSTRAINT IF EXISTS "wallets_status_valid";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "status";
@@ -0,0 +1,30 @@
+ALTER TABLE "wallets" ADD COLUMN "status" varchar(16) NOT NULL DEFAULT 'active';
+ALTER TABLE "wallets" ADD CONSTRAINT "wallets_status_valid" CHECK ("status" IN ('active', 'frozen'));
+
+-- Ledger: one row per balance change, written in the same transaction as the change.
+CREATE TABLE "movements" (
+    "id" uuid PRIMARY KEY,
+    "wallet_id" uuid NOT NULL REFERENCES "wallets"("id"),
+    "type" varchar(32) NOT NULL,
+    "amount" decimal(15,2) NOT NULL,
+    "balance_after" decimal(15,2) NOT NULL,
+    "counterparty_wallet_id" uuid,
+    "reference" varchar(255) NOT NULL DEFAULT '',
+    "actor" varchar(255) NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "idx_movements_wallet_id_created_at" ON "movements" ("wallet_id", "created_at");
+
+CREATE TABLE "audit_entries" (
+    "id" uuid PRIMARY KEY,
+    "actor" varchar(255) NOT NULL,
+    "action" varchar(64) NOT NULL,
+    "entity_type" varchar(32) NOT NULL,
+    "entity_id" varchar(64) NOT NULL,
+    "reason" text NOT NULL DEFAULT '',
+    "details" jsonb NOT NULL DEFAULT '{}',
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "idx_audit_entries_entity_id" ON "audit_entries" ("entity_id");
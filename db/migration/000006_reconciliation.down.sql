@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "reconciliation_discrepancies";
+DROP TABLE IF EXISTS "reconciliation_runs";
+DELETE FROM "movements" WHERE "type" = 'opening_balance';
@@ -0,0 +1,43 @@
+-- Wallets created before the ledger have a balance no movement accounts for.
+-- Record it as an opening balance: the balance before the first movement, or
+-- the current balance when there is none.
+INSERT INTO "movements" ("id", "wallet_id", "type", "amount", "balance_after", "reference", "actor", "created_at")
+SELECT gen_random_uuid(), "id", 'opening_balance', "opening", "opening", 'balance before the ledger', 'system', "created_at"
+FROM (
+    SELECT w."id", w."created_at", COALESCE(
+        (SELECT m."balance_after" - m."amount" FROM "movements" m
+         WHERE m."wallet_id" = w."id" ORDER BY m."created_at", m."id" LIMIT 1),
+        w."balance"
+    ) AS "opening"
+    FROM "wallets" w
+) AS openings
+WHERE "opening" <> 0;
+
+CREATE TABLE "reconciliation_runs" (
+    "id" uuid PRIMARY KEY,
+    "trigger" varchar(16) NOT NULL,
+    "scheduled_for" date,
+    "status" varchar(16) NOT NULL,
+    "actor" varchar(255) NOT NULL,
+    "wallets_checked" integer NOT NULL DEFAULT 0,
+    "discrepancies" integer NOT NULL DEFAULT 0,
+    "error" text NOT NULL DEFAULT '',
+    "started_at" timestamptz NOT NULL,
+    "finished_at" timestamptz
+);
+
+-- One scheduled run per day across every instance; manual runs have no day.
+CREATE UNIQUE INDEX "idx_reconciliation_runs_scheduled_for" ON "reconciliation_runs" ("scheduled_for");
+
+CREATE TABLE "reconciliation_discrepancies" (
+    "id" uuid PRIMARY KEY,
+    "run_id" uuid NOT NULL REFERENCES "reconciliation_runs"("id"),
+    "kind" varchar(32) NOT NULL,
+    "wallet_id" uuid,
+    "currency" varchar(3) NOT NULL,
+    "expected" decimal(20,2) NOT NULL,
+    "actual" decimal(20,2) NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "idx_reconciliation_discrepancies_run_id" ON "reconciliation_discrepancies" ("run_id");
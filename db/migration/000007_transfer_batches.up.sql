@@ -0,0 +1,31 @@
+CREATE TABLE "transfer_batches" (
+    "id" uuid PRIMARY KEY,
+    "mode" varchar(16) NOT NULL,
+    "status" varchar(32) NOT NULL,
+    "actor" varchar(255) NOT NULL,
+    "total" integer NOT NULL,
+    "succeeded" integer NOT NULL DEFAULT 0,
+    "failed" integer NOT NULL DEFAULT 0,
+    "claimed_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    "finished_at" timestamptz,
+    CONSTRAINT "transfer_batches_mode_valid" CHECK ("mode" IN ('atomic', 'best_effort'))
+);
+
+-- Workers look for the oldest batch to process.
+CREATE INDEX "idx_transfer_batches_status_created_at" ON "transfer_batches" ("status", "created_at");
+
+CREATE TABLE "transfer_batch_items" (
+    "id" uuid PRIMARY KEY,
+    "batch_id" uuid NOT NULL REFERENCES "transfer_batches"("id") ON DELETE CASCADE,
+    "position" integer NOT NULL,
+    "from_wallet_id" uuid NOT NULL,
+    "to_wallet_id" uuid NOT NULL,
+    "amount" decimal(15,2) NOT NULL,
+    "status" varchar(16) NOT NULL,
+    "error" text NOT NULL DEFAULT '',
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "idx_transfer_batch_items_batch_id_position" ON "transfer_batch_items" ("batch_id", "position");
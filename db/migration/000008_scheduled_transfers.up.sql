@@ -0,0 +1,36 @@
+CREATE TABLE "scheduled_transfers" (
+    "id" uuid PRIMARY KEY,
+    "from_wallet_id" uuid NOT NULL REFERENCES "wallets"("id"),
+    "to_wallet_id" uuid NOT NULL REFERENCES "wallets"("id"),
+    "amount" decimal(15,2) NOT NULL,
+    "recurrence" varchar(16) NOT NULL,
+    "day_of_month" integer NOT NULL DEFAULT 0,
+    "cron_expr" varchar(255) NOT NULL DEFAULT '',
+    "start_at" timestamptz NOT NULL,
+    "end_at" timestamptz,
+    "status" varchar(16) NOT NULL,
+    "next_run_at" timestamptz,
+    "last_run_at" timestamptz,
+    "actor" varchar(255) NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    CONSTRAINT "scheduled_transfers_amount_positive" CHECK ("amount" > 0),
+    CONSTRAINT "scheduled_transfers_recurrence_valid" CHECK ("recurrence" IN ('once', 'daily', 'weekly', 'monthly', 'cron')),
+    CONSTRAINT "scheduled_transfers_status_valid" CHECK ("status" IN ('active', 'paused', 'completed', 'cancelled'))
+);
+
+CREATE INDEX "idx_scheduled_transfers_from_wallet_id" ON "scheduled_transfers" ("from_wallet_id");
+-- The scheduler only ever looks for active schedules that are due.
+CREATE INDEX "idx_scheduled_transfers_due" ON "scheduled_transfers" ("next_run_at") WHERE "status" = 'active';
+
+CREATE TABLE "scheduled_transfer_executions" (
+    "id" uuid PRIMARY KEY,
+    "schedule_id" uuid NOT NULL REFERENCES "scheduled_transfers"("id"),
+    "scheduled_for" timestamptz NOT NULL,
+    "status" varchar(16) NOT NULL,
+    "amount" decimal(15,2) NOT NULL,
+    "error" text NOT NULL DEFAULT '',
+    "executed_at" timestamptz NOT NULL
+);
+
+CREATE UNIQUE INDEX "idx_scheduled_transfer_executions_occurrence" ON "scheduled_transfer_executions" ("schedule_id", "scheduled_for");
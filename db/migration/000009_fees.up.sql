@@ -0,0 +1,2 @@
+-- Tiers select the fees a user pays (see the fee schedule).
+ALTER TABLE "users" ADD COLUMN "tier" varchar(32) NOT NULL DEFAULT 'standard';
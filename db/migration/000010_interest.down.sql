@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "interest_accruals";
+ALTER TABLE "wallets" DROP COLUMN IF EXISTS "product";
+DROP TABLE IF EXISTS "wallet_products";
@@ -0,0 +1,31 @@
+CREATE TABLE "wallet_products" (
+    "code" varchar(32) PRIMARY KEY,
+    "name" varchar(255) NOT NULL,
+    "interest_rate" decimal(7,4) NOT NULL DEFAULT 0 CONSTRAINT "wallet_products_interest_rate_non_negative" CHECK ("interest_rate" >= 0),
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- Rates are annual, in percent. Change them with an UPDATE; accruals record
+-- the rate they were computed with.
+INSERT INTO "wallet_products" ("code", "name", "interest_rate") VALUES
+    ('current', 'Current account', 0),
+    ('savings', 'Savings account', 2.0000);
+
+ALTER TABLE "wallets" ADD COLUMN "product" varchar(32) NOT NULL DEFAULT 'current'
+    CONSTRAINT "fk_wallets_products" REFERENCES "wallet_products"("code");
+
+CREATE TABLE "interest_accruals" (
+    "id" uuid PRIMARY KEY,
+    "wallet_id" uuid NOT NULL REFERENCES "wallets"("id"),
+    "day" date NOT NULL,
+    "balance" decimal(15,2) NOT NULL,
+    "rate" decimal(7,4) NOT NULL,
+    "amount" decimal(20,8) NOT NULL,
+    "movement_id" uuid REFERENCES "movements"("id"),
+    "posted_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- A wallet accrues at most once a day, whoever runs the accrual and however often.
+CREATE UNIQUE INDEX "idx_interest_accruals_wallet_id_day" ON "interest_accruals" ("wallet_id", "day");
+CREATE INDEX "idx_interest_accruals_pending" ON "interest_accruals" ("day", "wallet_id") WHERE "posted_at" IS NULL;
@@ -0,0 +1,21 @@
+CREATE TABLE "payment_requests" (
+    "id" uuid PRIMARY KEY,
+    "requester_wallet_id" uuid NOT NULL REFERENCES "wallets"("id"),
+    "payer_wallet_id" uuid NOT NULL REFERENCES "wallets"("id"),
+    "amount" decimal(15,2) NOT NULL CONSTRAINT "payment_requests_amount_positive" CHECK ("amount" > 0),
+    "note" varchar(255) NOT NULL DEFAULT '',
+    "status" varchar(16) NOT NULL
+        CONSTRAINT "payment_requests_status_valid" CHECK ("status" IN ('pending', 'paid', 'declined', 'expired')),
+    "actor" varchar(255) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "resolved_at" timestamptz,
+    "resolved_by" varchar(255) NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    CONSTRAINT "payment_requests_distinct_wallets" CHECK ("requester_wallet_id" <> "payer_wallet_id")
+);
+
+CREATE INDEX "idx_payment_requests_requester_wallet_id" ON "payment_requests" ("requester_wallet_id");
+CREATE INDEX "idx_payment_requests_payer_wallet_id" ON "payment_requests" ("payer_wallet_id");
+-- Finds the requests to expire without scanning resolved ones.
+CREATE INDEX "idx_payment_requests_pending_expires_at" ON "payment_requests" ("expires_at") WHERE "status" = 'pending';
@@ -0,0 +1,11 @@
+CREATE TABLE "user_aliases" (
+    "id" uuid PRIMARY KEY,
+    "user_id" uuid NOT NULL CONSTRAINT "fk_user_aliases_users" REFERENCES "users"("id"),
+    "kind" varchar(16) NOT NULL CONSTRAINT "user_aliases_kind_valid" CHECK ("kind" IN ('phone', 'email')),
+    "value" varchar(255) NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- A phone number or email address identifies a single user.
+CREATE UNIQUE INDEX "idx_user_aliases_kind_value" ON "user_aliases" ("kind", "value");
+CREATE INDEX "idx_user_aliases_user_id" ON "user_aliases" ("user_id");
@@ -0,0 +1,21 @@
+CREATE TABLE "wallet_members" (
+    "id" uuid PRIMARY KEY,
+    "wallet_id" uuid NOT NULL CONSTRAINT "fk_wallet_members_wallets" REFERENCES "wallets"("id"),
+    "user_id" uuid NOT NULL CONSTRAINT "fk_wallet_members_users" REFERENCES "users"("id"),
+    "role" varchar(16) NOT NULL CONSTRAINT "wallet_members_role_valid" CHECK ("role" IN ('owner', 'spender', 'viewer')),
+    "daily_limit" decimal(15,2) NOT NULL DEFAULT 0,
+    "status" varchar(16) NOT NULL CONSTRAINT "wallet_members_status_valid" CHECK ("status" IN ('invited', 'active')),
+    "invited_by" varchar(255) NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    -- Only spenders are limited, and they always are.
+    CONSTRAINT "wallet_members_limit_valid" CHECK (("role" = 'spender') = ("daily_limit" > 0))
+);
+
+CREATE UNIQUE INDEX "idx_wallet_members_wallet_user" ON "wallet_members" ("wallet_id", "user_id");
+CREATE INDEX "idx_wallet_members_user_id" ON "wallet_members" ("user_id");
+
+-- Existing wallets are owned by the user they were created for.
+INSERT INTO "wallet_members" ("id", "wallet_id", "user_id", "role", "status", "invited_by", "created_at", "updated_at")
+SELECT gen_random_uuid(), "id", "user_id", 'owner', 'active', 'system', "created_at", "created_at"
+FROM "wallets";
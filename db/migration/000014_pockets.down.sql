@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "pocket_movements";
+DROP TABLE IF EXISTS "pockets";
+
+ALTER TABLE "wallets"
+    DROP CONSTRAINT IF EXISTS "wallets_pocketed_non_negative",
+    DROP CONSTRAINT IF EXISTS "wallets_balance_within_overdraft",
+    ADD CONSTRAINT "wallets_balance_within_overdraft" CHECK ("balance" >= -"overdraft_limit"),
+    DROP COLUMN IF EXISTS "pocketed";
@@ -0,0 +1,38 @@
+-- Money in pockets stays in the wallet balance but can't be spent, nor
+-- covered by the overdraft.
+ALTER TABLE "wallets" ADD COLUMN "pocketed" decimal(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE "wallets"
+    DROP CONSTRAINT "wallets_balance_within_overdraft",
+    ADD CONSTRAINT "wallets_balance_within_overdraft" CHECK ("balance" - "pocketed" >= -"overdraft_limit"),
+    ADD CONSTRAINT "wallets_pocketed_non_negative" CHECK ("pocketed" >= 0);
+
+CREATE TABLE "pockets" (
+    "id" uuid PRIMARY KEY,
+    "wallet_id" uuid NOT NULL CONSTRAINT "fk_pockets_wallets" REFERENCES "wallets"("id"),
+    "name" varchar(64) NOT NULL,
+    "target_amount" decimal(15,2) NOT NULL DEFAULT 0 CONSTRAINT "pockets_target_amount_non_negative" CHECK ("target_amount" >= 0),
+    "target_date" date,
+    "balance" decimal(15,2) NOT NULL DEFAULT 0 CONSTRAINT "pockets_balance_non_negative" CHECK ("balance" >= 0),
+    "status" varchar(16) NOT NULL DEFAULT 'open' CONSTRAINT "pockets_status_valid" CHECK ("status" IN ('open', 'closed')),
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    -- Closed pockets are empty.
+    CONSTRAINT "pockets_closed_empty" CHECK ("status" = 'open' OR "balance" = 0)
+);
+
+CREATE INDEX "idx_pockets_wallet_id" ON "pockets" ("wallet_id");
+-- The name of a closed pocket can be reused.
+CREATE UNIQUE INDEX "idx_pockets_wallet_id_name" ON "pockets" ("wallet_id", "name") WHERE "status" = 'open';
+
+CREATE TABLE "pocket_movements" (
+    "id" uuid PRIMARY KEY,
+    "pocket_id" uuid NOT NULL REFERENCES "pockets"("id"),
+    "wallet_id" uuid NOT NULL REFERENCES "wallets"("id"),
+    "amount" decimal(15,2) NOT NULL,
+    "balance_after" decimal(15,2) NOT NULL,
+    "actor" varchar(255) NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "idx_pocket_movements_pocket_id" ON "pocket_movements" ("pocket_id");
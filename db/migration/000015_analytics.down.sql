@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "analytics_rollups";
+DROP TABLE IF EXISTS "movement_categories";
+DROP TABLE IF EXISTS "merchants";
+DROP TABLE IF EXISTS "movement_events";
@@ -0,0 +1,40 @@
+-- Every movement saved from now on is queued here until the analytics count it.
+CREATE TABLE "movement_events" (
+    "movement_id" uuid PRIMARY KEY REFERENCES "movements"("id"),
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "idx_movement_events_created_at" ON "movement_events" ("created_at");
+
+-- The existing ledger is counted by the first runs.
+INSERT INTO "movement_events" ("movement_id", "created_at")
+SELECT "id", "created_at" FROM "movements";
+
+CREATE TABLE "merchants" (
+    "wallet_id" uuid PRIMARY KEY CONSTRAINT "fk_merchants_wallets" REFERENCES "wallets"("id"),
+    "name" varchar(255) NOT NULL,
+    "category" varchar(32) NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "movement_categories" (
+    "movement_id" uuid PRIMARY KEY REFERENCES "movements"("id"),
+    "wallet_id" uuid NOT NULL REFERENCES "wallets"("id"),
+    "category" varchar(32) NOT NULL,
+    "source" varchar(16) NOT NULL CONSTRAINT "movement_categories_source_valid" CHECK ("source" IN ('user', 'merchant', 'default')),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "idx_movement_categories_wallet_id" ON "movement_categories" ("wallet_id");
+
+CREATE TABLE "analytics_rollups" (
+    "wallet_id" uuid NOT NULL REFERENCES "wallets"("id"),
+    "month" date NOT NULL,
+    "dimension" varchar(16) NOT NULL CONSTRAINT "analytics_rollups_dimension_valid" CHECK ("dimension" IN ('total', 'counterparty', 'category')),
+    "key" varchar(64) NOT NULL,
+    "inflow" decimal(15,2) NOT NULL DEFAULT 0,
+    "outflow" decimal(15,2) NOT NULL DEFAULT 0,
+    "count" bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY ("wallet_id", "month", "dimension", "key")
+);
@@ -0,0 +1,19 @@
+CREATE TABLE "transfer_reviews" (
+    "id" uuid PRIMARY KEY,
+    "from_wallet_id" uuid NOT NULL REFERENCES "wallets"("id"),
+    "to_wallet_id" uuid NOT NULL REFERENCES "wallets"("id"),
+    "amount" decimal(15,2) NOT NULL CONSTRAINT "transfer_reviews_amount_positive" CHECK ("amount" > 0),
+    "score" integer NOT NULL,
+    "reasons" text NOT NULL,
+    "status" varchar(16) NOT NULL
+        CONSTRAINT "transfer_reviews_status_valid" CHECK ("status" IN ('pending', 'approved', 'rejected', 'failed')),
+    "actor" varchar(255) NOT NULL,
+    "reviewed_by" varchar(255) NOT NULL DEFAULT '',
+    "reviewed_at" timestamptz,
+    "note" text NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "idx_transfer_reviews_from_wallet_id" ON "transfer_reviews" ("from_wallet_id");
+CREATE INDEX "idx_transfer_reviews_status" ON "transfer_reviews" ("status");
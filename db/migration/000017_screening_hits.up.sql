@@ -0,0 +1,21 @@
+CREATE TABLE "screening_hits" (
+    "id" uuid PRIMARY KEY,
+    "user_id" uuid NOT NULL CONSTRAINT "fk_screening_hits_users" REFERENCES "users"("id"),
+    "screened_name" varchar(255) NOT NULL,
+    "entry_id" varchar(64) NOT NULL,
+    "entry_name" text NOT NULL,
+    "programs" text NOT NULL DEFAULT '',
+    "score" decimal(5,4) NOT NULL CONSTRAINT "screening_hits_score_valid" CHECK ("score" > 0 AND "score" <= 1),
+    "source" varchar(16) NOT NULL CONSTRAINT "screening_hits_source_valid" CHECK ("source" IN ('user_creation', 'transfer')),
+    "status" varchar(16) NOT NULL
+        CONSTRAINT "screening_hits_status_valid" CHECK ("status" IN ('pending', 'confirmed', 'cleared')),
+    "reviewed_by" varchar(255) NOT NULL DEFAULT '',
+    "reviewed_at" timestamptz,
+    "note" text NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- A user is hit at most once per list entry, so cleared hits aren't raised again.
+CREATE UNIQUE INDEX "idx_screening_hits_user_entry" ON "screening_hits" ("user_id", "entry_id");
+CREATE INDEX "idx_screening_hits_status" ON "screening_hits" ("status");
@@ -0,0 +1,20 @@
+CREATE TABLE "adjustment_requests" (
+    "id" uuid PRIMARY KEY,
+    "wallet_id" uuid NOT NULL CONSTRAINT "fk_adjustment_requests_wallets" REFERENCES "wallets"("id"),
+    "amount" decimal(15,2) NOT NULL CONSTRAINT "adjustment_requests_amount_non_zero" CHECK ("amount" <> 0),
+    "reason" text NOT NULL,
+    "status" varchar(16) NOT NULL
+        CONSTRAINT "adjustment_requests_status_valid" CHECK ("status" IN ('pending', 'approved', 'rejected', 'failed')),
+    "requested_by" varchar(255) NOT NULL,
+    "reviewed_by" varchar(255) NOT NULL DEFAULT '',
+    "reviewed_at" timestamptz,
+    "note" text NOT NULL DEFAULT '',
+    "movement_id" uuid REFERENCES "movements"("id"),
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    -- Four eyes: whoever requested an adjustment can't decide on it.
+    CONSTRAINT "adjustment_requests_reviewer_not_requester" CHECK ("reviewed_by" <> "requested_by")
+);
+
+CREATE INDEX "idx_adjustment_requests_wallet_id" ON "adjustment_requests" ("wallet_id");
+CREATE INDEX "idx_adjustment_requests_status" ON "adjustment_requests" ("status");
@@ -0,0 +1 @@
+ALTER TABLE "transfer_batches" DROP COLUMN IF EXISTS "from_wallet_id";
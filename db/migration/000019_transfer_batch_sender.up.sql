@@ -0,0 +1,11 @@
+-- Every transfer of a batch now leaves the same wallet, the one the submitter
+-- was authorized to spend from. Batches submitted before took a sender per
+-- transfer; they are attributed to the sender of their first one.
+ALTER TABLE "transfer_batches" ADD COLUMN "from_wallet_id" uuid;
+
+UPDATE "transfer_batches" b
+SET "from_wallet_id" = i."from_wallet_id"
+FROM "transfer_batch_items" i
+WHERE i."batch_id" = b."id" AND i."position" = 1;
+
+ALTER TABLE "transfer_batches" ALTER COLUMN "from_wallet_id" SET NOT NULL;
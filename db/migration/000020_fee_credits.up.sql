@@ -0,0 +1,8 @@
+-- Fees charged from now on wait here, as the payer's fee movement, until they
+-- are credited to the house wallet in a batch.
+CREATE TABLE "fee_credits" (
+    "movement_id" uuid PRIMARY KEY REFERENCES "movements"("id"),
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "idx_fee_credits_created_at" ON "fee_credits" ("created_at");
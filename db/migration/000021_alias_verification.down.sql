@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS "idx_user_aliases_user_id_kind_value";
+DROP INDEX IF EXISTS "idx_user_aliases_kind_value";
+
+-- Each value goes back to one user: the one who verified it, or else the
+-- first to register it.
+DELETE FROM "user_aliases" a
+WHERE a."verified_at" IS NULL AND EXISTS (
+    SELECT 1 FROM "user_aliases" b
+    WHERE b."kind" = a."kind" AND b."value" = a."value" AND b."id" <> a."id"
+      AND (b."verified_at" IS NOT NULL OR (b."created_at", b."id") < (a."created_at", a."id"))
+);
+CREATE UNIQUE INDEX "idx_user_aliases_kind_value" ON "user_aliases" ("kind", "value");
+
+ALTER TABLE "user_aliases"
+    DROP COLUMN IF EXISTS "code_attempts",
+    DROP COLUMN IF EXISTS "code_expires_at",
+    DROP COLUMN IF EXISTS "code_hash",
+    DROP COLUMN IF EXISTS "verified_at";
@@ -0,0 +1,14 @@
+-- An alias finds its user once verified with a code sent to it. Aliases
+-- registered before were never verified: they stop finding their user until
+-- registered and verified again.
+ALTER TABLE "user_aliases"
+    ADD COLUMN "verified_at" timestamptz,
+    ADD COLUMN "code_hash" varchar(64) NOT NULL DEFAULT '',
+    ADD COLUMN "code_expires_at" timestamptz,
+    ADD COLUMN "code_attempts" integer NOT NULL DEFAULT 0;
+
+-- Registering a value doesn't reserve it: only a verified alias does, so
+-- nobody can hold on to someone else's phone number or email address.
+DROP INDEX "idx_user_aliases_kind_value";
+CREATE UNIQUE INDEX "idx_user_aliases_kind_value" ON "user_aliases" ("kind", "value") WHERE "verified_at" IS NOT NULL;
+CREATE UNIQUE INDEX "idx_user_aliases_user_id_kind_value" ON "user_aliases" ("user_id", "kind", "value");
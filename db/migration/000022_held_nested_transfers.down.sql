@@ -0,0 +1,16 @@
+-- What is still held didn't happen: held items and executions count as
+-- failed, held payment requests as declined.
+UPDATE "transfer_batch_items" SET "status" = 'failed', "error" = 'held for review' WHERE "status" = 'held';
+UPDATE "scheduled_transfer_executions" SET "status" = 'failed', "error" = 'held for review' WHERE "status" = 'held';
+UPDATE "payment_requests" SET "status" = 'declined' WHERE "status" = 'held';
+UPDATE "transfer_batches" SET "failed" = "failed" + "held" WHERE "held" > 0;
+
+DROP INDEX IF EXISTS "idx_payment_requests_review_id";
+ALTER TABLE "payment_requests" DROP CONSTRAINT "payment_requests_status_valid";
+ALTER TABLE "payment_requests" ADD CONSTRAINT "payment_requests_status_valid"
+    CHECK ("status" IN ('pending', 'paid', 'declined', 'expired'));
+ALTER TABLE "payment_requests" DROP COLUMN IF EXISTS "review_id";
+
+ALTER TABLE "scheduled_transfer_executions" DROP COLUMN IF EXISTS "review_id";
+ALTER TABLE "transfer_batch_items" DROP COLUMN IF EXISTS "review_id";
+ALTER TABLE "transfer_batches" DROP COLUMN IF EXISTS "held";
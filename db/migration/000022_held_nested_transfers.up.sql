@@ -0,0 +1,12 @@
+-- Transfers made by batches, schedules and payment requests that the risk
+-- rules hold for review are recorded as held, with the review deciding them.
+ALTER TABLE "transfer_batches" ADD COLUMN "held" integer NOT NULL DEFAULT 0;
+ALTER TABLE "transfer_batch_items" ADD COLUMN "review_id" uuid REFERENCES "transfer_reviews"("id");
+ALTER TABLE "scheduled_transfer_executions" ADD COLUMN "review_id" uuid REFERENCES "transfer_reviews"("id");
+
+ALTER TABLE "payment_requests" ADD COLUMN "review_id" uuid REFERENCES "transfer_reviews"("id");
+ALTER TABLE "payment_requests" DROP CONSTRAINT "payment_requests_status_valid";
+ALTER TABLE "payment_requests" ADD CONSTRAINT "payment_requests_status_valid"
+    CHECK ("status" IN ('pending', 'held', 'paid', 'declined', 'expired'));
+-- Approving or rejecting a review settles the request held on it.
+CREATE INDEX "idx_payment_requests_review_id" ON "payment_requests" ("review_id");
@@ -0,0 +1,2 @@
+-- Nothing to undo: the foreign key belongs to 000001 on databases created by
+-- the migrations, and adopted databases keep it.
@@ -0,0 +1,9 @@
+-- Databases created by GORM's AutoMigrate before the migrations existed are
+-- adopted at version 1 (see Migrator), but AutoMigrate never created the
+-- foreign key from wallets to users.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_wallets_users' AND conrelid = '"wallets"'::regclass) THEN
+        ALTER TABLE "wallets" ADD CONSTRAINT "fk_wallets_users" FOREIGN KEY ("user_id") REFERENCES "users"("id");
+    END IF;
+END $$;
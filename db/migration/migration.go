@@ -0,0 +1,11 @@
+// Package migration embeds the versioned SQL migrations so the binaries can
+// apply them without shipping the files separately.
+package migration
+
+import "embed"
+
+// FS holds the migrations, named <version>_<name>.up.sql and
+// <version>_<name>.down.sql as expected by golang-migrate.
+//
+//go:embed *.sql
+var FS embed.FS
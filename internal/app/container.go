@@ -0,0 +1,224 @@
+// Package app wires the infrastructure, repositories and use cases shared by
+// the binaries (the HTTP API and walletctl), so both apply the same business
+// rules, caching and auditing.
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"wallet/db/migration"
+	"wallet/internal/config"
+	"wallet/internal/domain"
+	"wallet/internal/infrastructure/cache"
+	"wallet/internal/infrastructure/notify"
+	postgresRepo "wallet/internal/infrastructure/postgres"
+	"wallet/internal/infrastructure/redis"
+	"wallet/internal/usecase"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// The cache is bypassed after this many consecutive failures and probed again
+// once the open timeout has elapsed.
+const (
+	cacheBreakerFailureThreshold = 5
+	cacheBreakerOpenTimeout      = 30 * time.Second
+)
+
+// Container holds the wired dependencies.
+type Container struct {
+	DB       *gorm.DB
+	Replica  *gorm.DB // nil without DB_REPLICA_SOURCE
+	Cluster  *postgresRepo.Cluster
+	Cache    domain.CacheRepository // nil without REDIS_ADDR
+	Migrator *postgresRepo.Migrator
+	Notifier domain.Notifier
+	// RiskRules is nil without RISK_RULES_FILE. The API watches it for changes.
+	RiskRules *config.RiskRulesFile
+	// Sanctions is nil without SANCTIONS_LIST_FILE. The API watches it for changes.
+	Sanctions *config.SanctionsListFile
+
+	UserRepo     domain.UserRepository
+	WalletRepo   domain.WalletRepository
+	MovementRepo domain.MovementRepository
+	AuditRepo    domain.AuditRepository
+	TxnRepo      domain.TxnRepository
+
+	ReconciliationRepo domain.ReconciliationRepository
+	TransferBatchRepo  domain.TransferBatchRepository
+	ScheduleRepo       domain.ScheduledTransferRepository
+	InterestRepo       domain.InterestRepository
+	PaymentRequestRepo domain.PaymentRequestRepository
+	AliasRepo          domain.AliasRepository
+	MemberRepo         domain.WalletMemberRepository
+	PocketRepo         domain.PocketRepository
+	AnalyticsRepo      domain.AnalyticsRepository
+	ReviewRepo         domain.TransferReviewRepository
+	ScreeningRepo      domain.ScreeningRepository
+	AdjustmentRepo     domain.AdjustmentRequestRepository
+	FeeCreditRepo      domain.FeeCreditRepository
+
+	UserUsecase           usecase.UserUsecase
+	WalletUsecase         usecase.WalletUsecase
+	ReconciliationUsecase usecase.ReconciliationUsecase
+	StatementUsecase      usecase.StatementUsecase
+	TransferBatchUsecase  usecase.TransferBatchUsecase
+	ScheduleUsecase       usecase.ScheduledTransferUsecase
+	InterestUsecase       usecase.InterestUsecase
+	PaymentRequestUsecase usecase.PaymentRequestUsecase
+	RecipientUsecase      usecase.RecipientUsecase
+	MemberUsecase         usecase.WalletMemberUsecase
+	PocketUsecase         usecase.PocketUsecase
+	AnalyticsUsecase      usecase.AnalyticsUsecase
+	ReviewUsecase         usecase.TransferReviewUsecase
+	ScreeningUsecase      usecase.ScreeningUsecase
+	AdjustmentUsecase     usecase.AdjustmentUsecase
+	FeeUsecase            usecase.FeeUsecase
+}
+
+// New connects to the databases and the cache and builds the use cases.
+func New(cfg *config.Config, logger *slog.Logger) (*Container, error) {
+	c := &Container{}
+
+	fees, err := config.LoadFeeSchedule(cfg.FeeScheduleFile)
+	if err != nil {
+		return nil, err
+	}
+	dayCount, err := domain.ParseDayCount(cfg.InterestDayCount)
+	if err != nil {
+		return nil, err
+	}
+	var risk domain.RiskRulesSource
+	if cfg.RiskRulesFile != "" {
+		if c.RiskRules, err = config.NewRiskRulesFile(cfg.RiskRulesFile, logger); err != nil {
+			return nil, err
+		}
+		risk = c.RiskRules
+	}
+	if err := config.ValidateScreeningThresholds(cfg.SanctionsReviewThreshold, cfg.SanctionsBlockThreshold); err != nil {
+		return nil, err
+	}
+	var sanctions domain.SanctionsListSource
+	if cfg.SanctionsListFile != "" {
+		if c.Sanctions, err = config.NewSanctionsListFile(cfg.SanctionsListFile, logger); err != nil {
+			return nil, err
+		}
+		sanctions = c.Sanctions
+	}
+
+	db, err := gorm.Open(postgres.Open(cfg.DBSource), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	c.DB = db
+
+	// Schema changes are versioned SQL files embedded in the binary (db/migration).
+	c.Migrator, err = postgresRepo.NewMigrator(db, migration.FS, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// The replica is optional and may be down at startup: skip the initial ping
+	// and let the cluster's monitor decide when reads can use it.
+	if cfg.DBReplicaSource != "" {
+		c.Replica, err = gorm.Open(postgres.Open(cfg.DBReplicaSource), &gorm.Config{DisableAutomaticPing: true})
+		if err != nil {
+			logger.Warn("Cannot configure read replica, reading from the primary", "error", err)
+			c.Replica = nil
+		}
+	}
+	c.Cluster = postgresRepo.NewCluster(db, c.Replica, cfg.DBReplicaMaxLag, logger)
+
+	postgresUserRepo := postgresRepo.NewPostgresUserRepository(c.Cluster)
+	c.UserRepo = postgresUserRepo
+	c.WalletRepo = postgresRepo.NewPostgresWalletRepository(db)
+	c.MovementRepo = postgresRepo.NewPostgresMovementRepository(c.Cluster)
+	c.AuditRepo = postgresRepo.NewPostgresAuditRepository(db)
+	c.TxnRepo = postgresRepo.NewPostgresTxnRepository(db)
+	c.ReconciliationRepo = postgresRepo.NewPostgresReconciliationRepository(c.Cluster)
+	c.TransferBatchRepo = postgresRepo.NewPostgresTransferBatchRepository(db)
+	c.ScheduleRepo = postgresRepo.NewPostgresScheduledTransferRepository(db)
+	c.InterestRepo = postgresRepo.NewPostgresInterestRepository(db)
+	c.PaymentRequestRepo = postgresRepo.NewPostgresPaymentRequestRepository(db)
+	c.AliasRepo = postgresRepo.NewPostgresAliasRepository(db)
+	c.MemberRepo = postgresRepo.NewPostgresWalletMemberRepository(db)
+	c.PocketRepo = postgresRepo.NewPostgresPocketRepository(db)
+	c.AnalyticsRepo = postgresRepo.NewPostgresAnalyticsRepository(db)
+	c.ReviewRepo = postgresRepo.NewPostgresTransferReviewRepository(db)
+	c.ScreeningRepo = postgresRepo.NewPostgresScreeningRepository(db)
+	c.AdjustmentRepo = postgresRepo.NewPostgresAdjustmentRequestRepository(db)
+	c.FeeCreditRepo = postgresRepo.NewPostgresFeeCreditRepository(db)
+
+	// Redis is optional: without REDIS_ADDR we run uncached, and if it goes down
+	// the circuit breaker bypasses it until it recovers.
+	if cfg.RedisAddr != "" {
+		redisClient := redis.NewClient(cfg.RedisAddr)
+		c.Cache = redis.NewCircuitBreakerCacheRepository(
+			redis.NewRedisCacheRepository(redisClient),
+			logger,
+			cacheBreakerFailureThreshold,
+			cacheBreakerOpenTimeout,
+		)
+		if err := c.Cache.Ping(context.Background()); err != nil {
+			logger.Warn("Redis is unavailable, starting without cache", "error", err)
+		}
+
+		var locker domain.Locker
+		if cfg.CacheDistributedLock {
+			locker = redis.NewCircuitBreakerLocker(c.Cache, redis.NewRedisLocker(redisClient))
+		}
+
+		// Wrap the postgres repos with the cache decorators
+		c.UserRepo = cache.NewCachedUserRepository(c.Cache, postgresUserRepo, cache.Options{
+			TTL:         cfg.UserCacheTTL,
+			NegativeTTL: cfg.UserCacheNegativeTTL,
+			StaleTTL:    cfg.UserCacheStaleTTL,
+			Locker:      locker,
+		})
+		// Wallets are cached for display only; see cachedWalletRepository.
+		c.WalletRepo = cache.NewCachedWalletRepository(c.Cache, c.WalletRepo, cache.Options{
+			TTL:    cfg.WalletCacheTTL,
+			Locker: locker,
+		})
+	} else {
+		logger.Warn("REDIS_ADDR is not set, caching is disabled")
+	}
+
+	if cfg.NotificationWebhookURL != "" {
+		c.Notifier = notify.NewWebhookNotifier(cfg.NotificationWebhookURL)
+	} else {
+		c.Notifier = notify.NewLogNotifier(logger)
+	}
+
+	c.ScreeningUsecase = usecase.NewScreeningUsecase(c.ScreeningRepo, c.AuditRepo, c.TxnRepo, sanctions,
+		cfg.SanctionsReviewThreshold, cfg.SanctionsBlockThreshold, logger)
+	c.UserUsecase = usecase.NewUserUsecase(c.UserRepo, c.WalletRepo, c.MemberRepo, c.AliasRepo, c.AuditRepo, c.TxnRepo, c.ScreeningUsecase,
+		c.Notifier, cfg.AliasVerificationTTL)
+	c.WalletUsecase = usecase.NewWalletUsecase(c.WalletRepo, c.UserRepo, c.MovementRepo, c.MemberRepo, c.ReviewRepo, c.FeeCreditRepo, c.AuditRepo, c.TxnRepo,
+		fees, risk, c.ScreeningUsecase, logger)
+	c.ReconciliationUsecase = usecase.NewReconciliationUsecase(c.ReconciliationRepo, cfg.ReconciliationBatchSize, logger)
+	c.StatementUsecase = usecase.NewStatementUsecase(c.WalletRepo, c.MovementRepo, c.MemberRepo)
+	c.TransferBatchUsecase = usecase.NewTransferBatchUsecase(c.TransferBatchRepo, c.WalletRepo, c.MemberRepo, c.WalletUsecase, c.TxnRepo,
+		cfg.TransferBatchMaxItems, cfg.TransferBatchLease, logger)
+	c.ScheduleUsecase = usecase.NewScheduledTransferUsecase(c.ScheduleRepo, c.WalletRepo, c.MemberRepo, c.MovementRepo, c.WalletUsecase, c.TxnRepo, c.Notifier, logger)
+	c.InterestUsecase = usecase.NewInterestUsecase(c.InterestRepo, c.WalletRepo, c.MemberRepo, c.MovementRepo, c.TxnRepo, dayCount, logger)
+	c.PaymentRequestUsecase = usecase.NewPaymentRequestUsecase(c.PaymentRequestRepo, c.WalletRepo, c.MemberRepo, c.WalletUsecase, c.TxnRepo, c.Notifier,
+		cfg.PaymentRequestTTL, logger)
+	c.RecipientUsecase = usecase.NewRecipientUsecase(c.UserRepo, c.WalletRepo, c.AliasRepo)
+	c.MemberUsecase = usecase.NewWalletMemberUsecase(c.MemberRepo, c.WalletRepo, c.AuditRepo, c.TxnRepo)
+	c.PocketUsecase = usecase.NewPocketUsecase(c.PocketRepo, c.WalletRepo, c.MemberRepo, c.TxnRepo, logger)
+	c.AnalyticsUsecase = usecase.NewAnalyticsUsecase(c.AnalyticsRepo, c.WalletRepo, c.MovementRepo, c.MemberRepo, c.AuditRepo, c.TxnRepo,
+		cfg.AnalyticsBatchSize, logger)
+	c.ReviewUsecase = usecase.NewTransferReviewUsecase(c.ReviewRepo, c.WalletUsecase, c.PaymentRequestUsecase, c.AuditRepo, c.TxnRepo)
+	c.AdjustmentUsecase = usecase.NewAdjustmentUsecase(c.AdjustmentRepo, c.WalletRepo, c.WalletUsecase, c.AuditRepo, c.TxnRepo)
+	c.FeeUsecase = usecase.NewFeeUsecase(c.FeeCreditRepo, c.WalletRepo, c.MovementRepo, c.TxnRepo, cfg.FeeCreditBatchSize, logger)
+
+	return c, nil
+}
+
+// Close releases the database connections.
+func (c *Container) Close() error {
+	return c.Cluster.Close()
+}
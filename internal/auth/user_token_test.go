@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const alice = "6f1c2a9e-3b4d-4e8a-9c1f-2d7b5e0a4c31"
+
+// A token authenticates its user until it expires, and only as signed with
+// the key.
+func TestUserTokens(t *testing.T) {
+	tokens := NewUserTokens([]byte("key"))
+	valid := tokens.Sign(alice, time.Now().Add(time.Hour))
+	claims, signature, _ := cutLast(valid, ".")
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"valid", valid, nil},
+		{"expired", tokens.Sign(alice, time.Now().Add(-time.Second)), ErrUserTokenExpired},
+		{"other key", NewUserTokens([]byte("other")).Sign(alice, time.Now().Add(time.Hour)), ErrInvalidUserToken},
+		{"other user", strings.Replace(valid, alice[:8], "00000000", 1), ErrInvalidUserToken},
+		{"extended", claims + "0." + signature, ErrInvalidUserToken},
+		{"unsigned", claims, ErrInvalidUserToken},
+		{"bad signature encoding", claims + ".!!", ErrInvalidUserToken},
+		{"not a user ID", tokens.Sign("alice", time.Now().Add(time.Hour)), ErrInvalidUserToken},
+		{"empty", "", ErrInvalidUserToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, err := tokens.Verify(tt.token)
+			if !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+				t.Fatalf("Verify = %v, want %v", err, tt.want)
+			}
+			if err == nil && userID != alice {
+				t.Errorf("Verify = %q, want %q", userID, alice)
+			}
+			if err != nil && userID != "" {
+				t.Errorf("Verify returned %q with its error", userID)
+			}
+		})
+	}
+}
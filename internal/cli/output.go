@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats of the operator commands.
+const (
+	FormatJSON  = "json"
+	FormatTable = "table"
+)
+
+// Table is the tabular rendering of a command's result.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Print writes value as indented JSON, or table as aligned columns.
+func Print(out io.Writer, format string, value interface{}, table Table) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	case FormatTable:
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(table.Header, "\t"))
+		for _, row := range table.Rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
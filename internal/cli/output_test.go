@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrint(t *testing.T) {
+	value := map[string]string{"id": "w1", "currency": "USD"}
+	table := Table{
+		Header: []string{"ID", "CURRENCY"},
+		Rows:   [][]string{{"w1", "USD"}, {"wallet-2", "EUR"}},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatJSON, "{\n  \"currency\": \"USD\",\n  \"id\": \"w1\"\n}\n"},
+		{FormatTable, "ID        CURRENCY\nw1        USD\nwallet-2  EUR\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Print(&out, tt.format, value, table); err != nil {
+				t.Fatalf("Print: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("Print wrote\n%q\nwant\n%q", out.String(), tt.want)
+			}
+		})
+	}
+
+	if err := Print(&bytes.Buffer{}, "yaml", value, table); err == nil {
+		t.Error("Print with an unknown format succeeded, want an error")
+	}
+}
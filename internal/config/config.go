@@ -1,18 +1,167 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/spf13/viper"
+)
 
 type Config struct {
 	ServerPort string `mapstructure:"SERVER_PORT"`
 	DBSource   string `mapstructure:"DB_SOURCE"`
-	RedisAddr  string `mapstructure:"REDIS_ADDR"`
-	SentryDSN  string `mapstructure:"SENTRY_DSN"`
+	// DBReplicaSource is the read replica. When empty every query goes to DBSource.
+	DBReplicaSource string `mapstructure:"DB_REPLICA_SOURCE"`
+	// DBReplicaMaxLag is the replication lag above which reads fall back to the primary.
+	DBReplicaMaxLag time.Duration `mapstructure:"DB_REPLICA_MAX_LAG"`
+	RedisAddr       string        `mapstructure:"REDIS_ADDR"`
+	SentryDSN       string        `mapstructure:"SENTRY_DSN"`
+
+	// MigrateOnStartup applies pending migrations before serving.
+	MigrateOnStartup bool `mapstructure:"MIGRATE_ON_STARTUP"`
+
+	// UserCacheTTL is how long a user is kept in the cache.
+	UserCacheTTL time.Duration `mapstructure:"USER_CACHE_TTL"`
+	// UserCacheNegativeTTL is how long a "user not found" result is cached.
+	UserCacheNegativeTTL time.Duration `mapstructure:"USER_CACHE_NEGATIVE_TTL"`
+	// UserCacheStaleTTL enables stale-while-revalidate: for this long after
+	// UserCacheTTL a user is still served while it is reloaded in the background.
+	UserCacheStaleTTL time.Duration `mapstructure:"USER_CACHE_STALE_TTL"`
+	// WalletCacheTTL is how long a wallet is cached for display. Balances used
+	// for money movements never come from the cache.
+	WalletCacheTTL time.Duration `mapstructure:"WALLET_CACHE_TTL"`
+	// CacheDistributedLock coalesces cache reloads across instances with a Redis lock.
+	CacheDistributedLock bool `mapstructure:"CACHE_DISTRIBUTED_LOCK"`
+
+	// ReconciliationEnabled runs the daily reconciliation in the API process.
+	ReconciliationEnabled bool `mapstructure:"RECONCILIATION_ENABLED"`
+	// ReconciliationTime is the UTC time of day (HH:MM) the reconciliation runs at.
+	ReconciliationTime string `mapstructure:"RECONCILIATION_TIME"`
+	// ReconciliationBatchSize is how many wallets the reconciliation reads per query.
+	ReconciliationBatchSize int `mapstructure:"RECONCILIATION_BATCH_SIZE"`
+
+	// OperatorTokens authenticates the operators calling the admin routes, as
+	// comma-separated name:sha256 pairs (see ParseOperatorTokens).
+	OperatorTokens string `mapstructure:"OPERATOR_TOKENS"`
+
+	// UserTokenKey is the HMAC key of the user tokens (see auth.UserTokens),
+	// shared with the service that issues them. Without it no user can call
+	// the user routes.
+	UserTokenKey string `mapstructure:"USER_TOKEN_KEY"`
+
+	// StatementSigningKey is the HMAC key statements are signed with. Every
+	// instance must share it for statements to verify anywhere.
+	StatementSigningKey string `mapstructure:"STATEMENT_SIGNING_KEY"`
+
+	// TransferBatchMaxItems is the most transfers a batch may hold.
+	TransferBatchMaxItems int `mapstructure:"TRANSFER_BATCH_MAX_ITEMS"`
+	// TransferBatchPollInterval is how often idle workers look for new batches.
+	TransferBatchPollInterval time.Duration `mapstructure:"TRANSFER_BATCH_POLL_INTERVAL"`
+	// TransferBatchLease is how long a worker may go without progress on a
+	// batch before another worker takes it over.
+	TransferBatchLease time.Duration `mapstructure:"TRANSFER_BATCH_LEASE"`
+
+	// ScheduledTransferPollInterval is how often due scheduled transfers are looked for.
+	ScheduledTransferPollInterval time.Duration `mapstructure:"SCHEDULED_TRANSFER_POLL_INTERVAL"`
+	// NotificationWebhookURL receives user notifications as JSON. When empty
+	// they are only logged.
+	NotificationWebhookURL string `mapstructure:"NOTIFICATION_WEBHOOK_URL"`
+
+	// FeeScheduleFile is the JSON fee schedule (see LoadFeeSchedule). When
+	// empty no fees are charged.
+	FeeScheduleFile string `mapstructure:"FEE_SCHEDULE_FILE"`
+	// FeeCreditInterval is how often the fees charged are credited to the house wallets.
+	FeeCreditInterval time.Duration `mapstructure:"FEE_CREDIT_INTERVAL"`
+	// FeeCreditBatchSize is how many fees are credited per transaction.
+	FeeCreditBatchSize int `mapstructure:"FEE_CREDIT_BATCH_SIZE"`
+
+	// InterestEnabled runs the daily interest accrual in the API process.
+	InterestEnabled bool `mapstructure:"INTEREST_ENABLED"`
+	// InterestTime is the UTC time of day (HH:MM) the previous day is accrued at.
+	InterestTime string `mapstructure:"INTEREST_TIME"`
+	// InterestDayCount is the day-count convention: ACT/365, ACT/360 or ACT/ACT.
+	InterestDayCount string `mapstructure:"INTEREST_DAY_COUNT"`
+
+	// PaymentRequestTTL is how long a payment request stays open unless it sets its own expiry.
+	PaymentRequestTTL time.Duration `mapstructure:"PAYMENT_REQUEST_TTL"`
+	// PaymentRequestExpiryInterval is how often expired payment requests are closed.
+	PaymentRequestExpiryInterval time.Duration `mapstructure:"PAYMENT_REQUEST_EXPIRY_INTERVAL"`
+
+	// AliasVerificationTTL is how long the code verifying a new alias stays valid.
+	AliasVerificationTTL time.Duration `mapstructure:"ALIAS_VERIFICATION_TTL"`
+
+	// AnalyticsPollInterval is how often new movements are counted in the spending rollups.
+	AnalyticsPollInterval time.Duration `mapstructure:"ANALYTICS_POLL_INTERVAL"`
+	// AnalyticsBatchSize is how many movements are counted per transaction.
+	AnalyticsBatchSize int `mapstructure:"ANALYTICS_BATCH_SIZE"`
+
+	// RiskRulesFile holds the JSON risk rules transfers are scored with (see
+	// domain.RiskRules). When empty transfers are not scored.
+	RiskRulesFile string `mapstructure:"RISK_RULES_FILE"`
+	// RiskRulesReloadInterval is how often the API checks the risk rules file for changes.
+	RiskRulesReloadInterval time.Duration `mapstructure:"RISK_RULES_RELOAD_INTERVAL"`
+
+	// SanctionsListFile is the sanctions list users are screened against, in
+	// OFAC's sdn.csv or sdn.xml format. When empty no one is screened, but the
+	// hits already confirmed still block their users.
+	SanctionsListFile string `mapstructure:"SANCTIONS_LIST_FILE"`
+	// SanctionsReloadInterval is how often the API checks the sanctions list file for changes.
+	SanctionsReloadInterval time.Duration `mapstructure:"SANCTIONS_RELOAD_INTERVAL"`
+	// SanctionsReviewThreshold is the name similarity, from 0 to 1, from which
+	// a match is held for an operator to confirm or clear.
+	SanctionsReviewThreshold float64 `mapstructure:"SANCTIONS_REVIEW_THRESHOLD"`
+	// SanctionsBlockThreshold is the name similarity from which a match is
+	// confirmed right away.
+	SanctionsBlockThreshold float64 `mapstructure:"SANCTIONS_BLOCK_THRESHOLD"`
 }
 
 func Load() (*Config, error) {
 	// Tell viper to look for environment variables
 	viper.AutomaticEnv()
 
+	// Unmarshal only sees keys viper already knows about, so every key gets a
+	// default, even an empty one, for its environment variable to be picked up.
+	viper.SetDefault("SERVER_PORT", "8080")
+	viper.SetDefault("DB_SOURCE", "")
+	viper.SetDefault("DB_REPLICA_SOURCE", "")
+	viper.SetDefault("DB_REPLICA_MAX_LAG", 5*time.Second)
+	viper.SetDefault("REDIS_ADDR", "")
+	viper.SetDefault("SENTRY_DSN", "")
+	viper.SetDefault("MIGRATE_ON_STARTUP", true)
+	viper.SetDefault("USER_CACHE_TTL", 5*time.Minute)
+	viper.SetDefault("USER_CACHE_NEGATIVE_TTL", 30*time.Second)
+	viper.SetDefault("USER_CACHE_STALE_TTL", time.Duration(0))
+	viper.SetDefault("WALLET_CACHE_TTL", 30*time.Second)
+	viper.SetDefault("CACHE_DISTRIBUTED_LOCK", false)
+	viper.SetDefault("RECONCILIATION_ENABLED", true)
+	viper.SetDefault("RECONCILIATION_TIME", "03:00")
+	viper.SetDefault("RECONCILIATION_BATCH_SIZE", 500)
+	viper.SetDefault("OPERATOR_TOKENS", "")
+	viper.SetDefault("USER_TOKEN_KEY", "")
+	viper.SetDefault("STATEMENT_SIGNING_KEY", "")
+	viper.SetDefault("TRANSFER_BATCH_MAX_ITEMS", 1000)
+	viper.SetDefault("TRANSFER_BATCH_POLL_INTERVAL", time.Second)
+	viper.SetDefault("TRANSFER_BATCH_LEASE", 5*time.Minute)
+	viper.SetDefault("SCHEDULED_TRANSFER_POLL_INTERVAL", 10*time.Second)
+	viper.SetDefault("NOTIFICATION_WEBHOOK_URL", "")
+	viper.SetDefault("FEE_SCHEDULE_FILE", "")
+	viper.SetDefault("FEE_CREDIT_INTERVAL", time.Second)
+	viper.SetDefault("FEE_CREDIT_BATCH_SIZE", 500)
+	viper.SetDefault("INTEREST_ENABLED", true)
+	viper.SetDefault("INTEREST_TIME", "00:30")
+	viper.SetDefault("INTEREST_DAY_COUNT", string(domain.DayCountAct365))
+	viper.SetDefault("PAYMENT_REQUEST_TTL", 7*24*time.Hour)
+	viper.SetDefault("PAYMENT_REQUEST_EXPIRY_INTERVAL", time.Minute)
+	viper.SetDefault("ALIAS_VERIFICATION_TTL", 15*time.Minute)
+	viper.SetDefault("ANALYTICS_POLL_INTERVAL", 5*time.Second)
+	viper.SetDefault("ANALYTICS_BATCH_SIZE", 500)
+	viper.SetDefault("RISK_RULES_FILE", "")
+	viper.SetDefault("RISK_RULES_RELOAD_INTERVAL", 10*time.Second)
+	viper.SetDefault("SANCTIONS_LIST_FILE", "")
+	viper.SetDefault("SANCTIONS_RELOAD_INTERVAL", time.Minute)
+	viper.SetDefault("SANCTIONS_REVIEW_THRESHOLD", 0.85)
+	viper.SetDefault("SANCTIONS_BLOCK_THRESHOLD", 0.95)
+
 	// You can also tell it to read from a file (optional)
 	// viper.SetConfigName("config")
 	// viper.AddConfigPath(".")
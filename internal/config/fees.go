@@ -0,0 +1,32 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"wallet/internal/domain"
+)
+
+// LoadFeeSchedule reads the fee schedule from the JSON file at path. Without
+// a path no fees are charged.
+func LoadFeeSchedule(path string) (*domain.FeeSchedule, error) {
+	schedule := &domain.FeeSchedule{}
+	if path == "" {
+		return schedule, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fee schedule: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(schedule); err != nil {
+		return nil, fmt.Errorf("parse fee schedule %s: %w", path, err)
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fee schedule %s: %w", path, err)
+	}
+	return schedule, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ParseOperatorTokens parses OPERATOR_TOKENS: comma-separated name:hash pairs,
+// where hash is the hex SHA-256 of the token the operator presents. It returns
+// the hashes by operator name. Without operators nobody can call the admin
+// routes.
+func ParseOperatorTokens(s string) (map[string][]byte, error) {
+	tokens := make(map[string][]byte)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, digest, ok := strings.Cut(pair, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid operator token %q: want name:sha256", pair)
+		}
+		hash, err := hex.DecodeString(digest)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("invalid operator token for %s: want the hex SHA-256 of the token", name)
+		}
+		if _, dup := tokens[name]; dup {
+			return nil, fmt.Errorf("operator %s has more than one token", name)
+		}
+		tokens[name] = hash
+	}
+	return tokens, nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// watchedFile holds what load reads from a file, and reloads it whenever the
+// file changes so it can be updated without a restart.
+type watchedFile[T any] struct {
+	path   string
+	what   string // What the file holds, for logs and errors
+	load   func(path string) (*T, error)
+	logger *slog.Logger
+	value  atomic.Pointer[T]
+
+	// The version of the file the value was loaded from; only watch uses it.
+	modTime time.Time
+	size    int64
+}
+
+// init loads the file, which must be valid.
+func (f *watchedFile[T]) init(path, what string, load func(string) (*T, error), logger *slog.Logger) error {
+	f.path, f.what, f.load, f.logger = path, what, load, logger
+	_, err := f.reload()
+	return err
+}
+
+// watch checks the file every interval until ctx is done. A file that can't
+// be read or is invalid is reported and the value in force is kept.
+func (f *watchedFile[T]) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := f.reload()
+			if err != nil {
+				f.logger.ErrorContext(ctx, "Cannot reload "+f.what+", keeping the current one", "path", f.path, "error", err)
+			} else if reloaded {
+				f.logger.InfoContext(ctx, "Reloaded "+f.what, "path", f.path)
+			}
+		}
+	}
+}
+
+// reload loads the file again if it changed since it was loaded.
+func (f *watchedFile[T]) reload() (bool, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, fmt.Errorf("read %s: %w", f.what, err)
+	}
+	if f.value.Load() != nil && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return false, nil
+	}
+
+	value, err := f.load(f.path)
+	// Remember the version even when it's invalid, so it's reported once.
+	f.modTime, f.size = info.ModTime(), info.Size()
+	if err != nil {
+		return false, err
+	}
+	f.value.Store(value)
+	return true, nil
+}
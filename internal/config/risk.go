@@ -0,0 +1,55 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+	"wallet/internal/domain"
+)
+
+// LoadRiskRules reads the risk rules from the JSON file at path.
+func LoadRiskRules(path string) (*domain.RiskRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read risk rules: %w", err)
+	}
+	rules := &domain.RiskRules{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(rules); err != nil {
+		return nil, fmt.Errorf("parse risk rules %s: %w", path, err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid risk rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// RiskRulesFile serves the risk rules of a file, reloaded by Watch whenever
+// the file changes, so they can be tuned without a restart.
+type RiskRulesFile struct {
+	file watchedFile[domain.RiskRules]
+}
+
+// NewRiskRulesFile loads the rules at path, which must be valid.
+func NewRiskRulesFile(path string, logger *slog.Logger) (*RiskRulesFile, error) {
+	f := &RiskRulesFile{}
+	if err := f.file.init(path, "risk rules", LoadRiskRules, logger); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RiskRulesFile) RiskRules() *domain.RiskRules {
+	return f.file.value.Load()
+}
+
+// Watch checks the file every interval until ctx is done. A file that can't
+// be read or holds invalid rules is reported and the rules in force are kept.
+func (f *RiskRulesFile) Watch(ctx context.Context, interval time.Duration) {
+	f.file.watch(ctx, interval)
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"wallet/internal/domain"
+)
+
+// ofacNull is how the OFAC CSV files leave a field empty.
+const ofacNull = "-0-"
+
+// LoadSanctionsList reads a sanctions list in the OFAC SDN formats: sdn.xml,
+// or sdn.csv (uid, name, type, programs, ...; no aliases). The format is told
+// by the file extension.
+func LoadSanctionsList(path string) (*domain.SanctionsList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read sanctions list: %w", err)
+	}
+	defer file.Close()
+
+	var entries []domain.SanctionsEntry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		entries, err = parseSanctionsCSV(file)
+	case ".xml":
+		entries, err = parseSanctionsXML(file)
+	default:
+		return nil, fmt.Errorf("sanctions list %s: unknown format, expected .csv or .xml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse sanctions list %s: %w", path, err)
+	}
+	// An empty list is most likely a bad download; screening against it would
+	// let everybody through.
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("sanctions list %s has no entries", path)
+	}
+	return domain.NewSanctionsList(entries), nil
+}
+
+func parseSanctionsCSV(r io.Reader) ([]domain.SanctionsEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var entries []domain.SanctionsEntry
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Skips a header and the end-of-file marker OFAC appends.
+		if _, err := strconv.Atoi(strings.TrimSpace(record[0])); err != nil && (line == 1 || len(record) < 2) {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("line %d: expected at least a uid and a name", line)
+		}
+
+		field := func(i int) string {
+			if i >= len(record) || strings.TrimSpace(record[i]) == ofacNull {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		entry := domain.SanctionsEntry{ID: field(0), Name: field(1), Type: field(2)}
+		// Several programs come as "SDGT] [IRGC".
+		for _, program := range strings.Split(field(3), "] [") {
+			if program = strings.Trim(program, "[] "); program != "" {
+				entry.Programs = append(entry.Programs, program)
+			}
+		}
+		entries = append(entries, entry)
+	}
+}
+
+type sdnName struct {
+	FirstName string `xml:"firstName"`
+	LastName  string `xml:"lastName"`
+}
+
+func (n sdnName) String() string {
+	return strings.TrimSpace(n.FirstName + " " + n.LastName)
+}
+
+type sdnList struct {
+	Entries []struct {
+		UID string `xml:"uid"`
+		sdnName
+		Type     string    `xml:"sdnType"`
+		Programs []string  `xml:"programList>program"`
+		Akas     []sdnName `xml:"akaList>aka"`
+	} `xml:"sdnEntry"`
+}
+
+func parseSanctionsXML(r io.Reader) ([]domain.SanctionsEntry, error) {
+	var list sdnList
+	if err := xml.NewDecoder(r).Decode(&list); err != nil {
+		return nil, err
+	}
+	entries := make([]domain.SanctionsEntry, 0, len(list.Entries))
+	for _, e := range list.Entries {
+		entry := domain.SanctionsEntry{ID: e.UID, Name: e.sdnName.String(), Type: e.Type, Programs: e.Programs}
+		for _, aka := range e.Akas {
+			entry.Aliases = append(entry.Aliases, aka.String())
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// SanctionsListFile serves the sanctions list of a file, reloaded by Watch
+// whenever the file changes, so a new list applies without a restart.
+type SanctionsListFile struct {
+	file watchedFile[domain.SanctionsList]
+}
+
+// NewSanctionsListFile loads the list at path, which must be valid.
+func NewSanctionsListFile(path string, logger *slog.Logger) (*SanctionsListFile, error) {
+	f := &SanctionsListFile{}
+	if err := f.file.init(path, "sanctions list", LoadSanctionsList, logger); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *SanctionsListFile) SanctionsList() *domain.SanctionsList {
+	return f.file.value.Load()
+}
+
+// Watch checks the file every interval until ctx is done. A file that can't
+// be read or parsed is reported and the list in force is kept.
+func (f *SanctionsListFile) Watch(ctx context.Context, interval time.Duration) {
+	f.file.watch(ctx, interval)
+}
+
+// ValidateScreeningThresholds checks that both name similarity thresholds are
+// in (0, 1] and that a match is reviewed before it's blocked.
+func ValidateScreeningThresholds(review, block float64) error {
+	if review <= 0 || block > 1 || review > block {
+		return fmt.Errorf("invalid sanctions thresholds: need 0 < review (%g) <= block (%g) <= 1", review, block)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+// AnonymousActor is reported when nobody identified themselves.
+const AnonymousActor = "anonymous"
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying who performs the operation (an API
+// principal or an operator). It is recorded in movements and audit entries.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx, or AnonymousActor.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+type verifiedActorKey struct{}
+
+// WithVerifiedActor is WithActor for an actor whose identity was checked,
+// rather than taken from what the caller claims: an operator who presented
+// their token, or the system account running walletctl.
+func WithVerifiedActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(WithActor(ctx, actor), verifiedActorKey{}, actor)
+}
+
+// ActorVerified reports whether the actor in ctx was set by WithVerifiedActor.
+// An operation made on behalf of someone else with WithActor isn't verified.
+func ActorVerified(ctx context.Context) bool {
+	actor, ok := ctx.Value(verifiedActorKey{}).(string)
+	return ok && actor == ActorFromContext(ctx)
+}
+
+// userActorPrefix marks actors that are users of the API, as opposed to
+// operators ("operator:<name>") and background jobs ("system:<job>").
+const (
+	userActorPrefix     = "user:"
+	operatorActorPrefix = "operator:"
+	systemActorPrefix   = "system:"
+)
+
+// UserActor is the actor of the operations a user performs.
+func UserActor(userID string) string {
+	return userActorPrefix + userID
+}
+
+// UserFromContext returns the ID of the user acting in ctx, if the actor is a
+// user. Wallet memberships are enforced for users only.
+func UserFromContext(ctx context.Context) (string, bool) {
+	userID, ok := strings.CutPrefix(ActorFromContext(ctx), userActorPrefix)
+	return userID, ok && userID != ""
+}
+
+// OperatorActor is the actor of the operations an operator performs.
+func OperatorActor(name string) string {
+	return operatorActorPrefix + name
+}
+
+// OperatorFromContext returns the name of the operator acting in ctx, if the
+// actor is an operator.
+func OperatorFromContext(ctx context.Context) (string, bool) {
+	name, ok := strings.CutPrefix(ActorFromContext(ctx), operatorActorPrefix)
+	return name, ok && name != ""
+}
+
+// IsInternal reports whether the actor in ctx is an operator or a background
+// job. They act on any wallet; users only on the wallets they are members of,
+// and anonymous callers on none.
+func IsInternal(ctx context.Context) bool {
+	if _, ok := OperatorFromContext(ctx); ok {
+		return true
+	}
+	job, ok := strings.CutPrefix(ActorFromContext(ctx), systemActorPrefix)
+	return ok && job != ""
+}
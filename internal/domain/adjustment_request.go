@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// AdjustmentRequestStatus is the state of an adjustment request. Requests
+// start pending and end in exactly one of the other states.
+type AdjustmentRequestStatus string
+
+const (
+	AdjustmentPending  AdjustmentRequestStatus = "pending"
+	AdjustmentApproved AdjustmentRequestStatus = "approved" // The adjustment was applied
+	AdjustmentRejected AdjustmentRequestStatus = "rejected"
+	// AdjustmentFailed: the adjustment was approved but could not be applied,
+	// e.g. a debit larger than the wallet's available balance.
+	AdjustmentFailed AdjustmentRequestStatus = "failed"
+)
+
+// AdjustmentRequest is a manual credit (positive amount) or debit (negative
+// amount) an operator proposed. It only applies once another operator
+// approves it.
+type AdjustmentRequest struct {
+	ID          string                  `json:"id" gorm:"type:uuid;primary_key"`
+	WalletID    string                  `json:"wallet_id" gorm:"type:uuid;not null;index"`
+	Amount      float64                 `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Reason      string                  `json:"reason" gorm:"type:text;not null"`
+	Status      AdjustmentRequestStatus `json:"status" gorm:"type:varchar(16);not null;index"`
+	RequestedBy string                  `json:"requested_by" gorm:"type:varchar(255);not null"` // The maker
+	ReviewedBy  string                  `json:"reviewed_by,omitempty" gorm:"type:varchar(255);not null;default:''"`
+	ReviewedAt  *time.Time              `json:"reviewed_at,omitempty"`
+	Note        string                  `json:"note,omitempty" gorm:"type:text;not null;default:''"` // The checker's reason, or why the adjustment failed
+	MovementID  *string                 `json:"movement_id,omitempty" gorm:"type:uuid"`              // The adjustment movement, once applied
+	CreatedAt   time.Time               `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time               `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Resolve moves a pending request to status, reviewed by actor at now. It
+// returns ErrAdjustmentNotPending when the request was already resolved, and
+// ErrSelfReview when actor is the one who made it.
+func (r *AdjustmentRequest) Resolve(status AdjustmentRequestStatus, actor, note string, now time.Time) error {
+	if r.Status != AdjustmentPending {
+		return fmt.Errorf("%w: it is %s", ErrAdjustmentNotPending, r.Status)
+	}
+	if actor == r.RequestedBy {
+		return ErrSelfReview
+	}
+	r.Status = status
+	r.ReviewedBy = actor
+	r.ReviewedAt = &now
+	r.Note = note
+	return nil
+}
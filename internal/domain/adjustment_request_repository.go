@@ -0,0 +1,19 @@
+package domain
+
+import "context"
+
+// AdjustmentRequestRepository stores the adjustments waiting for, or given, a
+// second operator's decision.
+type AdjustmentRequestRepository interface {
+	Save(ctx context.Context, request *AdjustmentRequest) error
+	FindByID(ctx context.Context, id string) (*AdjustmentRequest, error)
+	// FindByIDForUpdate locks the request until the end of the transaction, so
+	// concurrent decisions on it run one after the other.
+	FindByIDForUpdate(ctx context.Context, id string) (*AdjustmentRequest, error)
+	// List returns the requests in status, oldest first; all of them when
+	// status is empty.
+	List(ctx context.Context, status AdjustmentRequestStatus) ([]AdjustmentRequest, error)
+	// Resolve saves the decision on a request that was pending. It returns
+	// ErrAdjustmentNotPending if the stored request no longer is.
+	Resolve(ctx context.Context, request *AdjustmentRequest) error
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AliasKind is a kind of identifier, other than the username, that users can
+// be found by.
+type AliasKind string
+
+const (
+	AliasPhone AliasKind = "phone"
+	AliasEmail AliasKind = "email"
+)
+
+// phonePattern is an E.164 phone number.
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// AliasCodeAttempts is how many wrong codes an alias verification accepts
+// before its code stops working and a new one must be sent.
+const AliasCodeAttempts = 5
+
+// UserAlias is a phone number or email address registered by a user, so
+// others can send them money without knowing their wallet ID. An alias finds
+// the user once verified with the code sent to it, and each value belongs to
+// one verified user at most.
+type UserAlias struct {
+	ID         string     `json:"id" gorm:"type:uuid;primary_key"`
+	UserID     string     `json:"user_id" gorm:"type:uuid;not null;index"`
+	Kind       AliasKind  `json:"kind" gorm:"type:varchar(16);not null;uniqueIndex:idx_user_aliases_kind_value,where:verified_at IS NOT NULL"`
+	Value      string     `json:"value" gorm:"type:varchar(255);not null;uniqueIndex:idx_user_aliases_kind_value,where:verified_at IS NOT NULL"` // Normalized, see NormalizeAlias
+	VerifiedAt *time.Time `json:"verified_at,omitempty" gorm:"type:timestamptz"`
+	// The pending verification code, hashed, and the wrong codes entered
+	// since it was sent.
+	CodeHash      string     `json:"-" gorm:"type:varchar(64);not null;default:''"`
+	CodeExpiresAt *time.Time `json:"-" gorm:"type:timestamptz"`
+	CodeAttempts  int        `json:"-" gorm:"type:integer;not null;default:0"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// IsVerified reports whether the user proved they hold the alias.
+func (a *UserAlias) IsVerified() bool {
+	return a.VerifiedAt != nil
+}
+
+// SetCode replaces the pending verification code with code, valid until expiresAt.
+func (a *UserAlias) SetCode(code string, expiresAt time.Time) {
+	a.CodeHash = hashAliasCode(a.ID, code)
+	a.CodeExpiresAt = &expiresAt
+	a.CodeAttempts = 0
+}
+
+// CheckCode reports whether code is the pending verification code, still
+// valid at now. A wrong code counts as an attempt.
+func (a *UserAlias) CheckCode(code string, now time.Time) bool {
+	if a.CodeHash == "" || a.CodeExpiresAt == nil || !now.Before(*a.CodeExpiresAt) || a.CodeAttempts >= AliasCodeAttempts {
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAliasCode(a.ID, code)), []byte(a.CodeHash)) != 1 {
+		a.CodeAttempts++
+		return false
+	}
+	return true
+}
+
+// Verify marks the alias verified at now and drops its code.
+func (a *UserAlias) Verify(now time.Time) {
+	a.VerifiedAt = &now
+	a.CodeHash = ""
+	a.CodeExpiresAt = nil
+	a.CodeAttempts = 0
+}
+
+// hashAliasCode hashes a verification code with the ID of its alias, so the
+// same code sent to two aliases isn't stored twice the same.
+func hashAliasCode(aliasID, code string) string {
+	sum := sha256.Sum256([]byte(aliasID + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeAlias returns the canonical form of an alias: phone numbers in
+// E.164 format without separators, email addresses in lower case.
+func NormalizeAlias(kind AliasKind, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch kind {
+	case AliasPhone:
+		phone := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(value)
+		if !phonePattern.MatchString(phone) {
+			return "", fmt.Errorf("%w: phone numbers must be in international format, e.g. +14155552671", ErrInvalidAlias)
+		}
+		return phone, nil
+	case AliasEmail:
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value || address.Name != "" {
+			return "", fmt.Errorf("%w: invalid email address", ErrInvalidAlias)
+		}
+		return strings.ToLower(value), nil
+	default:
+		return "", fmt.Errorf("%w: unknown kind %q", ErrInvalidAlias, kind)
+	}
+}
+
+// Recipient is the wallet a transfer to a username, alias or wallet ID goes to.
+type Recipient struct {
+	WalletID string `json:"wallet_id"`
+	Currency string `json:"currency"`
+	// MaskedName is the recipient's name with most letters hidden, for the
+	// sender to confirm they found the right person.
+	MaskedName string `json:"masked_name"`
+	// MatchedBy tells how the recipient was found: wallet_id, username, phone or email.
+	MatchedBy string `json:"matched_by"`
+}
+
+// MaskName hides all but the first letters of every word of name, e.g.
+// "John Doe" becomes "Jo** D**".
+func MaskName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		runes := []rune(word)
+		keep := 1
+		if len(runes) > 3 {
+			keep = 2
+		}
+		words[i] = string(runes[:keep]) + strings.Repeat("*", len(runes)-keep)
+	}
+	return strings.Join(words, " ")
+}
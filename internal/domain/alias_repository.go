@@ -0,0 +1,23 @@
+package domain
+
+import "context"
+
+// AliasRepository stores the aliases users can be found by.
+type AliasRepository interface {
+	// Save returns ErrAliasTaken when the user registered the value already.
+	Save(ctx context.Context, alias *UserAlias) error
+	// Update saves the verification state of an alias. It returns
+	// ErrAliasTaken when another user verified the value first.
+	Update(ctx context.Context, alias *UserAlias) error
+	ListByUser(ctx context.Context, userID string) ([]UserAlias, error)
+	// FindByIDForUpdate returns an alias of a user, locked until the end of
+	// the transaction so concurrent verifications run one after the other.
+	// It returns ErrAliasNotFound when the user has no such alias.
+	FindByIDForUpdate(ctx context.Context, userID, id string) (*UserAlias, error)
+	// FindByValue looks up a normalized, verified alias. It returns
+	// ErrAliasNotFound when nobody verified it.
+	FindByValue(ctx context.Context, kind AliasKind, value string) (*UserAlias, error)
+	// Delete removes an alias of a user. It returns ErrAliasNotFound when the
+	// user has no such alias.
+	Delete(ctx context.Context, userID, id string) error
+}
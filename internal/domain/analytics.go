@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MovementEvent tells the analytics that a movement was added to the ledger.
+// It is saved with the movement, in the same transaction, and deleted once the
+// movement is counted in the rollups.
+type MovementEvent struct {
+	MovementID string    `json:"movement_id" gorm:"type:uuid;primary_key"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// Merchant marks a wallet as a business. Movements with it are named after
+// the merchant and categorized with its category.
+type Merchant struct {
+	WalletID  string    `json:"wallet_id" gorm:"type:uuid;primary_key"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null"`
+	Category  string    `json:"category" gorm:"type:varchar(32);not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// CategorySource tells where the category of a movement comes from.
+type CategorySource string
+
+const (
+	CategoryByUser     CategorySource = "user"
+	CategoryByMerchant CategorySource = "merchant"
+	CategoryByDefault  CategorySource = "default"
+)
+
+// MovementCategory is the category a movement is counted under in the rollups.
+type MovementCategory struct {
+	MovementID string         `json:"movement_id" gorm:"type:uuid;primary_key"`
+	WalletID   string         `json:"wallet_id" gorm:"type:uuid;not null;index"`
+	Category   string         `json:"category" gorm:"type:varchar(32);not null"`
+	Source     CategorySource `json:"source" gorm:"type:varchar(16);not null"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// defaultCategories categorize the movements of every type but transfers to
+// and from merchants, and those the user categorized.
+var defaultCategories = map[MovementType]string{
+	MovementRecharge:       "top_up",
+	MovementTransferIn:     "transfer",
+	MovementTransferOut:    "transfer",
+	MovementAdjustment:     "adjustment",
+	MovementFee:            "fees",
+	MovementFeeIncome:      "fee_income",
+	MovementInterest:       "interest",
+	MovementOpeningBalance: "opening_balance",
+}
+
+// Categorize returns the category of a new movement: the merchant's one when
+// the counterparty is a merchant, otherwise the default for its type.
+func Categorize(movement *Movement, merchant *Merchant) MovementCategory {
+	category := MovementCategory{
+		MovementID: movement.ID,
+		WalletID:   movement.WalletID,
+		Category:   defaultCategories[movement.Type],
+		Source:     CategoryByDefault,
+	}
+	if category.Category == "" {
+		category.Category = string(movement.Type)
+	}
+	if merchant != nil && (movement.Type == MovementTransferIn || movement.Type == MovementTransferOut) {
+		category.Category = merchant.Category
+		category.Source = CategoryByMerchant
+	}
+	return category
+}
+
+var categoryPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// NormalizeCategory returns category in lower case with spaces as
+// underscores, e.g. "Eating out" becomes "eating_out".
+func NormalizeCategory(category string) (string, error) {
+	category = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(category)), " ", "_")
+	if !categoryPattern.MatchString(category) {
+		return "", fmt.Errorf("%w: use up to 32 letters, digits, '_' or '-'", ErrInvalidCategory)
+	}
+	return category, nil
+}
+
+// RollupDimension is what a rollup row breaks the movements of a month down by.
+type RollupDimension string
+
+const (
+	// RollupTotal rows hold all the movements of a wallet in a month; their key is empty.
+	RollupTotal        RollupDimension = "total"
+	RollupCounterparty RollupDimension = "counterparty" // Keyed by counterparty wallet ID
+	RollupCategory     RollupDimension = "category"     // Keyed by category
+)
+
+// AnalyticsRollup holds the totals of the movements of a wallet in a month,
+// for one value of a dimension. Rollups are updated as movements come in, so
+// insights never read the ledger.
+type AnalyticsRollup struct {
+	WalletID  string          `json:"wallet_id" gorm:"type:uuid;primaryKey"`
+	Month     time.Time       `json:"month" gorm:"type:date;primaryKey"` // First day of the month, UTC
+	Dimension RollupDimension `json:"dimension" gorm:"type:varchar(16);primaryKey"`
+	Key       string          `json:"key" gorm:"type:varchar(64);primaryKey"`
+	Inflow    float64         `json:"inflow" gorm:"type:decimal(15,2);not null;default:0"`
+	Outflow   float64         `json:"outflow" gorm:"type:decimal(15,2);not null;default:0"` // Positive
+	Count     int64           `json:"count" gorm:"type:bigint;not null;default:0"`
+}
+
+// RollupDeltas returns the changes counting movement under category makes to
+// the rollups of its wallet; sign -1 undoes them.
+func RollupDeltas(movement *Movement, category string, sign int) []AnalyticsRollup {
+	base := AnalyticsRollup{
+		WalletID: movement.WalletID,
+		Month:    MonthOf(movement.CreatedAt),
+		Count:    int64(sign),
+	}
+	if movement.Amount >= 0 {
+		base.Inflow = float64(sign) * movement.Amount
+	} else {
+		base.Outflow = float64(sign) * -movement.Amount
+	}
+
+	total, byCategory := base, base
+	total.Dimension = RollupTotal
+	byCategory.Dimension, byCategory.Key = RollupCategory, category
+	deltas := []AnalyticsRollup{total, byCategory}
+	if movement.CounterpartyWalletID != nil {
+		byCounterparty := base
+		byCounterparty.Dimension, byCounterparty.Key = RollupCounterparty, *movement.CounterpartyWalletID
+		deltas = append(deltas, byCounterparty)
+	}
+	return deltas
+}
+
+// MonthOf returns the first day of the UTC month of t.
+func MonthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// InsightRow is a rollup summed over the wallets of a user in one currency.
+type InsightRow struct {
+	Currency  string
+	Month     time.Time
+	Dimension RollupDimension
+	Key       string
+	Inflow    float64
+	Outflow   float64
+	Count     int64
+}
+
+// MonthlyTotal is the money that came in and went out in a month.
+type MonthlyTotal struct {
+	Month time.Time `json:"month"`
+	In    float64   `json:"in"`
+	Out   float64   `json:"out"`
+	Net   float64   `json:"net"`
+	Count int64     `json:"count"`
+}
+
+// CounterpartyTotal is the money exchanged with a wallet.
+type CounterpartyTotal struct {
+	WalletID string `json:"wallet_id"`
+	// Name is the merchant's name, or the owner's name masked (see MaskName).
+	Name  string  `json:"name"`
+	In    float64 `json:"in"`
+	Out   float64 `json:"out"`
+	Count int64   `json:"count"`
+}
+
+// CategoryTotal is the money that came in and went out under a category.
+type CategoryTotal struct {
+	Category string  `json:"category"`
+	In       float64 `json:"in"`
+	Out      float64 `json:"out"`
+	Count    int64   `json:"count"`
+}
+
+// CurrencyInsights sums up the movements of the wallets of a user in one currency.
+type CurrencyInsights struct {
+	Currency          string              `json:"currency"`
+	Months            []MonthlyTotal      `json:"months"`
+	TopCounterparties []CounterpartyTotal `json:"top_counterparties"`
+	Categories        []CategoryTotal     `json:"categories"`
+}
+
+// UserInsights sums up the movements of the wallets a user owns over the
+// months in [From, To).
+type UserInsights struct {
+	UserID     string             `json:"user_id"`
+	From       time.Time          `json:"from"`
+	To         time.Time          `json:"to"`
+	Currencies []CurrencyInsights `json:"currencies"`
+}
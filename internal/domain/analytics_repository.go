@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AnalyticsRepository stores the spending rollups and what feeds them.
+type AnalyticsRepository interface {
+	// ClaimEvents locks up to limit pending movement events, skipping those
+	// another transaction holds, and returns their movements. Call it inside
+	// a transaction, which should delete the events once they are counted.
+	ClaimEvents(ctx context.Context, limit int) ([]Movement, error)
+	DeleteEvents(ctx context.Context, movementIDs []string) error
+
+	// AddToRollups adds each delta to the rollup row with the same key,
+	// creating it if needed.
+	AddToRollups(ctx context.Context, deltas []AnalyticsRollup) error
+	SaveCategories(ctx context.Context, categories []MovementCategory) error
+	// FindCategoryForUpdate returns ErrMovementNotCategorized when the
+	// movement isn't counted in the rollups yet.
+	FindCategoryForUpdate(ctx context.Context, movementID string) (*MovementCategory, error)
+	UpdateCategory(ctx context.Context, category *MovementCategory) error
+
+	SaveMerchant(ctx context.Context, merchant *Merchant) error
+	// FindMerchants returns the merchants among walletIDs, by wallet ID.
+	FindMerchants(ctx context.Context, walletIDs []string) (map[string]Merchant, error)
+	// CounterpartyNames returns the merchant's name, or else the owner's
+	// name masked (see MaskName), of each of walletIDs.
+	CounterpartyNames(ctx context.Context, walletIDs []string) (map[string]string, error)
+
+	// ListInsights returns the rollups of the wallets userID owns for the
+	// months in [from, to), summed by currency, month, dimension and key.
+	ListInsights(ctx context.Context, userID string, from, to time.Time) ([]InsightRow, error)
+}
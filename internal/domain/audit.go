@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// AuditEntry records an operation performed on the system, who did it and why.
+type AuditEntry struct {
+	ID         string    `json:"id" gorm:"type:uuid;primary_key"`
+	Actor      string    `json:"actor" gorm:"type:varchar(255);not null"`
+	Action     string    `json:"action" gorm:"type:varchar(64);not null"`
+	EntityType string    `json:"entity_type" gorm:"type:varchar(32);not null"`
+	EntityID   string    `json:"entity_id" gorm:"type:varchar(64);not null;index"`
+	Reason     string    `json:"reason,omitempty" gorm:"type:text;not null;default:''"`
+	Details    string    `json:"details,omitempty" gorm:"type:jsonb;not null;default:'{}'"` // JSON document with action specific data
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// Audited actions.
+const (
+	AuditUserCreated         = "user.created"
+	AuditUserTierSet         = "user.tier_changed"
+	AuditUserAliasAdded      = "user.alias_added"
+	AuditUserAliasVerified   = "user.alias_verified"
+	AuditUserAliasGone       = "user.alias_removed"
+	AuditWalletAdjusted      = "wallet.adjusted"
+	AuditWalletFrozen        = "wallet.frozen"
+	AuditWalletUnfrozen      = "wallet.unfrozen"
+	AuditWalletProduct       = "wallet.product_changed"
+	AuditMemberInvited       = "wallet.member_invited"
+	AuditMemberJoined        = "wallet.member_joined"
+	AuditMemberRemoved       = "wallet.member_removed"
+	AuditMerchantSet         = "wallet.merchant_set"
+	AuditReviewApproved      = "transfer_review.approved"
+	AuditReviewRejected      = "transfer_review.rejected"
+	AuditHitConfirmed        = "screening_hit.confirmed"
+	AuditHitCleared          = "screening_hit.cleared"
+	AuditAdjustmentRequested = "adjustment_request.created"
+	AuditAdjustmentApproved  = "adjustment_request.approved"
+	AuditAdjustmentRejected  = "adjustment_request.rejected"
+	AuditEntityUser          = "user"
+	AuditEntityWallet        = "wallet"
+	AuditEntityReview        = "transfer_review"
+	AuditEntityHit           = "screening_hit"
+	AuditEntityAdjustment    = "adjustment_request"
+)
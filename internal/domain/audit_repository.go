@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// AuditRepository defines the contract for the audit log. Entries are only
+// ever appended.
+type AuditRepository interface {
+	Record(ctx context.Context, entry *AuditEntry) error
+}
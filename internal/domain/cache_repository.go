@@ -2,11 +2,20 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrCacheUnavailable is returned by a CacheRepository that is deliberately
+// not calling its backend (e.g. an open circuit breaker). Callers should go
+// straight to the source of truth.
+var ErrCacheUnavailable = errors.New("cache unavailable")
+
 // CacheRepository defines the contract for a cache.
 type CacheRepository interface {
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
+	Delete(ctx context.Context, keys ...string) error
+	// Ping checks that the cache backend is reachable.
+	Ping(ctx context.Context) error
 }
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+type readYourWritesKey struct{}
+
+// WithReadYourWrites marks ctx so that its reads are served by the primary
+// database and therefore observe writes that just committed, at the cost of
+// not offloading them to a replica.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesKey{}, true)
+}
+
+// ReadYourWrites reports whether ctx asked to read its own writes.
+func ReadYourWrites(ctx context.Context) bool {
+	v, _ := ctx.Value(readYourWritesKey{}).(bool)
+	return v
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// Currency is an entry of the currencies reference table. Wallets can only
+// hold currencies listed there.
+type Currency struct {
+	Code      string    `json:"code" gorm:"type:varchar(3);primary_key"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,94 @@
+package domain
+
+import "errors"
+
+// Errors returned by repositories and use cases so callers can tell expected
+// outcomes apart from infrastructure failures.
+var (
+	ErrUserNotFound           = errors.New("user not found")
+	ErrWalletNotFound         = errors.New("wallet not found")
+	ErrTransferBatchNotFound  = errors.New("transfer batch not found")
+	ErrScheduleNotFound       = errors.New("scheduled transfer not found")
+	ErrPaymentRequestNotFound = errors.New("payment request not found")
+	ErrAliasNotFound          = errors.New("alias not found")
+	ErrRecipientNotFound      = errors.New("recipient not found")
+	ErrMemberNotFound         = errors.New("wallet member not found")
+	ErrPocketNotFound         = errors.New("pocket not found")
+	ErrMovementNotFound       = errors.New("movement not found")
+	ErrReviewNotFound         = errors.New("transfer review not found")
+	ErrHitNotFound            = errors.New("screening hit not found")
+	ErrAdjustmentNotFound     = errors.New("adjustment request not found")
+
+	// Integrity violations, enforced by database constraints.
+	ErrUsernameTaken         = errors.New("username already exists")
+	ErrDNITaken              = errors.New("dni already registered")
+	ErrAliasTaken            = errors.New("alias already registered")
+	ErrAlreadyMember         = errors.New("user is already a member of this wallet")
+	ErrPocketNameTaken       = errors.New("wallet already has a pocket with this name")
+	ErrInsufficientFunds     = errors.New("insufficient funds")
+	ErrWalletAlreadyExists   = errors.New("user already has a wallet in this currency")
+	ErrUnsupportedCurrency   = errors.New("unsupported currency")
+	ErrUnknownProduct        = errors.New("unknown wallet product")
+	ErrInvalidOverdraftLimit = errors.New("overdraft limit cannot be negative")
+
+	// Business rule violations.
+	ErrWalletFrozen      = errors.New("wallet is frozen")
+	ErrFeeExceedsAmount  = errors.New("fee exceeds the amount")
+	ErrInvalidFeeRequest = errors.New("invalid fee request")
+	ErrCurrencyMismatch  = errors.New("wallets hold different currencies")
+
+	// Wallet membership violations.
+	ErrInvalidMember       = errors.New("invalid wallet member")
+	ErrNotWalletMember     = errors.New("not allowed to access this wallet")
+	ErrSpendNotAllowed     = errors.New("not allowed to send money from this wallet")
+	ErrSpendLimitExceeded  = errors.New("daily spending limit exceeded")
+	ErrCannotRemoveCreator = errors.New("the wallet's creator cannot be removed")
+	ErrOtherUser           = errors.New("not allowed to act on another user's data")
+
+	// Risk decisions on transfers.
+	ErrTransferBlocked  = errors.New("transfer blocked by risk rules")
+	ErrTransferHeld     = errors.New("transfer held for review")
+	ErrReviewNotPending = errors.New("transfer review is no longer pending")
+	// ErrHeldInAtomicBatch fails an atomic batch with a transfer the risk
+	// rules would hold, since a review approves transfers one by one.
+	ErrHeldInAtomicBatch = errors.New("transfer would be held for review, which an atomic batch cannot wait for")
+	ErrReviewByUser      = errors.New("transfers can only be reviewed by operators")
+
+	// Sanctions screening. ErrSanctioned doesn't tell the user why, so as not
+	// to tip off a sanctioned party.
+	ErrSanctioned      = errors.New("refused for compliance reasons")
+	ErrHitNotPending   = errors.New("screening hit is no longer pending")
+	ErrScreeningByUser = errors.New("screening hits can only be reviewed by operators")
+
+	// Maker-checker on manual adjustments: one operator requests, another one
+	// approves or rejects, both with a verified identity.
+	ErrAdjustmentNotPending  = errors.New("adjustment request is no longer pending")
+	ErrAdjustmentByOperator  = errors.New("adjustments can only be requested and reviewed by operators")
+	ErrOperatorNotVerified   = errors.New("adjustments can only be requested and reviewed by operators whose identity was verified")
+	ErrSelfReview            = errors.New("adjustment requests must be reviewed by another operator")
+	ErrAdjustmentNotApproved = errors.New("adjustments must be requested and approved first")
+
+	ErrReconciliationAlreadyRan = errors.New("reconciliation already ran for this day")
+	ErrInvalidStatementRange    = errors.New("statement must end after it starts")
+	ErrInvalidTransferBatch     = errors.New("invalid transfer batch")
+	ErrInvalidSchedule          = errors.New("invalid schedule")
+	ErrScheduleOver             = errors.New("scheduled transfer is completed or cancelled")
+	ErrInvalidPaymentRequest    = errors.New("invalid payment request")
+	ErrInvalidAlias             = errors.New("invalid alias")
+	ErrInvalidAliasCode         = errors.New("invalid or expired verification code")
+	ErrInvalidPocket            = errors.New("invalid pocket")
+	ErrInvalidAdjustment        = errors.New("invalid adjustment request")
+	ErrPocketClosed             = errors.New("pocket is closed")
+	ErrInvalidCategory          = errors.New("invalid category")
+	ErrInvalidInsightsRange     = errors.New("invalid insights range")
+	ErrMovementNotCategorized   = errors.New("movement is not analyzed yet, retry shortly")
+	ErrPaymentRequestNotPending = errors.New("payment request is no longer pending")
+	ErrPaymentRequestExpired    = errors.New("payment request has expired")
+
+	// ErrTransferBatchItemProcessed: another worker already processed the item.
+	ErrTransferBatchItemProcessed = errors.New("transfer batch item already processed")
+
+	// Concurrency conflicts. The operation can succeed if retried from scratch.
+	ErrConcurrentModification = errors.New("wallet was modified concurrently")
+	ErrTransactionConflict    = errors.New("transaction conflicted with a concurrent one")
+)
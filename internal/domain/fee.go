@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// FeeOperation is an operation fees are charged on.
+type FeeOperation string
+
+const (
+	FeeTransfer FeeOperation = "transfer"
+	FeeRecharge FeeOperation = "recharge"
+)
+
+// FeeBand prices the amounts up to UpTo. Bands are ordered by UpTo; the last
+// one may leave UpTo at 0 to cover every larger amount.
+type FeeBand struct {
+	UpTo       float64 `json:"up_to"`
+	Fixed      float64 `json:"fixed"`
+	Percentage float64 `json:"percentage"`
+}
+
+// FeeRule prices an operation. A rule without Currency or Tier applies to
+// every currency or user tier; the most specific matching rule wins.
+type FeeRule struct {
+	Operation  FeeOperation `json:"operation"`
+	Currency   string       `json:"currency,omitempty"`
+	Tier       string       `json:"tier,omitempty"`
+	Fixed      float64      `json:"fixed,omitempty"`
+	Percentage float64      `json:"percentage,omitempty"`
+	// Bands, when set, replace Fixed and Percentage: the band the amount falls
+	// in prices the whole amount.
+	Bands []FeeBand `json:"bands,omitempty"`
+	Min   float64   `json:"min,omitempty"`
+	Max   float64   `json:"max,omitempty"` // 0 means no cap
+}
+
+// FeeSchedule holds the fee rules and, per currency, the house wallet the
+// fees are credited to.
+type FeeSchedule struct {
+	Wallets map[string]string `json:"wallets"`
+	Rules   []FeeRule         `json:"rules"`
+}
+
+// FeeCredit queues a fee charged to a payer until it is credited to the
+// house wallet. Crediting every fee as it is charged would make all fee paying
+// traffic wait on the house wallet row, so fees are recorded on the payer
+// right away and credited in batches (see FeeUsecase).
+type FeeCredit struct {
+	// MovementID is the payer's fee movement, whose counterparty is the
+	// house wallet.
+	MovementID string    `json:"movement_id" gorm:"type:uuid;primary_key"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// FeeQuote is the fee an operation costs.
+type FeeQuote struct {
+	Operation FeeOperation `json:"operation"`
+	WalletID  string       `json:"wallet_id"`
+	Currency  string       `json:"currency"`
+	Amount    float64      `json:"amount"`
+	Fee       float64      `json:"fee"`
+	// Total is what the wallet is charged for a transfer (amount plus fee), or
+	// credited for a recharge (amount less fee).
+	Total float64 `json:"total"`
+}
+
+// Validate checks the rules are consistent.
+func (s *FeeSchedule) Validate() error {
+	for i, rule := range s.Rules {
+		if rule.Operation != FeeTransfer && rule.Operation != FeeRecharge {
+			return fmt.Errorf("rule %d: unknown operation %q", i, rule.Operation)
+		}
+		if rule.Fixed < 0 || rule.Percentage < 0 || rule.Min < 0 || rule.Max < 0 {
+			return fmt.Errorf("rule %d: fees cannot be negative", i)
+		}
+		if rule.Max > 0 && rule.Max < rule.Min {
+			return fmt.Errorf("rule %d: max is below min", i)
+		}
+		for j, band := range rule.Bands {
+			if band.Fixed < 0 || band.Percentage < 0 || band.UpTo < 0 {
+				return fmt.Errorf("rule %d, band %d: fees cannot be negative", i, j)
+			}
+			last := j == len(rule.Bands)-1
+			if band.UpTo == 0 && !last {
+				return fmt.Errorf("rule %d, band %d: only the last band can be unbounded", i, j)
+			}
+			if j > 0 && band.UpTo != 0 && band.UpTo <= rule.Bands[j-1].UpTo {
+				return fmt.Errorf("rule %d, band %d: bands must be ordered by up_to", i, j)
+			}
+		}
+		if rule.Currency != "" && s.Wallets[rule.Currency] == "" {
+			return fmt.Errorf("rule %d: no fee wallet for %s", i, rule.Currency)
+		}
+	}
+	return nil
+}
+
+// Charges reports whether any rule prices op.
+func (s *FeeSchedule) Charges(op FeeOperation) bool {
+	for _, rule := range s.Rules {
+		if rule.Operation == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Rule returns the most specific rule for op in currency for a user of tier,
+// or nil when there is none.
+func (s *FeeSchedule) Rule(op FeeOperation, currency, tier string) *FeeRule {
+	var best *FeeRule
+	bestScore := -1
+	for i := range s.Rules {
+		rule := &s.Rules[i]
+		if rule.Operation != op ||
+			(rule.Currency != "" && rule.Currency != currency) ||
+			(rule.Tier != "" && rule.Tier != tier) {
+			continue
+		}
+		// A currency match is more specific than a tier match.
+		score := 0
+		if rule.Currency != "" {
+			score += 2
+		}
+		if rule.Tier != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+	return best
+}
+
+// Fee returns the fee for amount, rounded to the cent.
+func (r *FeeRule) Fee(amount float64) float64 {
+	fixed, percentage := r.Fixed, r.Percentage
+	for i, band := range r.Bands {
+		if band.UpTo == 0 || amount <= band.UpTo || i == len(r.Bands)-1 {
+			fixed, percentage = band.Fixed, band.Percentage
+			break
+		}
+	}
+
+	fee := fixed + amount*percentage/100
+	if fee < r.Min {
+		fee = r.Min
+	}
+	if r.Max > 0 && fee > r.Max {
+		fee = r.Max
+	}
+	return math.Round(fee*100) / 100
+}
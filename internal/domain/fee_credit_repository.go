@@ -0,0 +1,15 @@
+package domain
+
+import "context"
+
+// FeeCreditRepository queues the fees waiting to be credited to the house
+// wallets.
+type FeeCreditRepository interface {
+	Save(ctx context.Context, credit *FeeCredit) error
+	// Claim locks up to limit pending fee credits, skipping those another
+	// transaction holds, and returns the payers' fee movements, oldest first.
+	// Call it inside a transaction, which should delete the credits once the
+	// fees are credited.
+	Claim(ctx context.Context, limit int) ([]Movement, error)
+	Delete(ctx context.Context, movementIDs []string) error
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Wallet products. Every wallet has one; its interest rate is what the
+// wallet earns.
+const (
+	ProductCurrent = "current"
+	ProductSavings = "savings"
+)
+
+// WalletProduct is an entry of the wallet_products reference table.
+type WalletProduct struct {
+	Code         string    `json:"code" gorm:"type:varchar(32);primary_key"`
+	Name         string    `json:"name" gorm:"type:varchar(255);not null"`
+	InterestRate float64   `json:"interest_rate" gorm:"type:decimal(7,4);not null;default:0"` // Annual, in percent
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// DayCount is the day-count convention turning an annual rate into a daily one.
+type DayCount string
+
+const (
+	DayCountAct365 DayCount = "ACT/365" // 365 days a year, leap years included
+	DayCountAct360 DayCount = "ACT/360"
+	DayCountActAct DayCount = "ACT/ACT" // 365 or 366 days, depending on the year of the day
+)
+
+// ParseDayCount validates a day-count convention.
+func ParseDayCount(s string) (DayCount, error) {
+	switch dc := DayCount(s); dc {
+	case DayCountAct365, DayCountAct360, DayCountActAct:
+		return dc, nil
+	default:
+		return "", fmt.Errorf("unknown day count convention %q", s)
+	}
+}
+
+// DailyInterest is the interest balance earns over day at an annual rate in
+// percent. It isn't rounded: accruals keep the fractions of a cent and only
+// their monthly total is rounded when posted.
+func (dc DayCount) DailyInterest(balance, rate float64, day time.Time) float64 {
+	days := 365.0
+	switch dc {
+	case DayCountAct360:
+		days = 360
+	case DayCountActAct:
+		if year := day.Year(); year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			days = 366
+		}
+	}
+	return balance * rate / 100 / days
+}
+
+// InterestAccrual is the interest a wallet earned over one day. There is at
+// most one per wallet and day, so running the accrual again never pays twice.
+// Accruals stay pending until posted to the wallet at the end of the month.
+type InterestAccrual struct {
+	ID         string     `json:"id" gorm:"type:uuid;primary_key"`
+	WalletID   string     `json:"wallet_id" gorm:"type:uuid;not null;uniqueIndex:idx_interest_accruals_wallet_id_day"`
+	Day        time.Time  `json:"day" gorm:"type:date;not null;uniqueIndex:idx_interest_accruals_wallet_id_day"`
+	Balance    float64    `json:"balance" gorm:"type:decimal(15,2);not null"` // End-of-day balance
+	Rate       float64    `json:"rate" gorm:"type:decimal(7,4);not null"`
+	Amount     float64    `json:"amount" gorm:"type:decimal(20,8);not null"`
+	MovementID *string    `json:"movement_id,omitempty" gorm:"type:uuid"` // The movement that paid it; nil while pending
+	PostedAt   *time.Time `json:"posted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// InterestCandidate is a wallet that earns interest, with its balance at the
+// end of the day being accrued.
+type InterestCandidate struct {
+	WalletID string
+	Balance  float64
+	Rate     float64
+}
+
+// InterestSummary is the interest situation of a wallet.
+type InterestSummary struct {
+	WalletID     string  `json:"wallet_id"`
+	Product      string  `json:"product"`
+	InterestRate float64 `json:"interest_rate"`
+	// Pending is the interest accrued but not posted yet, rounded to the cent.
+	Pending float64 `json:"pending"`
+	// AccruedThrough is the last day accrued, if any.
+	AccruedThrough *time.Time `json:"accrued_through,omitempty"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// InterestRepository stores interest accruals.
+type InterestRepository interface {
+	// ListCandidates returns up to limit wallets earning interest, ordered by
+	// ID and starting after afterID, with their balance at the end of day
+	// according to the ledger, or their opening balance when they had no
+	// movement by then.
+	ListCandidates(ctx context.Context, day time.Time, afterID string, limit int) ([]InterestCandidate, error)
+	// SaveAccruals stores accruals, skipping wallets already accrued for the
+	// day, and returns how many were stored.
+	SaveAccruals(ctx context.Context, accruals []InterestAccrual) (int, error)
+	// LastAccruedDay returns the latest day accrued for any wallet, or nil.
+	LastAccruedDay(ctx context.Context) (*time.Time, error)
+	// ListWalletsToPost returns up to limit wallets, ordered by ID and
+	// starting after afterID, with pending accruals for days before before.
+	ListWalletsToPost(ctx context.Context, before time.Time, afterID string, limit int) ([]string, error)
+	// LockPending locks and returns the pending accruals of a wallet for days
+	// before before. It must run in a transaction.
+	LockPending(ctx context.Context, walletID string, before time.Time) ([]InterestAccrual, error)
+	// MarkPosted records that movementID paid the accruals.
+	MarkPosted(ctx context.Context, ids []string, movementID *string, postedAt time.Time) error
+	// Pending returns the sum of the pending accruals of a wallet and the last day accrued.
+	Pending(ctx context.Context, walletID string) (float64, *time.Time, error)
+	FindProduct(ctx context.Context, code string) (*WalletProduct, error)
+}
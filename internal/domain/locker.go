@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Locker hands out short-lived locks shared by every instance of the service.
+type Locker interface {
+	// TryLock tries to acquire key for at most ttl without waiting. ok is false
+	// when someone else holds it. The returned unlock releases the lock only if
+	// it is still ours.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(context.Context) error, ok bool, err error)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// MovementType classifies a change of a wallet's balance.
+type MovementType string
+
+const (
+	MovementRecharge    MovementType = "recharge"
+	MovementTransferIn  MovementType = "transfer_in"
+	MovementTransferOut MovementType = "transfer_out"
+	MovementAdjustment  MovementType = "adjustment"
+	// MovementFee is a fee charged to a wallet; MovementFeeIncome is the same
+	// fee credited to the house fee wallet.
+	MovementFee       MovementType = "fee"
+	MovementFeeIncome MovementType = "fee_income"
+	// MovementInterest pays the interest a wallet accrued over a month.
+	MovementInterest MovementType = "interest"
+	// MovementOpeningBalance carries the balance a wallet had before the ledger existed.
+	MovementOpeningBalance MovementType = "opening_balance"
+)
+
+// Movement is a ledger entry: every change of a wallet's balance is recorded
+// as one, in the same transaction as the change.
+type Movement struct {
+	ID                   string       `json:"id" gorm:"type:uuid;primary_key"`
+	WalletID             string       `json:"wallet_id" gorm:"type:uuid;not null;index"`
+	Type                 MovementType `json:"type" gorm:"type:varchar(32);not null"`
+	Amount               float64      `json:"amount" gorm:"type:decimal(15,2);not null"`        // Positive for credits, negative for debits
+	BalanceAfter         float64      `json:"balance_after" gorm:"type:decimal(15,2);not null"` // Wallet balance right after this movement
+	CounterpartyWalletID *string      `json:"counterparty_wallet_id,omitempty" gorm:"type:uuid"`
+	Reference            string       `json:"reference,omitempty" gorm:"type:varchar(255);not null;default:''"` // Free text, e.g. the reason of an adjustment
+	Actor                string       `json:"actor" gorm:"type:varchar(255);not null"`
+	CreatedAt            time.Time    `json:"created_at" gorm:"autoCreateTime"`
+}
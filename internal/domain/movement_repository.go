@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MovementRepository defines the contract for the wallet ledger.
+type MovementRepository interface {
+	// Save also records a MovementEvent for the analytics, atomically.
+	Save(ctx context.Context, movement *Movement) error
+	// FindByID returns ErrMovementNotFound when there is no such movement.
+	FindByID(ctx context.Context, id string) (*Movement, error)
+	// ListByWallet returns the movements of a wallet created in [from, to), oldest first.
+	ListByWallet(ctx context.Context, walletID string, from, to time.Time) ([]Movement, error)
+	// ListByWalletAfter pages through the same movements as ListByWallet: it
+	// returns up to limit of them, starting after the movement after (from the
+	// first one when nil).
+	ListByWalletAfter(ctx context.Context, walletID string, from, to time.Time, after *Movement, limit int) ([]Movement, error)
+	// BalanceAt returns the balance of a wallet right before at, according to its ledger.
+	BalanceAt(ctx context.Context, walletID string, at time.Time) (float64, error)
+	// SpentBy returns how much actor took out of a wallet since since, in
+	// transfers and the fees they paid.
+	SpentBy(ctx context.Context, walletID, actor string, since time.Time) (float64, error)
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "context"
+
+// Notification kinds.
+const (
+	NotificationScheduledTransferFailed = "scheduled_transfer.failed"
+	NotificationPaymentRequested        = "payment_request.created"
+	NotificationPaymentRequestResolved  = "payment_request.resolved"
+	// NotificationAliasCode carries the code verifying an alias, to be
+	// delivered to the phone number or email address itself (data.kind and
+	// data.value), not to the user's usual channel.
+	NotificationAliasCode = "user_alias.verification_code"
+)
+
+// Notification is a message for a user about something that happened to
+// their wallet outside of a request they made.
+type Notification struct {
+	Kind    string                 `json:"kind"`
+	UserID  string                 `json:"user_id"`
+	Message string                 `json:"message"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+// Notifier delivers notifications to users.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// PaymentRequestStatus is the state of a payment request. Requests start
+// pending and end paid, declined or expired, possibly held in between.
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestPending PaymentRequestStatus = "pending"
+	// PaymentRequestHeld requests were accepted, but their transfer waits
+	// for the review in ReviewID: they are paid if it is approved and made,
+	// declined otherwise.
+	PaymentRequestHeld     PaymentRequestStatus = "held"
+	PaymentRequestPaid     PaymentRequestStatus = "paid"
+	PaymentRequestDeclined PaymentRequestStatus = "declined"
+	PaymentRequestExpired  PaymentRequestStatus = "expired"
+)
+
+// paymentRequestTransitions lists the states each state can move to.
+var paymentRequestTransitions = map[PaymentRequestStatus][]PaymentRequestStatus{
+	PaymentRequestPending: {PaymentRequestPaid, PaymentRequestHeld, PaymentRequestDeclined, PaymentRequestExpired},
+	PaymentRequestHeld:    {PaymentRequestPaid, PaymentRequestDeclined},
+}
+
+// PaymentRequest asks the owner of PayerWalletID to transfer Amount to
+// RequesterWalletID.
+type PaymentRequest struct {
+	ID                string               `json:"id" gorm:"type:uuid;primary_key"`
+	RequesterWalletID string               `json:"requester_wallet_id" gorm:"type:uuid;not null;index"`
+	PayerWalletID     string               `json:"payer_wallet_id" gorm:"type:uuid;not null;index"`
+	Amount            float64              `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Note              string               `json:"note,omitempty" gorm:"type:varchar(255);not null;default:''"`
+	Status            PaymentRequestStatus `json:"status" gorm:"type:varchar(16);not null"`
+	Actor             string               `json:"actor" gorm:"type:varchar(255);not null"` // Who created the request
+	ExpiresAt         time.Time            `json:"expires_at" gorm:"not null"`
+	ResolvedAt        *time.Time           `json:"resolved_at,omitempty"` // When the request left pending
+	ResolvedBy        string               `json:"resolved_by,omitempty" gorm:"type:varchar(255);not null;default:''"`
+	ReviewID          *string              `json:"review_id,omitempty" gorm:"type:uuid"` // The review of a held transfer
+	CreatedAt         time.Time            `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time            `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// IsExpired reports whether a pending request can no longer be paid at now.
+func (r *PaymentRequest) IsExpired(now time.Time) bool {
+	return r.Status == PaymentRequestPending && !now.Before(r.ExpiresAt)
+}
+
+// Transition moves the request to status, resolved by actor at now. It returns
+// ErrPaymentRequestNotPending when the current state doesn't allow it. A held
+// request keeps who resolved it and when: the review only decides how it ends.
+func (r *PaymentRequest) Transition(status PaymentRequestStatus, actor string, now time.Time) error {
+	for _, allowed := range paymentRequestTransitions[r.Status] {
+		if allowed == status {
+			if r.Status == PaymentRequestPending {
+				r.ResolvedAt = &now
+				r.ResolvedBy = actor
+			}
+			r.Status = status
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: it is %s", ErrPaymentRequestNotPending, r.Status)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PaymentRequestRepository stores payment requests.
+type PaymentRequestRepository interface {
+	Save(ctx context.Context, request *PaymentRequest) error
+	FindByID(ctx context.Context, id string) (*PaymentRequest, error)
+	// FindByIDForUpdate locks the request until the end of the transaction,
+	// so concurrent attempts to resolve it run one after the other.
+	FindByIDForUpdate(ctx context.Context, id string) (*PaymentRequest, error)
+	// FindByReviewIDForUpdate locks the request held on a transfer review. It
+	// returns ErrPaymentRequestNotFound if no request is.
+	FindByReviewIDForUpdate(ctx context.Context, reviewID string) (*PaymentRequest, error)
+	// ListOpen returns the pending, unexpired requests walletID sent or
+	// received, newest first.
+	ListOpen(ctx context.Context, walletID string, now time.Time) ([]PaymentRequest, error)
+	// Resolve saves the new state of a request that was in status from. It
+	// returns ErrPaymentRequestNotPending if the stored request no longer is.
+	Resolve(ctx context.Context, request *PaymentRequest, from PaymentRequestStatus) error
+	// ExpireDue moves every pending request expired at now to expired, and
+	// returns them.
+	ExpireDue(ctx context.Context, now time.Time) ([]PaymentRequest, error)
+}
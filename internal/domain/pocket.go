@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// PocketStatus tells whether a pocket still holds money.
+type PocketStatus string
+
+const (
+	PocketOpen PocketStatus = "open"
+	// PocketClosed pockets were emptied back into their wallet for good.
+	PocketClosed PocketStatus = "closed"
+)
+
+// Pocket is money set aside inside a wallet, optionally towards a savings
+// goal. It stays part of the wallet's balance (see Wallet.Pocketed), but can't
+// be spent until it is moved back out.
+type Pocket struct {
+	ID       string `json:"id" gorm:"type:uuid;primary_key"`
+	WalletID string `json:"wallet_id" gorm:"type:uuid;not null;index"`
+	Name     string `json:"name" gorm:"type:varchar(64);not null"` // Unique among the open pockets of the wallet
+	// TargetAmount and TargetDate are the goal of the pocket, if it has one.
+	TargetAmount float64      `json:"target_amount,omitempty" gorm:"type:decimal(15,2);not null;default:0"`
+	TargetDate   *time.Time   `json:"target_date,omitempty" gorm:"type:date"`
+	Balance      float64      `json:"balance" gorm:"type:decimal(15,2);not null;default:0"`
+	Status       PocketStatus `json:"status" gorm:"type:varchar(16);not null;default:'open'"`
+	CreatedAt    time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Validate checks the name and the goal of the pocket.
+func (p *Pocket) Validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" || len(p.Name) > 64 {
+		return fmt.Errorf("%w: name must have 1 to 64 characters", ErrInvalidPocket)
+	}
+	if p.TargetAmount < 0 {
+		return fmt.Errorf("%w: target amount cannot be negative", ErrInvalidPocket)
+	}
+	if p.TargetDate != nil && p.TargetAmount == 0 {
+		return fmt.Errorf("%w: a target date needs a target amount", ErrInvalidPocket)
+	}
+	return nil
+}
+
+// PocketMovement records money moved between a wallet and one of its pockets.
+// It doesn't change the wallet's balance, so it is not a ledger Movement.
+type PocketMovement struct {
+	ID           string    `json:"id" gorm:"type:uuid;primary_key"`
+	PocketID     string    `json:"pocket_id" gorm:"type:uuid;not null;index"`
+	WalletID     string    `json:"wallet_id" gorm:"type:uuid;not null"`
+	Amount       float64   `json:"amount" gorm:"type:decimal(15,2);not null"`        // Positive into the pocket, negative out of it
+	BalanceAfter float64   `json:"balance_after" gorm:"type:decimal(15,2);not null"` // Pocket balance right after this movement
+	Actor        string    `json:"actor" gorm:"type:varchar(255);not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// PocketProgress is a pocket and how far it is from its goal.
+type PocketProgress struct {
+	Pocket
+	// Percent of the target amount saved, at most 100. Omitted without a goal.
+	Percent   *float64 `json:"percent,omitempty"`
+	Remaining float64  `json:"remaining"` // Left to save to reach the target amount
+	Reached   bool     `json:"reached"`
+	// DaysLeft until the target date, negative once it has passed.
+	DaysLeft *int `json:"days_left,omitempty"`
+	// MonthlyNeeded is how much to save every 30 days from now on to reach
+	// the target amount by the target date.
+	MonthlyNeeded *float64 `json:"monthly_needed,omitempty"`
+}
+
+// Progress reports how far the pocket is from its goal at now.
+func (p *Pocket) Progress(now time.Time) PocketProgress {
+	progress := PocketProgress{Pocket: *p}
+	if p.TargetAmount == 0 {
+		return progress
+	}
+
+	percent := math.Min(100, math.Round(p.Balance/p.TargetAmount*10000)/100)
+	progress.Percent = &percent
+	progress.Remaining = math.Max(0, math.Round((p.TargetAmount-p.Balance)*100)/100)
+	progress.Reached = progress.Remaining == 0
+
+	if p.TargetDate != nil {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		days := int(p.TargetDate.Sub(today).Hours() / 24)
+		progress.DaysLeft = &days
+		if !progress.Reached && days > 0 {
+			monthly := math.Ceil(progress.Remaining/math.Ceil(float64(days)/30)*100) / 100
+			progress.MonthlyNeeded = &monthly
+		}
+	}
+	return progress
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "context"
+
+// PocketRepository stores the pockets of wallets and the money moved in and
+// out of them.
+type PocketRepository interface {
+	// Save returns ErrPocketNameTaken when the wallet has an open pocket with
+	// the same name.
+	Save(ctx context.Context, pocket *Pocket) error
+	// FindByID returns ErrPocketNotFound when there is no such pocket.
+	FindByID(ctx context.Context, id string) (*Pocket, error)
+	// ListByWallet returns the open pockets of a wallet, oldest first.
+	ListByWallet(ctx context.Context, walletID string) ([]Pocket, error)
+	// Update saves the balance and status of a pocket. Pockets aren't
+	// versioned: callers update the pocket's wallet in the same transaction,
+	// which serializes concurrent changes (see WalletRepository.Update).
+	Update(ctx context.Context, pocket *Pocket) error
+
+	SaveMovement(ctx context.Context, movement *PocketMovement) error
+	// ListMovements returns the movements of a pocket, oldest first.
+	ListMovements(ctx context.Context, pocketID string) ([]PocketMovement, error)
+}
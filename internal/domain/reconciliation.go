@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// ReconciliationTrigger tells how a reconciliation run was started.
+type ReconciliationTrigger string
+
+const (
+	ReconciliationScheduled ReconciliationTrigger = "scheduled"
+	ReconciliationManual    ReconciliationTrigger = "manual"
+)
+
+// ReconciliationStatus is the progress of a reconciliation run.
+type ReconciliationStatus string
+
+const (
+	ReconciliationRunning   ReconciliationStatus = "running"
+	ReconciliationCompleted ReconciliationStatus = "completed"
+	ReconciliationFailed    ReconciliationStatus = "failed"
+)
+
+// ReconciliationRun is one pass of checking wallet balances against the ledger.
+type ReconciliationRun struct {
+	ID      string                `json:"id" gorm:"type:uuid;primary_key"`
+	Trigger ReconciliationTrigger `json:"trigger" gorm:"type:varchar(16);not null"`
+	// ScheduledFor is the day a scheduled run covers. At most one run exists
+	// per day, so instances sharing the schedule don't run it twice.
+	ScheduledFor   *time.Time           `json:"scheduled_for,omitempty" gorm:"type:date;uniqueIndex"`
+	Status         ReconciliationStatus `json:"status" gorm:"type:varchar(16);not null"`
+	Actor          string               `json:"actor" gorm:"type:varchar(255);not null"`
+	WalletsChecked int                  `json:"wallets_checked" gorm:"type:integer;not null;default:0"`
+	Discrepancies  int                  `json:"discrepancies" gorm:"type:integer;not null;default:0"`
+	Error          string               `json:"error,omitempty" gorm:"type:text;not null;default:''"`
+	StartedAt      time.Time            `json:"started_at" gorm:"type:timestamptz;not null"`
+	FinishedAt     *time.Time           `json:"finished_at,omitempty" gorm:"type:timestamptz"`
+}
+
+// DiscrepancyKind tells which invariant a discrepancy breaks.
+type DiscrepancyKind string
+
+const (
+	// DiscrepancyWalletBalance: a wallet's balance differs from the sum of its movements.
+	DiscrepancyWalletBalance DiscrepancyKind = "wallet_balance"
+	// DiscrepancyCurrencyTotal: the money held in a currency differs from what
+	// entered the system through recharges, adjustments and opening balances,
+	// i.e. transfers created or destroyed money.
+	DiscrepancyCurrencyTotal DiscrepancyKind = "currency_total"
+)
+
+// ReconciliationDiscrepancy is an invariant violation found by a run.
+type ReconciliationDiscrepancy struct {
+	ID       string          `json:"id" gorm:"type:uuid;primary_key"`
+	RunID    string          `json:"run_id" gorm:"type:uuid;not null;index"`
+	Kind     DiscrepancyKind `json:"kind" gorm:"type:varchar(32);not null"`
+	WalletID *string         `json:"wallet_id,omitempty" gorm:"type:uuid"`
+	Currency string          `json:"currency" gorm:"type:varchar(3);not null"`
+	// Expected is what the ledger says, Actual what the balances say.
+	Expected  float64   `json:"expected" gorm:"type:decimal(20,2);not null"`
+	Actual    float64   `json:"actual" gorm:"type:decimal(20,2);not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// Difference is how much the balances exceed the ledger.
+func (d *ReconciliationDiscrepancy) Difference() float64 {
+	return d.Actual - d.Expected
+}
+
+// WalletLedgerBalance is a wallet's stored balance next to the one its movements add up to.
+type WalletLedgerBalance struct {
+	WalletID      string
+	Currency      string
+	Balance       float64
+	LedgerBalance float64
+}
+
+// CurrencyTotal is the money held in a currency next to the net amount that
+// entered the system in it: every movement except transfers.
+type CurrencyTotal struct {
+	Currency string
+	Balance  float64
+	Inflows  float64
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "context"
+
+// ReconciliationRepository reads the balances to reconcile and stores the reports.
+type ReconciliationRepository interface {
+	// StartRun saves a new run. It returns ErrReconciliationAlreadyRan when a
+	// run already exists for run.ScheduledFor.
+	StartRun(ctx context.Context, run *ReconciliationRun) error
+	FinishRun(ctx context.Context, run *ReconciliationRun) error
+	SaveDiscrepancies(ctx context.Context, discrepancies []ReconciliationDiscrepancy) error
+
+	// LedgerBalances returns up to limit wallets ordered by ID, starting after
+	// afterWalletID, each with its balance and the sum of its movements read
+	// from the same snapshot.
+	LedgerBalances(ctx context.Context, afterWalletID string, limit int) ([]WalletLedgerBalance, error)
+	// CurrencyTotals returns, per currency, the sum of all balances and of all
+	// movements that bring money into the system, read from the same snapshot.
+	CurrencyTotals(ctx context.Context) ([]CurrencyTotal, error)
+}
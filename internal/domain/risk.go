@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RiskDecision is what happens to a transfer after it is scored.
+type RiskDecision string
+
+const (
+	RiskAllow  RiskDecision = "allow"
+	RiskReview RiskDecision = "review" // Held until an operator approves or rejects it
+	RiskBlock  RiskDecision = "block"
+)
+
+// VelocityRule scores a transfer that takes the sender over MaxTransfers
+// transfers in the last WindowMinutes.
+type VelocityRule struct {
+	WindowMinutes int `json:"window_minutes"`
+	MaxTransfers  int `json:"max_transfers"`
+	Score         int `json:"score"`
+}
+
+// NewRecipientRule scores a transfer of at least MinAmount to a wallet the
+// sender never sent money to.
+type NewRecipientRule struct {
+	MinAmount float64 `json:"min_amount"`
+	Score     int     `json:"score"`
+}
+
+// UnusualHourRule scores a transfer made between the UTC hours From
+// (inclusive) and To (exclusive). From may be after To to span midnight.
+type UnusualHourRule struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Score int `json:"score"`
+}
+
+// AmountSpikeRule scores a transfer more than Multiplier times the sender's
+// average transfer of the last LookbackDays. Senders with fewer than
+// MinHistory transfers in that time are not scored.
+type AmountSpikeRule struct {
+	LookbackDays int     `json:"lookback_days"`
+	MinHistory   int     `json:"min_history"`
+	Multiplier   float64 `json:"multiplier"`
+	Score        int     `json:"score"`
+}
+
+// RiskRules score transfers before they are made. The scores of the rules a
+// transfer hits add up: from ReviewScore on it is held for review, from
+// BlockScore on it is refused. Rules left out don't apply.
+type RiskRules struct {
+	ReviewScore  int               `json:"review_score"`
+	BlockScore   int               `json:"block_score"`
+	Velocity     *VelocityRule     `json:"velocity,omitempty"`
+	NewRecipient *NewRecipientRule `json:"new_recipient,omitempty"`
+	UnusualHour  *UnusualHourRule  `json:"unusual_hour,omitempty"`
+	AmountSpike  *AmountSpikeRule  `json:"amount_spike,omitempty"`
+}
+
+// Validate checks the thresholds and every rule present.
+func (r *RiskRules) Validate() error {
+	if r.ReviewScore <= 0 || r.BlockScore < r.ReviewScore {
+		return errors.New("review_score must be positive and block_score at least review_score")
+	}
+	if v := r.Velocity; v != nil && (v.WindowMinutes <= 0 || v.MaxTransfers <= 0 || v.Score < 0) {
+		return errors.New("velocity: window_minutes and max_transfers must be positive, score non-negative")
+	}
+	if n := r.NewRecipient; n != nil && (n.MinAmount < 0 || n.Score < 0) {
+		return errors.New("new_recipient: min_amount and score cannot be negative")
+	}
+	if h := r.UnusualHour; h != nil && (h.From < 0 || h.From > 23 || h.To < 0 || h.To > 23 || h.From == h.To || h.Score < 0) {
+		return errors.New("unusual_hour: from and to must be different hours between 0 and 23, score non-negative")
+	}
+	if s := r.AmountSpike; s != nil && (s.LookbackDays <= 0 || s.MinHistory <= 0 || s.Multiplier <= 1 || s.Score < 0) {
+		return errors.New("amount_spike: lookback_days and min_history must be positive, multiplier above 1, score non-negative")
+	}
+	return nil
+}
+
+// TransferHistory is what the rules need to know about the sender's past
+// transfers (fees and other movements don't count).
+type TransferHistory struct {
+	// Recent is how many transfers the sender made in the velocity window.
+	Recent int64
+	// KnownRecipient tells whether the sender ever sent money to the recipient.
+	KnownRecipient bool
+	// Count and Average describe the sender's transfers in the spike lookback.
+	Count   int64
+	Average float64
+}
+
+// RiskAssessment is the outcome of scoring a transfer.
+type RiskAssessment struct {
+	Score    int          `json:"score"`
+	Decision RiskDecision `json:"decision"`
+	Reasons  []string     `json:"reasons"`
+}
+
+// Assess scores a transfer of amount made at, given the sender's history.
+func (r *RiskRules) Assess(amount float64, at time.Time, history TransferHistory) RiskAssessment {
+	assessment := RiskAssessment{Decision: RiskAllow}
+	hit := func(score int, reason string) {
+		assessment.Score += score
+		assessment.Reasons = append(assessment.Reasons, reason)
+	}
+
+	if v := r.Velocity; v != nil && history.Recent >= int64(v.MaxTransfers) {
+		hit(v.Score, fmt.Sprintf("more than %d transfers in %d minutes", v.MaxTransfers, v.WindowMinutes))
+	}
+	if n := r.NewRecipient; n != nil && !history.KnownRecipient && amount >= n.MinAmount {
+		hit(n.Score, fmt.Sprintf("%.2f to a new recipient", amount))
+	}
+	if h := r.UnusualHour; h != nil && h.covers(at.UTC().Hour()) {
+		hit(h.Score, fmt.Sprintf("made at %02d:00 UTC", at.UTC().Hour()))
+	}
+	if s := r.AmountSpike; s != nil && history.Count >= int64(s.MinHistory) && amount > history.Average*s.Multiplier {
+		hit(s.Score, fmt.Sprintf("%.2f is over %g times the average of %.2f", amount, s.Multiplier, history.Average))
+	}
+
+	switch {
+	case assessment.Score >= r.BlockScore:
+		assessment.Decision = RiskBlock
+	case assessment.Score >= r.ReviewScore:
+		assessment.Decision = RiskReview
+	}
+	return assessment
+}
+
+func (h *UnusualHourRule) covers(hour int) bool {
+	if h.From < h.To {
+		return hour >= h.From && hour < h.To
+	}
+	return hour >= h.From || hour < h.To
+}
+
+// RiskRulesSource provides the risk rules in force, which may change while
+// the application runs. It returns nil when transfers are not scored.
+type RiskRulesSource interface {
+	RiskRules() *RiskRules
+}
@@ -0,0 +1,273 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// SanctionsEntry is a person or organization on a sanctions list.
+type SanctionsEntry struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Type     string   `json:"type,omitempty"`     // e.g. Individual, Entity, Vessel
+	Programs []string `json:"programs,omitempty"` // The sanctions programs that list it
+}
+
+// SanctionsList is a sanctions list prepared for matching. Names are compared
+// when they share at least two trigrams (three letters in a row, counting the
+// start and end of words), which keeps a screening to a few hundred
+// comparisons on lists of tens of thousands of names while still finding the
+// transliterations that differ from the first letter, like "Iusuf" and
+// "Yusuf" or "Chekhov" and "Tchekhov".
+type SanctionsList struct {
+	Entries []SanctionsEntry
+	// names holds every name and alias, normalized; byTrigram indexes them by
+	// the trigrams of their words.
+	names     []listedName
+	byTrigram map[string][]int
+}
+
+// minSharedTrigrams is how many trigrams a listed name shares with a screened
+// one to be compared with it. Names of a single short word need only one.
+const minSharedTrigrams = 2
+
+type listedName struct {
+	entry      int
+	normalized string
+}
+
+// NewSanctionsList prepares entries for matching.
+func NewSanctionsList(entries []SanctionsEntry) *SanctionsList {
+	list := &SanctionsList{Entries: entries, byTrigram: make(map[string][]int)}
+	for i := range entries {
+		for _, name := range append([]string{entries[i].Name}, entries[i].Aliases...) {
+			normalized := NormalizePersonName(name)
+			if normalized == "" {
+				continue
+			}
+			list.names = append(list.names, listedName{entry: i, normalized: normalized})
+			for _, trigram := range nameTrigrams(normalized) {
+				list.byTrigram[trigram] = append(list.byTrigram[trigram], len(list.names)-1)
+			}
+		}
+	}
+	return list
+}
+
+// SanctionsMatch is an entry whose name or one of its aliases is similar to
+// a screened name.
+type SanctionsMatch struct {
+	Entry *SanctionsEntry
+	// Score is the similarity of the closest name, from 0 to 1 (identical).
+	Score float64
+}
+
+// Match returns the entries with a name at least threshold similar to name,
+// best first, once per entry.
+func (l *SanctionsList) Match(name string, threshold float64) []SanctionsMatch {
+	normalized := NormalizePersonName(name)
+	trigrams := nameTrigrams(normalized)
+	shared := make(map[int]int)
+	for _, trigram := range trigrams {
+		for _, i := range l.byTrigram[trigram] {
+			shared[i]++
+		}
+	}
+
+	best := make(map[int]float64)
+	for i, count := range shared {
+		if count < min(minSharedTrigrams, len(trigrams)) {
+			continue
+		}
+		listed := l.names[i]
+		if score := NameSimilarity(normalized, listed.normalized); score >= threshold && score > best[listed.entry] {
+			best[listed.entry] = score
+		}
+	}
+
+	matches := make([]SanctionsMatch, 0, len(best))
+	for entry, score := range best {
+		matches = append(matches, SanctionsMatch{Entry: &l.Entries[entry], Score: score})
+	}
+	slices.SortFunc(matches, func(a, b SanctionsMatch) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Entry.ID, b.Entry.ID)
+	})
+	return matches
+}
+
+// SanctionsListSource provides the sanctions list in force, which may change
+// while the application runs. It returns nil when there is no list.
+type SanctionsListSource interface {
+	SanctionsList() *SanctionsList
+}
+
+var stripMarks = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// NormalizePersonName returns name in lower case, without accents or
+// punctuation, with its words sorted, so that "SMITH, John" and "John Smith"
+// compare equal.
+func NormalizePersonName(name string) string {
+	stripped, _, err := transform.String(stripMarks, name)
+	if err != nil {
+		stripped = name
+	}
+	words := strings.FieldsFunc(strings.ToLower(stripped), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	slices.Sort(words)
+	return strings.Join(words, " ")
+}
+
+// nameTrigrams returns the distinct trigrams of the words of a normalized
+// name, each word padded with a space on both sides so that its first and
+// last letters count.
+func nameTrigrams(normalized string) []string {
+	var trigrams []string
+	for _, word := range strings.Fields(normalized) {
+		r := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			if trigram := string(r[i : i+3]); !slices.Contains(trigrams, trigram) {
+				trigrams = append(trigrams, trigram)
+			}
+		}
+	}
+	return trigrams
+}
+
+// NameSimilarity is the Jaro-Winkler similarity of two normalized names,
+// from 0 (nothing in common) to 1 (identical).
+func NameSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	s, t := []rune(a), []rune(b)
+	if len(s) == 0 || len(t) == 0 {
+		return 0
+	}
+
+	window := max(max(len(s), len(t))/2-1, 0)
+	sMatched, tMatched := make([]bool, len(s)), make([]bool, len(t))
+	matches := 0
+	for i := range s {
+		for j := max(0, i-window); j < min(len(t), i+window+1); j++ {
+			if !tMatched[j] && s[i] == t[j] {
+				sMatched[i], tMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range s {
+		if !sMatched[i] {
+			continue
+		}
+		for !tMatched[j] {
+			j++
+		}
+		if s[i] != t[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s)) + m/float64(len(t)) + (m-float64(transpositions/2))/m) / 3
+	prefix := 0
+	for prefix < min(4, len(s), len(t)) && s[prefix] == t[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// ScreeningStatus is where a user stands with the sanctions screening.
+type ScreeningStatus string
+
+const (
+	ScreeningClear   ScreeningStatus = "clear"
+	ScreeningFlagged ScreeningStatus = "flagged" // A hit awaits review
+	ScreeningBlocked ScreeningStatus = "blocked" // A hit was confirmed
+)
+
+// ScreeningHitStatus is the state of a hit. Hits start pending, or confirmed
+// when the match is close enough, and are confirmed or cleared by operators.
+type ScreeningHitStatus string
+
+const (
+	HitPending   ScreeningHitStatus = "pending"
+	HitConfirmed ScreeningHitStatus = "confirmed"
+	HitCleared   ScreeningHitStatus = "cleared" // A false positive
+)
+
+// ScreeningSource tells what a user was screened for.
+type ScreeningSource string
+
+const (
+	ScreenedOnCreation ScreeningSource = "user_creation"
+	ScreenedOnTransfer ScreeningSource = "transfer"
+)
+
+// ScreeningHit records that a user's name matched a sanctions list entry. A
+// user has at most one hit per entry, so a cleared hit isn't raised again.
+type ScreeningHit struct {
+	ID           string             `json:"id" gorm:"type:uuid;primary_key"`
+	UserID       string             `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_screening_hits_user_entry"`
+	ScreenedName string             `json:"screened_name" gorm:"type:varchar(255);not null"`
+	EntryID      string             `json:"entry_id" gorm:"type:varchar(64);not null;uniqueIndex:idx_screening_hits_user_entry"`
+	EntryName    string             `json:"entry_name" gorm:"type:text;not null"`
+	Programs     string             `json:"programs" gorm:"type:text;not null;default:''"` // Separated by ", "
+	Score        float64            `json:"score" gorm:"type:decimal(5,4);not null"`
+	Source       ScreeningSource    `json:"source" gorm:"type:varchar(16);not null"`
+	Status       ScreeningHitStatus `json:"status" gorm:"type:varchar(16);not null;index"`
+	ReviewedBy   string             `json:"reviewed_by,omitempty" gorm:"type:varchar(255);not null;default:''"`
+	ReviewedAt   *time.Time         `json:"reviewed_at,omitempty"`
+	Note         string             `json:"note,omitempty" gorm:"type:text;not null;default:''"`
+	CreatedAt    time.Time          `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Resolve confirms or clears a pending hit, reviewed by actor at now. It
+// returns ErrHitNotPending when the hit was already resolved.
+func (h *ScreeningHit) Resolve(status ScreeningHitStatus, actor, note string, now time.Time) error {
+	if h.Status != HitPending {
+		return fmt.Errorf("%w: it is %s", ErrHitNotPending, h.Status)
+	}
+	h.Status = status
+	h.ReviewedBy = actor
+	h.ReviewedAt = &now
+	h.Note = note
+	return nil
+}
+
+// ScreeningStatusOf returns the status hits give a user: blocked by any
+// confirmed hit, flagged by any pending one.
+func ScreeningStatusOf(hits []ScreeningHit) ScreeningStatus {
+	status := ScreeningClear
+	for _, hit := range hits {
+		switch hit.Status {
+		case HitConfirmed:
+			return ScreeningBlocked
+		case HitPending:
+			status = ScreeningFlagged
+		}
+	}
+	return status
+}
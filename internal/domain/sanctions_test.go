@@ -0,0 +1,30 @@
+package domain
+
+import "testing"
+
+// Transliterations of a listed name are matched even when they differ from
+// the first letter.
+func TestSanctionsListMatchesTransliterations(t *testing.T) {
+	list := NewSanctionsList([]SanctionsEntry{
+		{ID: "1", Name: "Muhammad"},
+		{ID: "2", Name: "Yusuf"},
+		{ID: "3", Name: "Chekhov"},
+		{ID: "4", Name: "Ali, Muhammad"},
+		{ID: "5", Name: "Smith, John"},
+	})
+
+	for name, want := range map[string]string{
+		"Mohammed":     "1",
+		"Iusuf":        "2",
+		"Tchekhov":     "3",
+		"Mohammed Ali": "4",
+	} {
+		matches := list.Match(name, 0.85)
+		if len(matches) == 0 || matches[0].Entry.ID != want {
+			t.Errorf("Match(%q) = %+v, want entry %s first", name, matches, want)
+		}
+	}
+	if matches := list.Match("Jane Doe", 0.85); len(matches) != 0 {
+		t.Errorf("Match(%q) = %+v, want no match", "Jane Doe", matches)
+	}
+}
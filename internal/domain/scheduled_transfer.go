@@ -0,0 +1,81 @@
+package domain
+
+import "time"
+
+// Recurrence tells when a scheduled transfer runs.
+type Recurrence string
+
+const (
+	// RecurrenceOnce runs once, at StartAt.
+	RecurrenceOnce Recurrence = "once"
+	// RecurrenceDaily runs every day at the time of day of StartAt.
+	RecurrenceDaily Recurrence = "daily"
+	// RecurrenceWeekly runs every week on the weekday and at the time of StartAt.
+	RecurrenceWeekly Recurrence = "weekly"
+	// RecurrenceMonthly runs every month on DayOfMonth at the time of day of
+	// StartAt, or on the last day of shorter months.
+	RecurrenceMonthly Recurrence = "monthly"
+	// RecurrenceCron runs at the times of a standard five-field cron expression.
+	RecurrenceCron Recurrence = "cron"
+)
+
+// ScheduleStatus tells whether a scheduled transfer still runs.
+type ScheduleStatus string
+
+const (
+	ScheduleActive ScheduleStatus = "active"
+	SchedulePaused ScheduleStatus = "paused"
+	// ScheduleCompleted schedules have no run left.
+	ScheduleCompleted ScheduleStatus = "completed"
+	ScheduleCancelled ScheduleStatus = "cancelled"
+)
+
+// ScheduledTransfer is a transfer made at a future time or on a recurrence.
+// All times are UTC.
+type ScheduledTransfer struct {
+	ID           string         `json:"id" gorm:"type:uuid;primary_key"`
+	FromWalletID string         `json:"from_wallet_id" gorm:"type:uuid;not null;index"`
+	ToWalletID   string         `json:"to_wallet_id" gorm:"type:uuid;not null"`
+	Amount       float64        `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Recurrence   Recurrence     `json:"recurrence" gorm:"type:varchar(16);not null"`
+	DayOfMonth   int            `json:"day_of_month,omitempty" gorm:"type:integer;not null;default:0"` // Monthly schedules only
+	CronExpr     string         `json:"cron,omitempty" gorm:"type:varchar(255);not null;default:''"`   // Cron schedules only
+	StartAt      time.Time      `json:"start_at" gorm:"type:timestamptz;not null"`
+	EndAt        *time.Time     `json:"end_at,omitempty" gorm:"type:timestamptz"`
+	Status       ScheduleStatus `json:"status" gorm:"type:varchar(16);not null"`
+	// NextRunAt is when the transfer runs next; nil once the schedule is over.
+	NextRunAt *time.Time `json:"next_run_at,omitempty" gorm:"type:timestamptz;index:idx_scheduled_transfers_due,where:status = 'active'"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty" gorm:"type:timestamptz"`
+	Actor     string     `json:"actor" gorm:"type:varchar(255);not null"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// IsOver reports whether the schedule will never run again.
+func (s *ScheduledTransfer) IsOver() bool {
+	return s.Status == ScheduleCompleted || s.Status == ScheduleCancelled
+}
+
+// ExecutionStatus is the outcome of one run of a scheduled transfer.
+type ExecutionStatus string
+
+const (
+	ExecutionSucceeded ExecutionStatus = "succeeded"
+	ExecutionFailed    ExecutionStatus = "failed"
+	// ExecutionHeld transfers wait for the review in ReviewID, which decides
+	// whether they are made.
+	ExecutionHeld ExecutionStatus = "held"
+)
+
+// ScheduledTransferExecution records one run of a scheduled transfer.
+type ScheduledTransferExecution struct {
+	ID         string `json:"id" gorm:"type:uuid;primary_key"`
+	ScheduleID string `json:"schedule_id" gorm:"type:uuid;not null;uniqueIndex:idx_scheduled_transfer_executions_occurrence,priority:1"`
+	// ScheduledFor is the occurrence that ran. Each occurrence runs at most once.
+	ScheduledFor time.Time       `json:"scheduled_for" gorm:"type:timestamptz;not null;uniqueIndex:idx_scheduled_transfer_executions_occurrence,priority:2"`
+	Status       ExecutionStatus `json:"status" gorm:"type:varchar(16);not null"`
+	Amount       float64         `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Error        string          `json:"error,omitempty" gorm:"type:text;not null;default:''"`
+	ReviewID     *string         `json:"review_id,omitempty" gorm:"type:uuid"`
+	ExecutedAt   time.Time       `json:"executed_at" gorm:"type:timestamptz;not null"`
+}
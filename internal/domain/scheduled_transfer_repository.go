@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ScheduledTransferRepository stores scheduled transfers and their executions.
+type ScheduledTransferRepository interface {
+	Save(ctx context.Context, schedule *ScheduledTransfer) error
+	FindByID(ctx context.Context, id string) (*ScheduledTransfer, error)
+	// FindByIDForUpdate is FindByID locking the schedule until the
+	// transaction of ctx ends.
+	FindByIDForUpdate(ctx context.Context, id string) (*ScheduledTransfer, error)
+	// ListByWallet returns the schedules sending money from a wallet, newest first.
+	ListByWallet(ctx context.Context, walletID string) ([]ScheduledTransfer, error)
+	Update(ctx context.Context, schedule *ScheduledTransfer) error
+
+	// ClaimDue locks the active schedule that has been due the longest (by
+	// now) until the transaction of ctx ends, skipping schedules locked by
+	// other instances. It returns nil when none is due.
+	ClaimDue(ctx context.Context, now time.Time) (*ScheduledTransfer, error)
+
+	SaveExecution(ctx context.Context, execution *ScheduledTransferExecution) error
+	// ListExecutions returns the latest executions of a schedule, newest first.
+	ListExecutions(ctx context.Context, scheduleID string, limit int) ([]ScheduledTransferExecution, error)
+}
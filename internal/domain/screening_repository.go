@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+// ScreeningRepository stores sanctions screening hits.
+type ScreeningRepository interface {
+	// SaveHits records new hits. A user's hit on an entry already recorded is
+	// left as it is, so a cleared hit stays cleared.
+	SaveHits(ctx context.Context, hits []ScreeningHit) error
+	// ListByUsers returns every hit of the users.
+	ListByUsers(ctx context.Context, userIDs []string) ([]ScreeningHit, error)
+	FindByID(ctx context.Context, id string) (*ScreeningHit, error)
+	// FindByIDForUpdate locks the hit until the end of the transaction, so
+	// concurrent decisions on it run one after the other.
+	FindByIDForUpdate(ctx context.Context, id string) (*ScreeningHit, error)
+	// List returns the hits in status, oldest first; all of them when status
+	// is empty.
+	List(ctx context.Context, status ScreeningHitStatus) ([]ScreeningHit, error)
+	// Resolve saves the decision on a hit that was pending. It returns
+	// ErrHitNotPending if the stored hit no longer is.
+	Resolve(ctx context.Context, hit *ScreeningHit) error
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Statement describes the account statement of a wallet over [From, To).
+// Its movements are rendered one at a time, so statements of any length can
+// be streamed.
+type Statement struct {
+	ID             string
+	WalletID       string
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance float64
+	GeneratedAt    time.Time
+}
+
+// StatementRenderer writes a statement in some format.
+type StatementRenderer interface {
+	Begin(statement *Statement) error
+	// Movement writes one movement; its BalanceAfter is the running balance.
+	Movement(movement *Movement) error
+	End(closingBalance float64) error
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TransferBatchMode tells what happens to a batch when one of its transfers fails.
+type TransferBatchMode string
+
+const (
+	// TransferBatchAtomic batches apply every transfer or none.
+	TransferBatchAtomic TransferBatchMode = "atomic"
+	// TransferBatchBestEffort batches apply every transfer that succeeds on its own.
+	TransferBatchBestEffort TransferBatchMode = "best_effort"
+)
+
+// TransferBatchStatus is the progress of a batch.
+type TransferBatchStatus string
+
+const (
+	TransferBatchPending    TransferBatchStatus = "pending"
+	TransferBatchProcessing TransferBatchStatus = "processing"
+	// TransferBatchCompleted batches applied every transfer.
+	TransferBatchCompleted TransferBatchStatus = "completed"
+	// TransferBatchPartiallyCompleted batches applied some transfers only, or
+	// had some held for review.
+	TransferBatchPartiallyCompleted TransferBatchStatus = "partially_completed"
+	// TransferBatchFailed batches applied no transfer and had none held.
+	TransferBatchFailed TransferBatchStatus = "failed"
+)
+
+// TransferBatch is a set of transfers from one wallet, submitted together and
+// processed in the background.
+type TransferBatch struct {
+	ID           string              `json:"id" gorm:"type:uuid;primary_key"`
+	FromWalletID string              `json:"from_wallet_id" gorm:"type:uuid;not null"`
+	Mode         TransferBatchMode   `json:"mode" gorm:"type:varchar(16);not null"`
+	Status       TransferBatchStatus `json:"status" gorm:"type:varchar(32);not null;index:idx_transfer_batches_status_created_at,priority:1"`
+	Actor        string              `json:"actor" gorm:"type:varchar(255);not null"`
+	Total        int                 `json:"total" gorm:"type:integer;not null"`
+	Succeeded    int                 `json:"succeeded" gorm:"type:integer;not null;default:0"`
+	Failed       int                 `json:"failed" gorm:"type:integer;not null;default:0"`
+	Held         int                 `json:"held" gorm:"type:integer;not null;default:0"`
+	// ClaimedAt is when a worker last reported progress on the batch. A
+	// processing batch whose claim is too old is picked up by another worker.
+	ClaimedAt  *time.Time `json:"-" gorm:"type:timestamptz"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime;index:idx_transfer_batches_status_created_at,priority:2"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" gorm:"type:timestamptz"`
+}
+
+// IsFinished reports whether the batch has been processed.
+func (b *TransferBatch) IsFinished() bool {
+	return b.Status != TransferBatchPending && b.Status != TransferBatchProcessing
+}
+
+// TransferBatchItemStatus is the outcome of one transfer of a batch.
+type TransferBatchItemStatus string
+
+const (
+	TransferItemPending   TransferBatchItemStatus = "pending"
+	TransferItemSucceeded TransferBatchItemStatus = "succeeded"
+	TransferItemFailed    TransferBatchItemStatus = "failed"
+	// TransferItemSkipped transfers of an atomic batch were rolled back
+	// because another transfer of the batch failed or was held.
+	TransferItemSkipped TransferBatchItemStatus = "skipped"
+	// TransferItemHeld transfers wait for the review in ReviewID, which
+	// decides whether they are made.
+	TransferItemHeld TransferBatchItemStatus = "held"
+)
+
+// TransferBatchItem is one transfer of a batch.
+type TransferBatchItem struct {
+	ID           string                  `json:"id" gorm:"type:uuid;primary_key"`
+	BatchID      string                  `json:"-" gorm:"type:uuid;not null;uniqueIndex:idx_transfer_batch_items_batch_id_position,priority:1"`
+	Position     int                     `json:"position" gorm:"type:integer;not null;uniqueIndex:idx_transfer_batch_items_batch_id_position,priority:2"` // 1-based, in submission order
+	FromWalletID string                  `json:"from_wallet_id" gorm:"type:uuid;not null"`
+	ToWalletID   string                  `json:"to_wallet_id" gorm:"type:uuid;not null"`
+	Amount       float64                 `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Status       TransferBatchItemStatus `json:"status" gorm:"type:varchar(16);not null"`
+	Error        string                  `json:"error,omitempty" gorm:"type:text;not null;default:''"`
+	ReviewID     *string                 `json:"review_id,omitempty" gorm:"type:uuid"`
+	UpdatedAt    time.Time               `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TransferBatchItemError is a transfer rejected when its batch was validated.
+type TransferBatchItemError struct {
+	Position int    `json:"position"`
+	Error    string `json:"error"`
+}
+
+// TransferBatchValidationError lists every transfer of a batch that can't be
+// submitted. Batches are validated as a whole before anything is stored.
+type TransferBatchValidationError struct {
+	Items []TransferBatchItemError
+}
+
+func (e *TransferBatchValidationError) Error() string {
+	messages := make([]string, len(e.Items))
+	for i, item := range e.Items {
+		messages[i] = fmt.Sprintf("transfer %d: %s", item.Position, item.Error)
+	}
+	return "invalid transfer batch: " + strings.Join(messages, "; ")
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TransferBatchRepository stores transfer batches and hands them out to workers.
+type TransferBatchRepository interface {
+	// Save stores a new batch with its items.
+	Save(ctx context.Context, batch *TransferBatch, items []TransferBatchItem) error
+	FindByID(ctx context.Context, id string) (*TransferBatch, error)
+	ListItems(ctx context.Context, batchID string) ([]TransferBatchItem, error)
+
+	// Claim marks the oldest pending batch, or a processing one not claimed
+	// since staleBefore, as processing and returns it. It returns nil when there
+	// is nothing to do. Concurrent workers never claim the same batch.
+	Claim(ctx context.Context, staleBefore time.Time) (*TransferBatch, error)
+	// Touch renews the claim on a batch being processed.
+	Touch(ctx context.Context, batchID string) error
+	// UpdateItem stores the outcome of a pending item. It returns
+	// ErrTransferBatchItemProcessed if the item is no longer pending.
+	UpdateItem(ctx context.Context, item *TransferBatchItem) error
+	// Finish stores the final status and counters of a batch.
+	Finish(ctx context.Context, batch *TransferBatch) error
+}
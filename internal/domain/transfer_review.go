@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TransferReviewStatus is the state of a held transfer. Reviews start pending
+// and end in exactly one of the other states.
+type TransferReviewStatus string
+
+const (
+	ReviewPending  TransferReviewStatus = "pending"
+	ReviewApproved TransferReviewStatus = "approved" // The transfer was made
+	ReviewRejected TransferReviewStatus = "rejected"
+	// ReviewFailed: the transfer was approved but could not be made, e.g. the
+	// sender no longer had the funds.
+	ReviewFailed TransferReviewStatus = "failed"
+)
+
+// TransferReview is a transfer the risk rules held for an operator to
+// approve or reject. No money moves until it is approved.
+type TransferReview struct {
+	ID           string               `json:"id" gorm:"type:uuid;primary_key"`
+	FromWalletID string               `json:"from_wallet_id" gorm:"type:uuid;not null;index"`
+	ToWalletID   string               `json:"to_wallet_id" gorm:"type:uuid;not null"`
+	Amount       float64              `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Score        int                  `json:"score" gorm:"type:integer;not null"`
+	Reasons      string               `json:"reasons" gorm:"type:text;not null"` // The rules hit, separated by "; "
+	Status       TransferReviewStatus `json:"status" gorm:"type:varchar(16);not null;index"`
+	Actor        string               `json:"actor" gorm:"type:varchar(255);not null"` // Who asked for the transfer
+	ReviewedBy   string               `json:"reviewed_by,omitempty" gorm:"type:varchar(255);not null;default:''"`
+	ReviewedAt   *time.Time           `json:"reviewed_at,omitempty"`
+	Note         string               `json:"note,omitempty" gorm:"type:text;not null;default:''"` // The reviewer's reason, or why the transfer failed
+	CreatedAt    time.Time            `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time            `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Resolve moves a pending review to status, reviewed by actor at now. It
+// returns ErrReviewNotPending when the review was already resolved.
+func (r *TransferReview) Resolve(status TransferReviewStatus, actor, note string, now time.Time) error {
+	if r.Status != ReviewPending {
+		return fmt.Errorf("%w: it is %s", ErrReviewNotPending, r.Status)
+	}
+	r.Status = status
+	r.ReviewedBy = actor
+	r.ReviewedAt = &now
+	r.Note = note
+	return nil
+}
+
+// TransferHeldError is returned by a transfer the risk rules held for review.
+// It matches ErrTransferHeld. The review is saved with the transaction the
+// transfer ran in, so an operation that makes the transfer within its own
+// transaction must commit it to keep the review.
+type TransferHeldError struct {
+	ReviewID string
+	Score    int
+	Review   *TransferReview
+}
+
+func (e *TransferHeldError) Error() string {
+	return fmt.Sprintf("%s (review %s)", ErrTransferHeld, e.ReviewID)
+}
+
+func (e *TransferHeldError) Unwrap() error { return ErrTransferHeld }
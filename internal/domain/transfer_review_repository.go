@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TransferReviewRepository stores held transfers and reads the transfer
+// history the risk rules score against.
+type TransferReviewRepository interface {
+	Save(ctx context.Context, review *TransferReview) error
+	FindByID(ctx context.Context, id string) (*TransferReview, error)
+	// FindByIDForUpdate locks the review until the end of the transaction, so
+	// concurrent decisions on it run one after the other.
+	FindByIDForUpdate(ctx context.Context, id string) (*TransferReview, error)
+	// List returns the reviews in status, oldest first; all of them when
+	// status is empty.
+	List(ctx context.Context, status TransferReviewStatus) ([]TransferReview, error)
+	// Resolve saves the decision on a review that was pending. It returns
+	// ErrReviewNotPending if the stored review no longer is.
+	Resolve(ctx context.Context, review *TransferReview) error
+
+	// TransferHistory describes the transfers out of walletID: those since
+	// recentSince, those since historySince, and whether any ever went to
+	// toWalletID.
+	TransferHistory(ctx context.Context, walletID, toWalletID string, recentSince, historySince time.Time) (*TransferHistory, error)
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 // TxnRepository defines the contract for transaction management.
 type TxnRepository interface {
@@ -9,3 +12,48 @@ type TxnRepository interface {
 	// Otherwise, the transaction is committed.
 	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
+
+type txScopeKey struct{}
+
+// txScope tracks the work that must wait for the enclosing transaction to commit.
+type txScope struct {
+	mu          sync.Mutex
+	afterCommit []func(context.Context)
+}
+
+// NewTransactionContext marks ctx as running inside a transaction. The returned
+// commit function must be called by the TxnRepository once the transaction has
+// committed; it runs the callbacks registered with AfterCommit.
+func NewTransactionContext(ctx context.Context) (context.Context, func(context.Context)) {
+	scope := &txScope{}
+	commit := func(ctx context.Context) {
+		scope.mu.Lock()
+		callbacks := scope.afterCommit
+		scope.afterCommit = nil
+		scope.mu.Unlock()
+
+		for _, fn := range callbacks {
+			fn(ctx)
+		}
+	}
+	return context.WithValue(ctx, txScopeKey{}, scope), commit
+}
+
+// InTransaction reports whether ctx belongs to a WithTransaction callback.
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txScopeKey{}).(*txScope)
+	return ok
+}
+
+// AfterCommit runs fn once the transaction ctx belongs to has committed, or
+// right away when ctx is not in a transaction. fn never runs on rollback.
+func AfterCommit(ctx context.Context, fn func(context.Context)) {
+	scope, ok := ctx.Value(txScopeKey{}).(*txScope)
+	if !ok {
+		fn(ctx)
+		return
+	}
+	scope.mu.Lock()
+	scope.afterCommit = append(scope.afterCommit, fn)
+	scope.mu.Unlock()
+}
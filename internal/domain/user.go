@@ -2,11 +2,15 @@ package domain
 
 import "time"
 
+// DefaultUserTier is the tier users start in. Tiers select the fees a user pays.
+const DefaultUserTier = "standard"
+
 type User struct {
 	ID        string    `gorm:"type:uuid;primary_key"`
 	Username  string    `gorm:"type:varchar(255);unique;not null"`
 	Name      string    `gorm:"type:varchar(255);not null"`
 	DNI       string    `gorm:"type:varchar(255);unique;not null"`
+	Tier      string    `gorm:"type:varchar(32);not null;default:'standard'"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 }
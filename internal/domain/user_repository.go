@@ -7,4 +7,10 @@ type UserRepository interface {
 	Save(ctx context.Context, user *User) error
 	FindByID(ctx context.Context, id string) (*User, error)
 	FindByUsername(ctx context.Context, username string) (*User, error)
+	// List returns up to limit users ordered by ID, starting after afterID
+	// (empty for the first page).
+	List(ctx context.Context, afterID string, limit int) ([]User, error)
+	// UpdateTier changes the tier of a user. It returns ErrUserNotFound when
+	// there is no such user.
+	UpdateTier(ctx context.Context, id, tier string) error
 }
@@ -8,13 +8,44 @@ const (
 	DefaultBalance  = 0.0
 )
 
+// WalletStatus tells whether money can move in and out of a wallet.
+type WalletStatus string
+
+const (
+	WalletActive WalletStatus = "active"
+	// WalletFrozen wallets can't be recharged, send or receive transfers. Only
+	// operator adjustments still apply.
+	WalletFrozen WalletStatus = "frozen"
+)
+
 type Wallet struct {
-	ID        string    `json:"id" gorm:"type:uuid;primary_key"`
-	UserID    string    `json:"user_id" gorm:"type:uuid;not null;index"` // A wallet belongs to a User
-	Currency  string    `json:"currency" gorm:"type:varchar(3);not null;default:'USD'"`
-	Balance   float64   `json:"balance" gorm:"type:decimal(15,2);not null;default:0"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID             string       `json:"id" gorm:"type:uuid;primary_key"`
+	UserID         string       `json:"user_id" gorm:"type:uuid;not null;index"` // A wallet belongs to a User
+	Currency       string       `json:"currency" gorm:"type:varchar(3);not null;default:'USD'"`
+	Balance        float64      `json:"balance" gorm:"type:decimal(15,2);not null;default:0"`         // Includes the money set aside in pockets
+	Pocketed       float64      `json:"pocketed" gorm:"type:decimal(15,2);not null;default:0"`        // Sum of the balances of the wallet's pockets
+	OverdraftLimit float64      `json:"overdraft_limit" gorm:"type:decimal(15,2);not null;default:0"` // The database enforces balance - pocketed >= -overdraft_limit
+	Status         WalletStatus `json:"status" gorm:"type:varchar(16);not null;default:'active'"`
+	Product        string       `json:"product" gorm:"type:varchar(32);not null;default:'current'"` // A WalletProduct code; selects the interest rate
+	Version        int64        `json:"version" gorm:"type:bigint;not null;default:0"`              // Incremented by every Update, see WalletRepository
+	CreatedAt      time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// IsFrozen reports whether the wallet is frozen.
+func (w *Wallet) IsFrozen() bool {
+	return w.Status == WalletFrozen
+}
+
+// AvailableBalance is the amount that can be moved out of the wallet,
+// including any overdraft it is allowed but not the money set aside in pockets.
+func (w *Wallet) AvailableBalance() float64 {
+	return w.MainBalance() + w.OverdraftLimit
+}
+
+// MainBalance is the money in the wallet outside of its pockets.
+func (w *Wallet) MainBalance() float64 {
+	return w.Balance - w.Pocketed
 }
 
 // NewWallet creates a new wallet with default values
@@ -23,5 +54,7 @@ func NewWallet(userID string) *Wallet {
 		UserID:   userID,
 		Currency: DefaultCurrency,
 		Balance:  DefaultBalance,
+		Status:   WalletActive,
+		Product:  ProductCurrent,
 	}
 }
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MemberRole is what a member can do with a shared wallet.
+type MemberRole string
+
+const (
+	// RoleOwner members can spend without limit and manage the members.
+	RoleOwner MemberRole = "owner"
+	// RoleSpender members can send up to their daily limit.
+	RoleSpender MemberRole = "spender"
+	// RoleViewer members can see the wallet and its movements only.
+	RoleViewer MemberRole = "viewer"
+)
+
+// MemberStatus tells whether an invitation was accepted.
+type MemberStatus string
+
+const (
+	MemberInvited MemberStatus = "invited"
+	MemberActive  MemberStatus = "active"
+)
+
+// WalletMember gives a user access to a wallet. The user a wallet was created
+// for (Wallet.UserID) is always one of its owners.
+type WalletMember struct {
+	ID       string     `json:"id" gorm:"type:uuid;primary_key"`
+	WalletID string     `json:"wallet_id" gorm:"type:uuid;not null;uniqueIndex:idx_wallet_members_wallet_user"`
+	UserID   string     `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_wallet_members_wallet_user;index"`
+	Role     MemberRole `json:"role" gorm:"type:varchar(16);not null"`
+	// DailyLimit is how much a spender can take out of the wallet per UTC
+	// day, fees included. Zero for owners and viewers.
+	DailyLimit float64      `json:"daily_limit" gorm:"type:decimal(15,2);not null;default:0"`
+	Status     MemberStatus `json:"status" gorm:"type:varchar(16);not null"`
+	InvitedBy  string       `json:"invited_by" gorm:"type:varchar(255);not null"` // Actor who sent the invitation
+	CreatedAt  time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Validate checks the role and the limit that goes with it.
+func (m *WalletMember) Validate() error {
+	switch m.Role {
+	case RoleSpender:
+		if m.DailyLimit <= 0 {
+			return fmt.Errorf("%w: spenders need a positive daily limit", ErrInvalidMember)
+		}
+	case RoleOwner, RoleViewer:
+		if m.DailyLimit != 0 {
+			return fmt.Errorf("%w: only spenders have a daily limit", ErrInvalidMember)
+		}
+	default:
+		return fmt.Errorf("%w: unknown role %q", ErrInvalidMember, m.Role)
+	}
+	return nil
+}
+
+// IsActive reports whether the member accepted the invitation.
+func (m *WalletMember) IsActive() bool {
+	return m.Status == MemberActive
+}
+
+// CanSpend reports whether the member can send money out of the wallet.
+func (m *WalletMember) CanSpend() bool {
+	return m.IsActive() && (m.Role == RoleOwner || m.Role == RoleSpender)
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+// WalletMemberRepository stores who has access to which wallet.
+type WalletMemberRepository interface {
+	// Save returns ErrAlreadyMember when the user is a member (or invited) already.
+	Save(ctx context.Context, member *WalletMember) error
+	// Find returns ErrMemberNotFound when the user is not a member of the wallet.
+	Find(ctx context.Context, walletID, userID string) (*WalletMember, error)
+	ListByWallet(ctx context.Context, walletID string) ([]WalletMember, error)
+	// Activate accepts a pending invitation. It returns ErrMemberNotFound when
+	// the user has none.
+	Activate(ctx context.Context, walletID, userID string) error
+	// Delete returns ErrMemberNotFound when the user is not a member of the wallet.
+	Delete(ctx context.Context, walletID, userID string) error
+}
@@ -7,5 +7,12 @@ type WalletRepository interface {
 	Save(ctx context.Context, wallet *Wallet) error
 	FindByID(ctx context.Context, id string) (*Wallet, error)
 	FindByUserID(ctx context.Context, userID string) (*Wallet, error)
+	// FindByUserIDAndCurrency returns the wallet a user holds in currency.
+	FindByUserIDAndCurrency(ctx context.Context, userID, currency string) (*Wallet, error)
+	// List returns up to limit wallets ordered by ID, starting after afterID
+	// (empty for the first page).
+	List(ctx context.Context, afterID string, limit int) ([]Wallet, error)
+	// Update saves wallet only if its Version still matches the stored one, and
+	// increments Version. It returns ErrConcurrentModification otherwise.
 	Update(ctx context.Context, wallet *Wallet) error
 }
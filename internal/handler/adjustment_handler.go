@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type AdjustmentHandler struct {
+	adjustmentUsecase usecase.AdjustmentUsecase
+	logger            *slog.Logger
+}
+
+func NewAdjustmentHandler(au usecase.AdjustmentUsecase, logger *slog.Logger) *AdjustmentHandler {
+	return &AdjustmentHandler{adjustmentUsecase: au, logger: logger}
+}
+
+type AdjustmentRequestRequest struct {
+	WalletID string `json:"wallet_id"`
+	// Amount is credited when positive and debited when negative.
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason"`
+}
+
+// @Summary Request a manual adjustment
+// @Description Proposes to credit (positive amount) or debit (negative amount) a wallet. Nothing moves until another operator approves it.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param adjustment body AdjustmentRequestRequest true "Adjustment to request"
+// @Success 201 {object} domain.AdjustmentRequest
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/adjustments [post]
+func (h *AdjustmentHandler) Request(c fiber.Ctx) error {
+	var req AdjustmentRequestRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
+
+	request, err := h.adjustmentUsecase.Request(c.Context(), req.WalletID, req.Amount, req.Reason)
+	if err != nil {
+		return h.fail(c, "failed to request adjustment", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(request)
+}
+
+// @Summary List adjustment requests
+// @Description Returns the manual adjustments requested, oldest first. Only operators can list them.
+// @Tags admin
+// @Produce json
+// @Param status query string false "pending, approved, rejected or failed; all when empty"
+// @Success 200 {array} domain.AdjustmentRequest
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/adjustments [get]
+func (h *AdjustmentHandler) List(c fiber.Ctx) error {
+	requests, err := h.adjustmentUsecase.List(c.Context(), domain.AdjustmentRequestStatus(c.Query("status")))
+	if err != nil {
+		return h.fail(c, "failed to list adjustment requests", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(requests)
+}
+
+// @Summary Get an adjustment request
+// @Description Returns an adjustment request, who requested and reviewed it, and its movement once applied.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Adjustment request ID"
+// @Success 200 {object} domain.AdjustmentRequest
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/adjustments/{id} [get]
+func (h *AdjustmentHandler) Get(c fiber.Ctx) error {
+	request, err := h.adjustmentUsecase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to get adjustment request", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(request)
+}
+
+// @Summary Approve an adjustment request
+// @Description Applies the adjustment. The operator who requested it can't approve it. If it can no longer be applied (e.g. a debit larger than the balance) the request ends failed, with the reason in its note.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Adjustment request ID"
+// @Param decision body ReviewDecisionRequest false "Why the adjustment is approved"
+// @Success 200 {object} domain.AdjustmentRequest
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/adjustments/{id}/approve [post]
+func (h *AdjustmentHandler) Approve(c fiber.Ctx) error {
+	return h.decide(c, "failed to approve adjustment request", h.adjustmentUsecase.Approve)
+}
+
+// @Summary Reject an adjustment request
+// @Description Rejects the adjustment; no money moves. The operator who requested it can't reject it.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Adjustment request ID"
+// @Param decision body ReviewDecisionRequest false "Why the adjustment is rejected"
+// @Success 200 {object} domain.AdjustmentRequest
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/adjustments/{id}/reject [post]
+func (h *AdjustmentHandler) Reject(c fiber.Ctx) error {
+	return h.decide(c, "failed to reject adjustment request", h.adjustmentUsecase.Reject)
+}
+
+func (h *AdjustmentHandler) decide(c fiber.Ctx, msg string, decide func(ctx context.Context, id, note string) (*domain.AdjustmentRequest, error)) error {
+	var req ReviewDecisionRequest
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+		}
+	}
+
+	request, err := decide(c.Context(), c.Params("id"), req.Note)
+	if err != nil {
+		return h.fail(c, msg, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(request)
+}
+
+func (h *AdjustmentHandler) fail(c fiber.Ctx, msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAdjustment):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrOperatorNotVerified):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrAdjustmentByOperator), errors.Is(err, domain.ErrSelfReview):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrAdjustmentNotFound), errors.Is(err, domain.ErrWalletNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrAdjustmentNotPending):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case isConflict(err):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "wallet is busy, please retry"})
+	}
+	h.logger.ErrorContext(c.Context(), msg, "error", err)
+	captureException(c.Context(), err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
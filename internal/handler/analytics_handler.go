@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type AnalyticsHandler struct {
+	analyticsUsecase usecase.AnalyticsUsecase
+	logger           *slog.Logger
+}
+
+func NewAnalyticsHandler(au usecase.AnalyticsUsecase, logger *slog.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsUsecase: au, logger: logger}
+}
+
+type CategorizeRequest struct {
+	Category string `json:"category"`
+}
+
+// @Summary Get spending insights
+// @Description Sums up the movements of the wallets the user owns, by currency: money in and out every month, the counterparties most money was exchanged with, and the breakdown by category. Movements show up a few seconds after they happen.
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Param months query int false "Months covered, the current one included (1 to 24, default 6)"
+// @Success 200 {object} domain.UserInsights
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/insights [get]
+func (h *AnalyticsHandler) GetInsights(c fiber.Ctx) error {
+	months := 0
+	if raw := c.Query("months"); raw != "" {
+		var err error
+		if months, err = strconv.Atoi(raw); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "months must be a number"})
+		}
+	}
+
+	insights, err := h.analyticsUsecase.Insights(c.Context(), c.Params("id"), months)
+	if err != nil {
+		return h.fail(c, "failed to get insights", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(insights)
+}
+
+// @Summary Categorize a movement
+// @Description Files a movement under a category of the user's choice, e.g. "groceries", instead of the merchant's or the default one. Categories are stored in lower case, with spaces as underscores.
+// @Tags wallets
+// @Accept json
+// @Produce json
+// @Param id path string true "Movement ID"
+// @Param category body CategorizeRequest true "Category"
+// @Success 200 {object} domain.MovementCategory
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /movements/{id}/category [put]
+func (h *AnalyticsHandler) Categorize(c fiber.Ctx) error {
+	var req CategorizeRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
+
+	category, err := h.analyticsUsecase.Categorize(c.Context(), c.Params("id"), req.Category)
+	if err != nil {
+		return h.fail(c, "failed to categorize movement", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(category)
+}
+
+func (h *AnalyticsHandler) fail(c fiber.Ctx, msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCategory), errors.Is(err, domain.ErrInvalidInsightsRange):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrOtherUser), errors.Is(err, domain.ErrNotWalletMember):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrMovementNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrMovementNotCategorized):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	h.logger.ErrorContext(c.Context(), msg, "error", err)
+	captureException(c.Context(), err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"wallet/internal/health"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// @Summary Liveness probe
+// @Description Reports that the process is running. It never checks dependencies.
+// @Tags health
+// @Produce json
+// @Success 200 {object} fiber.Map
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": health.StatusUp})
+}
+
+// @Summary Readiness probe
+// @Description Reports whether the service can serve traffic, with the status and latency of each dependency.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c fiber.Ctx) error {
+	// Probes must not be tied to the request: a cancelled probe would poison the cached report.
+	report := h.checker.Readiness(context.WithoutCancel(c.Context()))
+	if !report.Ready() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(report)
+	}
+	return c.Status(fiber.StatusOK).JSON(report)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type InterestHandler struct {
+	interestUsecase usecase.InterestUsecase
+	logger          *slog.Logger
+}
+
+func NewInterestHandler(iu usecase.InterestUsecase, logger *slog.Logger) *InterestHandler {
+	return &InterestHandler{interestUsecase: iu, logger: logger}
+}
+
+// @Summary Get the interest of a wallet
+// @Description Returns the product and annual interest rate (in percent) of a wallet, and the interest accrued but not paid yet. Interest is paid at the start of each month.
+// @Tags wallets
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Success 200 {object} domain.InterestSummary
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id}/interest [get]
+func (h *InterestHandler) GetSummary(c fiber.Ctx) error {
+	summary, err := h.interestUsecase.Summary(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrNotWalletMember) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.logger.ErrorContext(c.Context(), "failed to get interest summary", "error", err)
+		captureException(c.Context(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+	return c.Status(fiber.StatusOK).JSON(summary)
+}
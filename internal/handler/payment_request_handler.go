@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type PaymentRequestHandler struct {
+	requestUsecase usecase.PaymentRequestUsecase
+	logger         *slog.Logger
+}
+
+func NewPaymentRequestHandler(pu usecase.PaymentRequestUsecase, logger *slog.Logger) *PaymentRequestHandler {
+	return &PaymentRequestHandler{requestUsecase: pu, logger: logger}
+}
+
+// @Summary Request a payment
+// @Description Asks the owner of the payer wallet to transfer an amount to the requester wallet. Both wallets must hold the same currency. The request expires at expires_at, by default after the configured time to live.
+// @Tags payment-requests
+// @Accept json
+// @Produce json
+// @Param request body usecase.PaymentRequestInput true "Payment request"
+// @Success 201 {object} domain.PaymentRequest
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /payment-requests [post]
+func (h *PaymentRequestHandler) Create(c fiber.Ctx) error {
+	var input usecase.PaymentRequestInput
+	if err := c.Bind().Body(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
+
+	request, err := h.requestUsecase.Create(c.Context(), input)
+	if err != nil {
+		return h.fail(c, "failed to create payment request", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(request)
+}
+
+// @Summary Get a payment request
+// @Tags payment-requests
+// @Produce json
+// @Param id path string true "Payment request ID"
+// @Success 200 {object} domain.PaymentRequest
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /payment-requests/{id} [get]
+func (h *PaymentRequestHandler) Get(c fiber.Ctx) error {
+	request, err := h.requestUsecase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to get payment request", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(request)
+}
+
+// @Summary Accept a payment request
+// @Description Pays a pending request with a transfer from the payer wallet to the requester wallet. A request is paid at most once.
+// @Tags payment-requests
+// @Produce json
+// @Param id path string true "Payment request ID"
+// @Success 200 {object} domain.PaymentRequest
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /payment-requests/{id}/accept [post]
+func (h *PaymentRequestHandler) Accept(c fiber.Ctx) error {
+	request, err := h.requestUsecase.Accept(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to accept payment request", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(request)
+}
+
+// @Summary Decline a payment request
+// @Tags payment-requests
+// @Produce json
+// @Param id path string true "Payment request ID"
+// @Success 200 {object} domain.PaymentRequest
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /payment-requests/{id}/decline [post]
+func (h *PaymentRequestHandler) Decline(c fiber.Ctx) error {
+	request, err := h.requestUsecase.Decline(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to decline payment request", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(request)
+}
+
+// @Summary List the open payment requests of a wallet
+// @Description Returns the pending requests the wallet sent or received, newest first.
+// @Tags payment-requests
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Success 200 {array} domain.PaymentRequest
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id}/payment-requests [get]
+func (h *PaymentRequestHandler) ListOpen(c fiber.Ctx) error {
+	requests, err := h.requestUsecase.ListOpen(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to list payment requests", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(requests)
+}
+
+func (h *PaymentRequestHandler) fail(c fiber.Ctx, msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPaymentRequest), errors.Is(err, domain.ErrInsufficientFunds):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrPaymentRequestNotFound), errors.Is(err, domain.ErrWalletNotFound),
+		strings.Contains(err.Error(), "not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrPaymentRequestNotPending), errors.Is(err, domain.ErrPaymentRequestExpired):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrSpendNotAllowed), errors.Is(err, domain.ErrNotWalletMember):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrWalletFrozen), errors.Is(err, domain.ErrSpendLimitExceeded),
+		errors.Is(err, domain.ErrTransferBlocked), errors.Is(err, domain.ErrSanctioned):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	case isConflict(err):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "wallet is busy, please retry"})
+	}
+	h.logger.ErrorContext(c.Context(), msg, "error", err)
+	captureException(c.Context(), err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type PocketHandler struct {
+	pocketUsecase usecase.PocketUsecase
+	logger        *slog.Logger
+}
+
+func NewPocketHandler(pu usecase.PocketUsecase, logger *slog.Logger) *PocketHandler {
+	return &PocketHandler{pocketUsecase: pu, logger: logger}
+}
+
+type PocketFundsRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+// @Summary Create a pocket
+// @Description Creates a pocket to set money aside inside the wallet, optionally towards a goal: a target amount, and a date to reach it by. Names are unique among the open pockets of a wallet.
+// @Tags pockets
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param pocket body usecase.PocketInput true "Pocket to create"
+// @Success 201 {object} domain.Pocket
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id}/pockets [post]
+func (h *PocketHandler) Create(c fiber.Ctx) error {
+	var input usecase.PocketInput
+	if err := c.Bind().Body(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
+
+	pocket, err := h.pocketUsecase.Create(c.Context(), c.Params("id"), input)
+	if err != nil {
+		return h.fail(c, "failed to create pocket", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(pocket)
+}
+
+// @Summary List the pockets of a wallet
+// @Description Returns the open pockets of the wallet and the progress towards their goals. Their balances are part of the wallet's balance.
+// @Tags pockets
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Success 200 {array} domain.PocketProgress
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id}/pockets [get]
+func (h *PocketHandler) List(c fiber.Ctx) error {
+	pockets, err := h.pocketUsecase.List(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to list pockets", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(pockets)
+}
+
+// @Summary Get a pocket
+// @Description Returns a pocket and the progress towards its goal.
+// @Tags pockets
+// @Produce json
+// @Param id path string true "Pocket ID"
+// @Success 200 {object} domain.PocketProgress
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /pockets/{id} [get]
+func (h *PocketHandler) Get(c fiber.Ctx) error {
+	pocket, err := h.pocketUsecase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to get pocket", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(pocket)
+}
+
+// @Summary List the movements of a pocket
+// @Tags pockets
+// @Produce json
+// @Param id path string true "Pocket ID"
+// @Success 200 {array} domain.PocketMovement
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /pockets/{id}/movements [get]
+func (h *PocketHandler) ListMovements(c fiber.Ctx) error {
+	movements, err := h.pocketUsecase.ListMovements(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to list pocket movements", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(movements)
+}
+
+// @Summary Move money into a pocket
+// @Description Moves an amount from the wallet's main balance into the pocket. The overdraft can't be used for it.
+// @Tags pockets
+// @Accept json
+// @Produce json
+// @Param id path string true "Pocket ID"
+// @Param funds body PocketFundsRequest true "Amount to move"
+// @Success 200 {object} domain.PocketProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /pockets/{id}/deposit [post]
+func (h *PocketHandler) Deposit(c fiber.Ctx) error {
+	var req PocketFundsRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
+
+	pocket, err := h.pocketUsecase.Deposit(c.Context(), c.Params("id"), req.Amount)
+	if err != nil {
+		return h.fail(c, "failed to move funds into pocket", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(pocket)
+}
+
+// @Summary Move money out of a pocket
+// @Description Moves an amount from the pocket back to the wallet's main balance.
+// @Tags pockets
+// @Accept json
+// @Produce json
+// @Param id path string true "Pocket ID"
+// @Param funds body PocketFundsRequest true "Amount to move"
+// @Success 200 {object} domain.PocketProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /pockets/{id}/withdraw [post]
+func (h *PocketHandler) Withdraw(c fiber.Ctx) error {
+	var req PocketFundsRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
+
+	pocket, err := h.pocketUsecase.Withdraw(c.Context(), c.Params("id"), req.Amount)
+	if err != nil {
+		return h.fail(c, "failed to move funds out of pocket", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(pocket)
+}
+
+// @Summary Close a pocket
+// @Description Moves what is left in the pocket back to the wallet's main balance and closes it.
+// @Tags pockets
+// @Param id path string true "Pocket ID"
+// @Success 204
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /pockets/{id} [delete]
+func (h *PocketHandler) Close(c fiber.Ctx) error {
+	if err := h.pocketUsecase.Close(c.Context(), c.Params("id")); err != nil {
+		return h.fail(c, "failed to close pocket", err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *PocketHandler) fail(c fiber.Ctx, msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPocket):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrNotWalletMember), errors.Is(err, domain.ErrSpendNotAllowed):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrPocketNotFound), errors.Is(err, domain.ErrWalletNotFound),
+		strings.Contains(err.Error(), "not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrPocketNameTaken), errors.Is(err, domain.ErrPocketClosed):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInsufficientFunds), errors.Is(err, domain.ErrWalletFrozen):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	case isConflict(err):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "wallet is busy, please retry"})
+	}
+	h.logger.ErrorContext(c.Context(), msg, "error", err)
+	captureException(c.Context(), err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
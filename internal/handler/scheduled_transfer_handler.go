@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// executionsLimit is how many executions of a schedule are listed.
+const executionsLimit = 100
+
+type ScheduledTransferHandler struct {
+	scheduleUsecase usecase.ScheduledTransferUsecase
+	logger          *slog.Logger
+}
+
+func NewScheduledTransferHandler(su usecase.ScheduledTransferUsecase, logger *slog.Logger) *ScheduledTransferHandler {
+	return &ScheduledTransferHandler{scheduleUsecase: su, logger: logger}
+}
+
+// @Summary Schedule a transfer
+// @Description Schedules a transfer once (at start_at) or on a recurrence: daily or weekly from start_at, monthly on day_of_month, or on a five-field cron expression (UTC).
+// @Tags scheduled-transfers
+// @Accept json
+// @Produce json
+// @Param schedule body usecase.ScheduleRequest true "Transfer and schedule"
+// @Success 201 {object} domain.ScheduledTransfer
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /scheduled-transfers [post]
+func (h *ScheduledTransferHandler) Create(c fiber.Ctx) error {
+	var req usecase.ScheduleRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
+
+	schedule, err := h.scheduleUsecase.Create(c.Context(), req)
+	if err != nil {
+		return h.fail(c, "failed to create scheduled transfer", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(schedule)
+}
+
+// @Summary Get a scheduled transfer
+// @Tags scheduled-transfers
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} domain.ScheduledTransfer
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /scheduled-transfers/{id} [get]
+func (h *ScheduledTransferHandler) Get(c fiber.Ctx) error {
+	schedule, err := h.scheduleUsecase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to get scheduled transfer", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(schedule)
+}
+
+// @Summary List the scheduled transfers of a wallet
+// @Description Returns the schedules sending money from the wallet, newest first.
+// @Tags scheduled-transfers
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Success 200 {array} domain.ScheduledTransfer
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id}/scheduled-transfers [get]
+func (h *ScheduledTransferHandler) ListByWallet(c fiber.Ctx) error {
+	schedules, err := h.scheduleUsecase.ListByWallet(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to list scheduled transfers", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(schedules)
+}
+
+// @Summary Update a scheduled transfer
+// @Description Changes the amount or end, or pauses (status paused) and resumes (status active) the schedule. Runs missed while paused are skipped.
+// @Tags scheduled-transfers
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param update body usecase.ScheduleUpdate true "Fields to change"
+// @Success 200 {object} domain.ScheduledTransfer
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /scheduled-transfers/{id} [patch]
+func (h *ScheduledTransferHandler) Update(c fiber.Ctx) error {
+	var update usecase.ScheduleUpdate
+	if err := c.Bind().Body(&update); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
+
+	schedule, err := h.scheduleUsecase.Update(c.Context(), c.Params("id"), update)
+	if err != nil {
+		return h.fail(c, "failed to update scheduled transfer", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(schedule)
+}
+
+// @Summary Cancel a scheduled transfer
+// @Tags scheduled-transfers
+// @Param id path string true "Schedule ID"
+// @Success 204
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /scheduled-transfers/{id} [delete]
+func (h *ScheduledTransferHandler) Cancel(c fiber.Ctx) error {
+	if err := h.scheduleUsecase.Cancel(c.Context(), c.Params("id")); err != nil {
+		return h.fail(c, "failed to cancel scheduled transfer", err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary List the executions of a scheduled transfer
+// @Description Returns the latest runs of the schedule and their outcome, newest first.
+// @Tags scheduled-transfers
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {array} domain.ScheduledTransferExecution
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /scheduled-transfers/{id}/executions [get]
+func (h *ScheduledTransferHandler) ListExecutions(c fiber.Ctx) error {
+	executions, err := h.scheduleUsecase.ListExecutions(c.Context(), c.Params("id"), executionsLimit)
+	if err != nil {
+		return h.fail(c, "failed to list scheduled transfer executions", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(executions)
+}
+
+func (h *ScheduledTransferHandler) fail(c fiber.Ctx, msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotWalletMember), errors.Is(err, domain.ErrSpendNotAllowed):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrScheduleNotFound), errors.Is(err, domain.ErrWalletNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidSchedule):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrSpendLimitExceeded):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrScheduleOver), isConflict(err):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	h.logger.ErrorContext(c.Context(), msg, "error", err)
+	captureException(c.Context(), err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type ScreeningHandler struct {
+	screeningUsecase usecase.ScreeningUsecase
+	logger           *slog.Logger
+}
+
+func NewScreeningHandler(su usecase.ScreeningUsecase, logger *slog.Logger) *ScreeningHandler {
+	return &ScreeningHandler{screeningUsecase: su, logger: logger}
+}
+
+// @Summary List sanctions screening hits
+// @Description Returns the users whose name matched the sanctions list, oldest first. Only operators can list them.
+// @Tags admin
+// @Produce json
+// @Param status query string false "pending, confirmed or cleared; all when empty"
+// @Success 200 {array} domain.ScreeningHit
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/screening-hits [get]
+func (h *ScreeningHandler) List(c fiber.Ctx) error {
+	hits, err := h.screeningUsecase.List(c.Context(), domain.ScreeningHitStatus(c.Query("status")))
+	if err != nil {
+		return h.fail(c, "failed to list screening hits", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(hits)
+}
+
+// @Summary Get a sanctions screening hit
+// @Description Returns a hit: the screened name, the list entry it matched and how closely.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Hit ID"
+// @Success 200 {object} domain.ScreeningHit
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/screening-hits/{id} [get]
+func (h *ScreeningHandler) Get(c fiber.Ctx) error {
+	hit, err := h.screeningUsecase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to get screening hit", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(hit)
+}
+
+// @Summary Confirm a sanctions screening hit
+// @Description Marks a pending hit as a true match: the user can no longer send or receive transfers.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Hit ID"
+// @Param decision body ReviewDecisionRequest false "Why the hit is confirmed"
+// @Success 200 {object} domain.ScreeningHit
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/screening-hits/{id}/confirm [post]
+func (h *ScreeningHandler) Confirm(c fiber.Ctx) error {
+	return h.decide(c, "failed to confirm screening hit", h.screeningUsecase.Confirm)
+}
+
+// @Summary Clear a sanctions screening hit
+// @Description Marks a pending hit as a false positive; the same entry won't be raised for the user again.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Hit ID"
+// @Param decision body ReviewDecisionRequest false "Why the hit is cleared"
+// @Success 200 {object} domain.ScreeningHit
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/screening-hits/{id}/clear [post]
+func (h *ScreeningHandler) Clear(c fiber.Ctx) error {
+	return h.decide(c, "failed to clear screening hit", h.screeningUsecase.Clear)
+}
+
+func (h *ScreeningHandler) decide(c fiber.Ctx, msg string, decide func(ctx context.Context, id, note string) (*domain.ScreeningHit, error)) error {
+	var req ReviewDecisionRequest
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+		}
+	}
+
+	hit, err := decide(c.Context(), c.Params("id"), req.Note)
+	if err != nil {
+		return h.fail(c, msg, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(hit)
+}
+
+func (h *ScreeningHandler) fail(c fiber.Ctx, msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrScreeningByUser):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrHitNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrHitNotPending):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	h.logger.ErrorContext(c.Context(), msg, "error", err)
+	captureException(c.Context(), err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// captureException reports err through the Sentry hub attached to the request
+// context, so the event carries the request scope (e.g. the request ID).
+func captureException(ctx context.Context, err error) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	hub.CaptureException(err)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/domain"
+	"wallet/internal/statement"
+	"wallet/internal/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type StatementHandler struct {
+	statementUsecase usecase.StatementUsecase
+	signingKey       []byte
+	logger           *slog.Logger
+}
+
+func NewStatementHandler(su usecase.StatementUsecase, signingKey []byte, logger *slog.Logger) *StatementHandler {
+	return &StatementHandler{statementUsecase: su, signingKey: signingKey, logger: logger}
+}
+
+// @Summary Get a wallet statement
+// @Description Returns the opening balance, every movement with the running balance, and the closing balance of a wallet over [from, to). Without from and to, the previous calendar month (UTC) is returned. The document is streamed and ends with a signature line that POST /statements/verify checks.
+// @Tags wallets
+// @Produce text/csv
+// @Produce application/pdf
+// @Param id path string true "Wallet ID"
+// @Param from query string false "Start, inclusive (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "End, exclusive (RFC 3339 or YYYY-MM-DD)"
+// @Param format query string false "csv (default) or pdf"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id}/statements [get]
+func (h *StatementHandler) GetStatement(c fiber.Ctx) error {
+	format := c.Query("format", statement.FormatCSV)
+	contentType, err := statement.ContentType(format)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	from, to, err := statementRange(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	st, err := h.statementUsecase.Open(c.Context(), c.Params("id"), from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrWalletNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrInvalidStatementRange):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrNotWalletMember):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.logger.ErrorContext(c.Context(), "failed to open statement", "error", err)
+		captureException(c.Context(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="statement-%s-%s.%s"`, st.WalletID, st.From.Format(time.DateOnly), format))
+	c.Set("X-Statement-ID", st.ID)
+
+	// The body is written after the handler returns, so the status is already
+	// sent when rendering fails. The document then lacks its signature line,
+	// which makes the truncation detectable.
+	ctx := context.WithoutCancel(c.Context())
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		renderer, err := statement.NewRenderer(format, w, h.signingKey)
+		if err == nil {
+			err = h.statementUsecase.Render(ctx, st, renderer)
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to render statement", "statement_id", st.ID, "error", err)
+			captureException(ctx, err)
+		}
+	})
+}
+
+// VerifyStatementResponse tells whether a statement is authentic.
+type VerifyStatementResponse struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// @Summary Verify a statement
+// @Description Checks that a statement returned by GET /wallets/{id}/statements was issued by this service and not altered. The body is the statement document as downloaded.
+// @Tags statements
+// @Accept text/csv
+// @Accept application/pdf
+// @Produce json
+// @Success 200 {object} VerifyStatementResponse
+// @Failure 422 {object} VerifyStatementResponse
+// @Router /statements/verify [post]
+func (h *StatementHandler) VerifyStatement(c fiber.Ctx) error {
+	if err := statement.Verify(c.Body(), h.signingKey); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(VerifyStatementResponse{Valid: false, Error: err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(VerifyStatementResponse{Valid: true})
+}
+
+// statementRange parses the from and to query parameters. Both default to
+// the calendar month before now.
+func statementRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	if from == "" && to == "" {
+		now = now.UTC()
+		thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return thisMonth.AddDate(0, -1, 0), thisMonth, nil
+	}
+	if from == "" {
+		return time.Time{}, time.Time{}, errors.New("from is required when to is set")
+	}
+
+	start, err := parseStatementTime(from)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+	}
+	end := now
+	if to != "" {
+		if end, err = parseStatementTime(to); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+	return start, end, nil
+}
+
+func parseStatementTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
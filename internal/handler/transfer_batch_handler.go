@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// csvTransferHeader is the header row of CSV batches.
+var csvTransferHeader = []string{"to_wallet_id", "amount"}
+
+type TransferBatchHandler struct {
+	batchUsecase usecase.TransferBatchUsecase
+	logger       *slog.Logger
+}
+
+func NewTransferBatchHandler(bu usecase.TransferBatchUsecase, logger *slog.Logger) *TransferBatchHandler {
+	return &TransferBatchHandler{batchUsecase: bu, logger: logger}
+}
+
+type TransferBatchRequest struct {
+	Mode         domain.TransferBatchMode  `json:"mode"`
+	FromWalletID string                    `json:"from_wallet_id"`
+	Transfers    []usecase.TransferRequest `json:"transfers"`
+}
+
+type TransferBatchAcceptedResponse struct {
+	ID        string                     `json:"id"`
+	Status    domain.TransferBatchStatus `json:"status"`
+	StatusURL string                     `json:"status_url"`
+}
+
+type TransferBatchResponse struct {
+	*domain.TransferBatch
+	Items []domain.TransferBatchItem `json:"items"`
+}
+
+type TransferBatchErrorResponse struct {
+	Error     string                          `json:"error"`
+	Transfers []domain.TransferBatchItemError `json:"transfers,omitempty"`
+}
+
+// @Summary Submit a batch of transfers
+// @Description Validates every transfer up front and queues the batch; poll status_url for the outcome. Every transfer leaves from_wallet_id, which the caller must be allowed to spend from. The body is JSON, or CSV (Content-Type text/csv) with the header to_wallet_id,amount and the mode and sender wallet in the query string. In atomic mode every transfer is applied or none; in best_effort mode each one is applied on its own.
+// @Tags wallets
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param batch body TransferBatchRequest true "Transfers to make"
+// @Param mode query string false "atomic (default) or best_effort, for CSV bodies"
+// @Param from_wallet_id query string false "Wallet every transfer leaves, for CSV bodies"
+// @Success 202 {object} TransferBatchAcceptedResponse
+// @Failure 400 {object} TransferBatchErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/transfers/batch [post]
+func (h *TransferBatchHandler) Submit(c fiber.Ctx) error {
+	req, err := parseTransferBatch(c)
+	var batch *domain.TransferBatch
+	if err == nil {
+		if req.Mode == "" {
+			req.Mode = domain.TransferBatchAtomic
+		}
+		batch, err = h.batchUsecase.Submit(c.Context(), req.Mode, req.FromWalletID, req.Transfers)
+	}
+	if err != nil {
+		var invalid *domain.TransferBatchValidationError
+		switch {
+		case errors.As(err, &invalid):
+			return c.Status(fiber.StatusBadRequest).JSON(TransferBatchErrorResponse{Error: domain.ErrInvalidTransferBatch.Error(), Transfers: invalid.Items})
+		case errors.Is(err, domain.ErrInvalidTransferBatch):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrSpendNotAllowed):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrWalletNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.logger.ErrorContext(c.Context(), "failed to submit transfer batch", "error", err)
+		captureException(c.Context(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	statusURL := c.BaseURL() + "/api/v1/wallets/transfers/batch/" + batch.ID
+	c.Location(statusURL)
+	return c.Status(fiber.StatusAccepted).JSON(TransferBatchAcceptedResponse{ID: batch.ID, Status: batch.Status, StatusURL: statusURL})
+}
+
+// @Summary Get a batch of transfers
+// @Description Returns the status of a batch and the outcome of each of its transfers, to the members of the wallet it sends from.
+// @Tags wallets
+// @Produce json
+// @Param id path string true "Batch ID"
+// @Success 200 {object} TransferBatchResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/transfers/batch/{id} [get]
+func (h *TransferBatchHandler) Get(c fiber.Ctx) error {
+	batch, items, err := h.batchUsecase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotWalletMember):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrTransferBatchNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.logger.ErrorContext(c.Context(), "failed to get transfer batch", "error", err)
+		captureException(c.Context(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(TransferBatchResponse{TransferBatch: batch, Items: items})
+}
+
+func parseTransferBatch(c fiber.Ctx) (*TransferBatchRequest, error) {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), "text/csv") {
+		var req TransferBatchRequest
+		if err := c.Bind().Body(&req); err != nil {
+			return nil, fmt.Errorf("%w: cannot parse request", domain.ErrInvalidTransferBatch)
+		}
+		return &req, nil
+	}
+
+	req := &TransferBatchRequest{Mode: domain.TransferBatchMode(c.Query("mode")), FromWalletID: c.Query("from_wallet_id")}
+	r := csv.NewReader(bytes.NewReader(c.Body()))
+	r.FieldsPerRecord = len(csvTransferHeader)
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil || !equalFold(header, csvTransferHeader) {
+		return nil, fmt.Errorf("%w: CSV must start with the header %s", domain.ErrInvalidTransferBatch, strings.Join(csvTransferHeader, ","))
+	}
+
+	var invalid []domain.TransferBatchItemError
+	for position := 1; ; position++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			invalid = append(invalid, domain.TransferBatchItemError{Position: position, Error: err.Error()})
+			continue
+		}
+		amount, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			invalid = append(invalid, domain.TransferBatchItemError{Position: position, Error: fmt.Sprintf("invalid amount %q", record[1])})
+		}
+		req.Transfers = append(req.Transfers, usecase.TransferRequest{ToWalletID: record[0], Amount: amount})
+	}
+	if len(invalid) > 0 {
+		return nil, &domain.TransferBatchValidationError{Items: invalid}
+	}
+	return req, nil
+}
+
+func equalFold(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(strings.TrimSpace(a[i]), b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type TransferReviewHandler struct {
+	reviewUsecase usecase.TransferReviewUsecase
+	logger        *slog.Logger
+}
+
+func NewTransferReviewHandler(ru usecase.TransferReviewUsecase, logger *slog.Logger) *TransferReviewHandler {
+	return &TransferReviewHandler{reviewUsecase: ru, logger: logger}
+}
+
+type ReviewDecisionRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+// @Summary List transfer reviews
+// @Description Returns the transfers the risk rules held, oldest first. Only operators can list them.
+// @Tags admin
+// @Produce json
+// @Param status query string false "pending, approved, rejected or failed; all when empty"
+// @Success 200 {array} domain.TransferReview
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/transfer-reviews [get]
+func (h *TransferReviewHandler) List(c fiber.Ctx) error {
+	reviews, err := h.reviewUsecase.List(c.Context(), domain.TransferReviewStatus(c.Query("status")))
+	if err != nil {
+		return h.fail(c, "failed to list transfer reviews", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(reviews)
+}
+
+// @Summary Get a transfer review
+// @Description Returns a held transfer, its risk score and the rules it hit.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Review ID"
+// @Success 200 {object} domain.TransferReview
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/transfer-reviews/{id} [get]
+func (h *TransferReviewHandler) Get(c fiber.Ctx) error {
+	review, err := h.reviewUsecase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to get transfer review", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(review)
+}
+
+// @Summary Approve a held transfer
+// @Description Makes the held transfer on behalf of whoever asked for it. If it can no longer be made (e.g. the funds are gone) the review ends failed, with the reason in its note.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param decision body ReviewDecisionRequest false "Why the transfer is approved"
+// @Success 200 {object} domain.TransferReview
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/transfer-reviews/{id}/approve [post]
+func (h *TransferReviewHandler) Approve(c fiber.Ctx) error {
+	return h.decide(c, "failed to approve transfer review", h.reviewUsecase.Approve)
+}
+
+// @Summary Reject a held transfer
+// @Description Rejects the held transfer; no money moves.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param decision body ReviewDecisionRequest false "Why the transfer is rejected"
+// @Success 200 {object} domain.TransferReview
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/transfer-reviews/{id}/reject [post]
+func (h *TransferReviewHandler) Reject(c fiber.Ctx) error {
+	return h.decide(c, "failed to reject transfer review", h.reviewUsecase.Reject)
+}
+
+func (h *TransferReviewHandler) decide(c fiber.Ctx, msg string, decide func(ctx context.Context, id, note string) (*domain.TransferReview, error)) error {
+	var req ReviewDecisionRequest
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+		}
+	}
+
+	review, err := decide(c.Context(), c.Params("id"), req.Note)
+	if err != nil {
+		return h.fail(c, msg, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(review)
+}
+
+func (h *TransferReviewHandler) fail(c fiber.Ctx, msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrReviewByUser):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrReviewNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrReviewNotPending):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case isConflict(err):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "wallet is busy, please retry"})
+	}
+	h.logger.ErrorContext(c.Context(), msg, "error", err)
+	captureException(c.Context(), err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
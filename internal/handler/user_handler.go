@@ -1,8 +1,9 @@
 package handler
 
 import (
-	"strings"
+	"errors"
 	"time"
+	"wallet/internal/domain"
 	"wallet/internal/usecase"
 
 	"github.com/gofiber/fiber/v3"
@@ -21,6 +22,7 @@ type UserResponse struct {
 	ID        string    `json:"id"`
 	Username  string    `json:"username"`
 	Name      string    `json:"name"`
+	Tier      string    `json:"tier"`
 	CreatedAt time.Time `json:"created_at"`
 	Message   string    `json:"message,omitempty"`
 }
@@ -32,7 +34,7 @@ type CreateUserRequest struct {
 }
 
 // @Summary Create a new user
-// @Description Creates a new user and an associated empty wallet.
+// @Description Creates a new user and an associated empty wallet. Users whose name matches the sanctions list are refused (422).
 // @Tags users
 // @Accept json
 // @Produce json
@@ -40,6 +42,7 @@ type CreateUserRequest struct {
 // @Success 201 {object} UserResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /users [post]
 func (h *UserHandler) CreateUser(c fiber.Ctx) error {
@@ -53,9 +56,12 @@ func (h *UserHandler) CreateUser(c fiber.Ctx) error {
 	user, err := h.userUsecase.Create(c.Context(), req.Username, req.Name, req.DNI)
 	if err != nil {
 		// 3. Map domain errors to HTTP errors
-		if strings.Contains(err.Error(), "username already exists") {
+		if errors.Is(err, domain.ErrUsernameTaken) || errors.Is(err, domain.ErrDNITaken) {
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
 		}
+		if errors.Is(err, domain.ErrSanctioned) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+		}
 		// For any other unexpected error
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
 	}
@@ -64,6 +70,7 @@ func (h *UserHandler) CreateUser(c fiber.Ctx) error {
 		ID:        user.ID,
 		Username:  user.Username,
 		Name:      user.Name,
+		Tier:      user.Tier,
 		CreatedAt: user.CreatedAt,
 		Message:   "User created successfully with an empty wallet",
 	}
@@ -72,6 +79,121 @@ func (h *UserHandler) CreateUser(c fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
+type AddAliasRequest struct {
+	Kind  domain.AliasKind `json:"kind"` // phone or email
+	Value string           `json:"value"`
+}
+
+// @Summary Register an alias
+// @Description Registers a phone number (international format, e.g. +14155552671) or email address others can send the user money to, and sends it a verification code. The alias finds the user once verified; registering a pending alias again sends a new code. Only the user registers their aliases, and a verified alias belongs to one user at most.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param alias body AddAliasRequest true "Alias to register"
+// @Success 201 {object} domain.UserAlias
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/aliases [post]
+func (h *UserHandler) AddAlias(c fiber.Ctx) error {
+	var req AddAliasRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
+
+	alias, err := h.userUsecase.AddAlias(c.Context(), c.Params("id"), req.Kind, req.Value)
+	if err != nil {
+		return aliasError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(alias)
+}
+
+type VerifyAliasRequest struct {
+	Code string `json:"code"`
+}
+
+// @Summary Verify an alias
+// @Description Checks the code sent to a registered alias; the alias then finds the user. A code expires after the configured time to live or 5 wrong attempts, after which the alias must be registered again for a new one.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param alias_id path string true "Alias ID"
+// @Param code body VerifyAliasRequest true "Verification code"
+// @Success 200 {object} domain.UserAlias
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/aliases/{alias_id}/verify [post]
+func (h *UserHandler) VerifyAlias(c fiber.Ctx) error {
+	var req VerifyAliasRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
+
+	alias, err := h.userUsecase.VerifyAlias(c.Context(), c.Params("id"), c.Params("alias_id"), req.Code)
+	if err != nil {
+		return aliasError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(alias)
+}
+
+// @Summary List the aliases of a user
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {array} domain.UserAlias
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/aliases [get]
+func (h *UserHandler) ListAliases(c fiber.Ctx) error {
+	aliases, err := h.userUsecase.ListAliases(c.Context(), c.Params("id"))
+	if err != nil {
+		return aliasError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(aliases)
+}
+
+// @Summary Remove an alias
+// @Tags users
+// @Param id path string true "User ID"
+// @Param alias_id path string true "Alias ID"
+// @Success 204
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/aliases/{alias_id} [delete]
+func (h *UserHandler) RemoveAlias(c fiber.Ctx) error {
+	if err := h.userUsecase.RemoveAlias(c.Context(), c.Params("id"), c.Params("alias_id")); err != nil {
+		return aliasError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func aliasError(c fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAlias):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrAliasNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrOtherUser):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrAliasTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidAliasCode):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
+	captureException(c.Context(), err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
+
 // ErrorResponse defines the structure for API error responses.
 type ErrorResponse struct {
 	Error string `json:"error"`
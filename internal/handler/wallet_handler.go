@@ -1,21 +1,91 @@
 package handler
 
 import (
+	"errors"
 	"log/slog"
+	"strconv"
 	"strings"
+	"wallet/internal/domain"
 	"wallet/internal/usecase"
 
-	"github.com/getsentry/sentry-go"
 	"github.com/gofiber/fiber/v3"
 )
 
 type WalletHandler struct {
-	walletUsecase usecase.WalletUsecase
-	logger        *slog.Logger
+	walletUsecase    usecase.WalletUsecase
+	recipientUsecase usecase.RecipientUsecase
+	logger           *slog.Logger
 }
 
-func NewWalletHandler(wu usecase.WalletUsecase, logger *slog.Logger) *WalletHandler {
-	return &WalletHandler{walletUsecase: wu, logger: logger}
+func NewWalletHandler(wu usecase.WalletUsecase, ru usecase.RecipientUsecase, logger *slog.Logger) *WalletHandler {
+	return &WalletHandler{walletUsecase: wu, recipientUsecase: ru, logger: logger}
+}
+
+// @Summary Get a wallet
+// @Description Returns a wallet and its balance. The balance may be up to a few seconds stale.
+// @Tags wallets
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Success 200 {object} domain.Wallet
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id} [get]
+func (h *WalletHandler) GetWallet(c fiber.Ctx) error {
+	wallet, err := h.walletUsecase.GetWallet(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrNotWalletMember) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.logger.ErrorContext(c.Context(), "failed to get wallet", "error", err)
+		captureException(c.Context(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(wallet)
+}
+
+// @Summary Preview a fee
+// @Description Returns the fee a transfer from, or a recharge of, the wallet would cost. Total is what a transfer debits (amount plus fee) or a recharge credits (amount less fee).
+// @Tags wallets
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param operation query string true "transfer or recharge"
+// @Param amount query number true "Amount of the operation"
+// @Success 200 {object} domain.FeeQuote
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id}/fees [get]
+func (h *WalletHandler) PreviewFee(c fiber.Ctx) error {
+	amount, err := strconv.ParseFloat(c.Query("amount"), 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "amount must be a number"})
+	}
+
+	quote, err := h.walletUsecase.PreviewFee(c.Context(), c.Params("id"), domain.FeeOperation(c.Query("operation")), amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidFeeRequest):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrWalletNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrNotWalletMember):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrFeeExceedsAmount):
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.logger.ErrorContext(c.Context(), "failed to preview fee", "error", err)
+		captureException(c.Context(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(quote)
 }
 
 type RechargeRequest struct {
@@ -46,7 +116,13 @@ func (h *WalletHandler) Recharge(c fiber.Ctx) error {
 		if strings.Contains(err.Error(), "not found") {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 		}
-		sentry.CaptureException(err)
+		if errors.Is(err, domain.ErrWalletFrozen) || errors.Is(err, domain.ErrFeeExceedsAmount) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+		}
+		if isConflict(err) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "wallet is busy, please retry"})
+		}
+		captureException(c.Context(), err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
 	}
 
@@ -54,9 +130,12 @@ func (h *WalletHandler) Recharge(c fiber.Ctx) error {
 }
 
 type TransferRequest struct {
-	FromWalletID string  `json:"from_wallet_id"`
-	ToWalletID   string  `json:"to_wallet_id"`
-	Amount       float64 `json:"amount"`
+	FromWalletID string `json:"from_wallet_id"`
+	ToWalletID   string `json:"to_wallet_id"`
+	// To is the recipient's username, phone or email alias, or wallet ID. It
+	// is used when ToWalletID is empty.
+	To     string  `json:"to,omitempty"`
+	Amount float64 `json:"amount"`
 }
 
 func (h *WalletHandler) Transfer(c fiber.Ctx) error {
@@ -65,20 +144,81 @@ func (h *WalletHandler) Transfer(c fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
 	}
 
-	err := h.walletUsecase.Transfer(c.Context(), req.FromWalletID, req.ToWalletID, req.Amount)
+	var err error
+	if req.ToWalletID == "" && req.To != "" {
+		var recipient *domain.Recipient
+		if recipient, err = h.recipientUsecase.Resolve(c.Context(), req.FromWalletID, req.To); err == nil {
+			req.ToWalletID = recipient.WalletID
+		}
+	}
+	if err == nil {
+		err = h.walletUsecase.Transfer(c.Context(), req.FromWalletID, req.ToWalletID, req.Amount)
+	}
+	var held *domain.TransferHeldError
+	if errors.As(err, &held) {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"message":   "transfer held for review",
+			"review_id": held.ReviewID,
+		})
+	}
 	if err != nil {
 		h.logger.ErrorContext(c.Context(), "failed to transfer funds", "error", err)
 		// Map specific business logic errors to 4xx status codes
-		if strings.Contains(err.Error(), "insufficient funds") || strings.Contains(err.Error(), "cannot transfer to the same wallet") {
+		if errors.Is(err, domain.ErrInsufficientFunds) || errors.Is(err, domain.ErrInvalidAlias) ||
+			strings.Contains(err.Error(), "cannot transfer to the same wallet") {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
 		if strings.Contains(err.Error(), "not found") {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 		}
+		if errors.Is(err, domain.ErrSpendNotAllowed) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrWalletFrozen) || errors.Is(err, domain.ErrSpendLimitExceeded) ||
+			errors.Is(err, domain.ErrTransferBlocked) || errors.Is(err, domain.ErrSanctioned) ||
+			errors.Is(err, domain.ErrCurrencyMismatch) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+		}
+		if isConflict(err) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "wallet is busy, please retry"})
+		}
 		// Report unexpected errors to Sentry
-		sentry.CaptureException(err)
+		captureException(c.Context(), err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "transfer successful"})
 }
+
+// @Summary Look up a transfer recipient
+// @Description Finds the wallet a transfer from from_wallet_id to a username, phone or email alias, or wallet ID would go to, in the sender's currency, and returns the recipient's name masked for confirmation.
+// @Tags wallets
+// @Produce json
+// @Param from_wallet_id query string true "Sender wallet ID"
+// @Param to query string true "Username (optionally @username), phone (+...), email or wallet ID"
+// @Success 200 {object} domain.Recipient
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /recipients/lookup [get]
+func (h *WalletHandler) LookupRecipient(c fiber.Ctx) error {
+	recipient, err := h.recipientUsecase.Resolve(c.Context(), c.Query("from_wallet_id"), c.Query("to"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAlias) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		h.logger.ErrorContext(c.Context(), "failed to look up recipient", "error", err)
+		captureException(c.Context(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+	return c.Status(fiber.StatusOK).JSON(recipient)
+}
+
+// isConflict reports whether err is a concurrency conflict that persisted
+// after the use case's retries; the client may try again.
+func isConflict(err error) bool {
+	return errors.Is(err, domain.ErrConcurrentModification) || errors.Is(err, domain.ErrTransactionConflict)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type WalletMemberHandler struct {
+	memberUsecase usecase.WalletMemberUsecase
+	logger        *slog.Logger
+}
+
+func NewWalletMemberHandler(mu usecase.WalletMemberUsecase, logger *slog.Logger) *WalletMemberHandler {
+	return &WalletMemberHandler{memberUsecase: mu, logger: logger}
+}
+
+type InviteMemberRequest struct {
+	UserID string            `json:"user_id"`
+	Role   domain.MemberRole `json:"role"` // owner, spender or viewer
+	// DailyLimit is how much a spender can send per UTC day, fees included.
+	DailyLimit float64 `json:"daily_limit,omitempty"`
+}
+
+// @Summary Invite a wallet member
+// @Description Invites a user to share the wallet as an owner, a spender (with a daily limit) or a viewer. The membership applies once the user accepts. Only owners can invite.
+// @Tags wallets
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param member body InviteMemberRequest true "Invitation"
+// @Success 201 {object} domain.WalletMember
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id}/members [post]
+func (h *WalletMemberHandler) Invite(c fiber.Ctx) error {
+	var req InviteMemberRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse request"})
+	}
+
+	member, err := h.memberUsecase.Invite(c.Context(), c.Params("id"), req.UserID, req.Role, req.DailyLimit)
+	if err != nil {
+		return h.fail(c, "failed to invite wallet member", err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(member)
+}
+
+// @Summary List the members of a wallet
+// @Tags wallets
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Success 200 {array} domain.WalletMember
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id}/members [get]
+func (h *WalletMemberHandler) List(c fiber.Ctx) error {
+	members, err := h.memberUsecase.List(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, "failed to list wallet members", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(members)
+}
+
+// @Summary Accept a wallet invitation
+// @Tags wallets
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param user_id path string true "Invited user ID"
+// @Success 200 {object} domain.WalletMember
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id}/members/{user_id}/accept [post]
+func (h *WalletMemberHandler) Accept(c fiber.Ctx) error {
+	member, err := h.memberUsecase.Accept(c.Context(), c.Params("id"), c.Params("user_id"))
+	if err != nil {
+		return h.fail(c, "failed to accept wallet invitation", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(member)
+}
+
+// @Summary Remove a wallet member
+// @Description Removes a member from the wallet or withdraws their invitation. Owners can remove anyone but the wallet's creator; members can remove themselves.
+// @Tags wallets
+// @Param id path string true "Wallet ID"
+// @Param user_id path string true "Member user ID"
+// @Success 204
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /wallets/{id}/members/{user_id} [delete]
+func (h *WalletMemberHandler) Remove(c fiber.Ctx) error {
+	if err := h.memberUsecase.Remove(c.Context(), c.Params("id"), c.Params("user_id")); err != nil {
+		return h.fail(c, "failed to remove wallet member", err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *WalletMemberHandler) fail(c fiber.Ctx, msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidMember):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrNotWalletMember):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrWalletNotFound), errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrMemberNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrAlreadyMember):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrCannotRemoveCreator):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
+	h.logger.ErrorContext(c.Context(), msg, "error", err)
+	captureException(c.Context(), err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+}
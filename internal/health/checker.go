@@ -0,0 +1,149 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status values reported for the service and for each dependency.
+const (
+	StatusUp           = "up"
+	StatusDown         = "down"
+	StatusReady        = "ready"
+	StatusNotReady     = "not_ready"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check probes a single dependency and returns an error if it is unusable.
+type Check func(ctx context.Context) error
+
+// DependencyStatus is the result of probing one dependency.
+type DependencyStatus struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	// Optional dependencies are reported but never make the service not ready.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// Report is the aggregated readiness result.
+type Report struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+	CheckedAt    time.Time                   `json:"checked_at"`
+}
+
+// Ready reports whether the service should receive traffic.
+func (r Report) Ready() bool {
+	return r.Status == StatusReady
+}
+
+type namedCheck struct {
+	name     string
+	check    Check
+	optional bool
+}
+
+// Checker runs the registered dependency checks and caches the result for a
+// short period so that frequent probes don't stampede the dependencies.
+type Checker struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+	checks   []namedCheck
+
+	shuttingDown atomic.Bool
+
+	mu   sync.Mutex
+	last *Report
+}
+
+// NewChecker creates a Checker. Each check gets at most timeout to answer and
+// a computed report is reused for cacheTTL.
+func NewChecker(timeout, cacheTTL time.Duration) *Checker {
+	return &Checker{timeout: timeout, cacheTTL: cacheTTL}
+}
+
+// Register adds a dependency check. It must be called before serving traffic.
+func (c *Checker) Register(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// RegisterOptional adds a check for a dependency the service can run without
+// (e.g. a cache). Its failures are reported but don't fail readiness.
+func (c *Checker) RegisterOptional(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check, optional: true})
+}
+
+// SetShuttingDown makes every following readiness report fail, so load
+// balancers stop routing new requests while in-flight ones drain.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Readiness returns the current readiness report, probing the dependencies
+// only if the cached report has expired.
+func (c *Checker) Readiness(ctx context.Context) Report {
+	if c.shuttingDown.Load() {
+		return Report{Status: StatusShuttingDown, Dependencies: map[string]DependencyStatus{}, CheckedAt: time.Now()}
+	}
+
+	// Holding the lock while probing means concurrent callers wait for a
+	// single round of checks instead of each starting their own.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last != nil && time.Since(c.last.CheckedAt) < c.cacheTTL {
+		return *c.last
+	}
+
+	report := c.run(ctx)
+	c.last = &report
+	return report
+}
+
+func (c *Checker) run(ctx context.Context) Report {
+	results := make([]DependencyStatus, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, nc := range c.checks {
+		wg.Add(1)
+		go func(i int, nc namedCheck) {
+			defer wg.Done()
+			results[i] = c.probe(ctx, nc.check)
+		}(i, nc)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:       StatusReady,
+		Dependencies: make(map[string]DependencyStatus, len(c.checks)),
+		CheckedAt:    time.Now(),
+	}
+	for i, nc := range c.checks {
+		results[i].Optional = nc.optional
+		report.Dependencies[nc.name] = results[i]
+		if results[i].Status != StatusUp && !nc.optional {
+			report.Status = StatusNotReady
+		}
+	}
+	return report
+}
+
+func (c *Checker) probe(ctx context.Context, check Check) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	status := DependencyStatus{
+		Status:    StatusUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+	return status
+}
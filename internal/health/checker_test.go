@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func up(ctx context.Context) error   { return nil }
+func down(ctx context.Context) error { return errors.New("connection refused") }
+
+// A required dependency that is down makes the service not ready; an
+// optional one is only reported.
+func TestCheckerReadiness(t *testing.T) {
+	tests := []struct {
+		name               string
+		required, optional Check
+		want               string
+	}{
+		{"all up", up, up, StatusReady},
+		{"optional down", up, down, StatusReady},
+		{"required down", down, up, StatusNotReady},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChecker(time.Second, 0)
+			c.Register("postgres", tt.required)
+			c.RegisterOptional("redis", tt.optional)
+
+			report := c.Readiness(context.Background())
+			if report.Status != tt.want {
+				t.Errorf("status = %s, want %s", report.Status, tt.want)
+			}
+			if !report.Dependencies["redis"].Optional || report.Dependencies["postgres"].Optional {
+				t.Errorf("dependencies = %+v, want only redis optional", report.Dependencies)
+			}
+			for name, dep := range report.Dependencies {
+				if (dep.Status == StatusDown) != (dep.Error != "") {
+					t.Errorf("%s = %+v, want an error exactly when down", name, dep)
+				}
+			}
+		})
+	}
+}
+
+// A check that doesn't answer within the timeout counts as down.
+func TestCheckerTimesOutSlowChecks(t *testing.T) {
+	c := NewChecker(10*time.Millisecond, 0)
+	c.Register("postgres", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if report := c.Readiness(context.Background()); report.Ready() {
+		t.Errorf("report = %+v, want not ready", report)
+	}
+}
+
+// Reports are reused for the cache TTL, and every report fails once the
+// service is shutting down.
+func TestCheckerCachesAndShutsDown(t *testing.T) {
+	var probes atomic.Int32
+	c := NewChecker(time.Second, time.Hour)
+	c.Register("postgres", func(ctx context.Context) error {
+		probes.Add(1)
+		return nil
+	})
+
+	for range 3 {
+		if report := c.Readiness(context.Background()); !report.Ready() {
+			t.Fatalf("report = %+v, want ready", report)
+		}
+	}
+	if n := probes.Load(); n != 1 {
+		t.Errorf("dependency probed %d times, want once within the cache TTL", n)
+	}
+
+	c.SetShuttingDown()
+	if report := c.Readiness(context.Background()); report.Status != StatusShuttingDown {
+		t.Errorf("status = %s after shutdown started, want %s", report.Status, StatusShuttingDown)
+	}
+}
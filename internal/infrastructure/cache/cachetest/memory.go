@@ -0,0 +1,67 @@
+// Package cachetest provides an in-memory domain.CacheRepository for tests.
+package cachetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotCached plays the part of redis.Nil for Memory.
+var ErrNotCached = errors.New("not cached")
+
+// Memory is an in-memory domain.CacheRepository. Like the Redis one it stores
+// values as JSON and honors TTLs.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	value     string
+	expiresAt time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]entry)}
+}
+
+func (m *Memory) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = entry{value: string(data), expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		return "", ErrNotCached
+	}
+	return e.value, nil
+}
+
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+func (m *Memory) Ping(ctx context.Context) error { return nil }
+
+// Has reports whether key holds a live entry.
+func (m *Memory) Has(key string) bool {
+	_, err := m.Get(context.Background(), key)
+	return err == nil
+}
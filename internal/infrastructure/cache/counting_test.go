@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+	"wallet/internal/domain"
+)
+
+// countingUserRepository is an in-memory domain.UserRepository that counts
+// the lookups reaching it, i.e. the database calls the cache didn't save.
+type countingUserRepository struct {
+	mu    sync.Mutex
+	users map[string]domain.User
+	calls atomic.Int64
+	delay time.Duration // Added to every lookup, like a database round trip
+}
+
+func newCountingUserRepository(users ...domain.User) *countingUserRepository {
+	r := &countingUserRepository{users: make(map[string]domain.User)}
+	for _, u := range users {
+		r.users[u.ID] = u
+	}
+	return r
+}
+
+func (r *countingUserRepository) Save(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *countingUserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	r.calls.Add(1)
+	time.Sleep(r.delay)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (r *countingUserRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	r.calls.Add(1)
+	time.Sleep(r.delay)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *countingUserRepository) List(ctx context.Context, afterID string, limit int) ([]domain.User, error) {
+	return nil, nil
+}
+
+func (r *countingUserRepository) UpdateTier(ctx context.Context, id, tier string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	user.Tier = tier
+	r.users[id] = user
+	return nil
+}
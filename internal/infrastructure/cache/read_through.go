@@ -0,0 +1,203 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"time"
+	"wallet/internal/domain"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// ttlJitter spreads expirations by ±10% so keys cached together don't all
+	// expire together.
+	ttlJitter = 0.1
+
+	// lockTTL bounds how long another instance may hold a reload lock.
+	lockTTL = 5 * time.Second
+
+	// While another instance reloads a key, we poll the cache lockPollAttempts
+	// times, lockPollInterval apart, before loading it ourselves.
+	lockPollAttempts = 5
+	lockPollInterval = 20 * time.Millisecond
+)
+
+// Options tunes a cached repository.
+type Options struct {
+	// TTL is how long a value is considered fresh.
+	TTL time.Duration
+	// NegativeTTL is how long a "not found" result is cached; zero disables it.
+	NegativeTTL time.Duration
+	// StaleTTL enables stale-while-revalidate when positive: for this long after
+	// TTL a value is still served while it is reloaded in the background.
+	StaleTTL time.Duration
+	// Locker, when set, coalesces reloads across instances: only the instance
+	// holding the lock for a key goes to the database, the others wait for it.
+	Locker domain.Locker
+}
+
+// entry is what gets stored in the cache. FreshUntil is kept inside the value
+// because the cache only tells us whether a key exists, not how old it is.
+type entry[T any] struct {
+	Value      T         `json:"value"`
+	NotFound   bool      `json:"not_found,omitempty"`
+	FreshUntil time.Time `json:"fresh_until"`
+}
+
+type lookupResult int
+
+const (
+	cacheMiss lookupResult = iota
+	cacheHit
+	cacheStaleHit
+	cacheNegativeHit
+	cacheUnavailable
+)
+
+// readThrough implements the cache-aside read path shared by the cached
+// repositories: concurrent misses of a key within this process are coalesced
+// with singleflight, and across processes with Options.Locker.
+type readThrough[T any] struct {
+	cache    domain.CacheRepository
+	opts     Options
+	notFound error // the repository's "not found" error, cached as a negative result
+	group    singleflight.Group
+}
+
+func newReadThrough[T any](cache domain.CacheRepository, opts Options, notFound error) *readThrough[T] {
+	return &readThrough[T]{cache: cache, opts: opts, notFound: notFound}
+}
+
+// get returns the cached value for key, or loads it with fetch and caches it.
+func (r *readThrough[T]) get(ctx context.Context, key string, fetch func(context.Context) (T, error)) (T, error) {
+	var zero T
+	value, result := r.lookup(ctx, key)
+	switch result {
+	case cacheHit:
+		return value, nil
+	case cacheStaleHit:
+		r.revalidate(ctx, key, fetch)
+		return value, nil
+	case cacheNegativeHit:
+		return zero, r.notFound
+	case cacheUnavailable:
+		// Cache is down: go straight to the database without trying to populate it.
+		return fetch(ctx)
+	}
+
+	// The first caller does the load; it must not be cut short because that
+	// particular caller went away while others are waiting on the result.
+	loadCtx := context.WithoutCancel(ctx)
+	v, err, _ := r.group.Do(key, func() (interface{}, error) {
+		return r.load(loadCtx, key, fetch, true)
+	})
+	if err != nil {
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// set caches value under key, e.g. when it was obtained by another lookup.
+func (r *readThrough[T]) set(ctx context.Context, key string, value T) {
+	r.store(ctx, key, entry[T]{Value: value}, r.opts.TTL)
+}
+
+// revalidate reloads a stale key in the background, once per process. It uses
+// its own singleflight key because it may give up without a value, which must
+// never be handed to a caller waiting on a miss.
+func (r *readThrough[T]) revalidate(ctx context.Context, key string, fetch func(context.Context) (T, error)) {
+	loadCtx := context.WithoutCancel(ctx)
+	go r.group.Do("revalidate:"+key, func() (interface{}, error) {
+		return r.load(loadCtx, key, fetch, false)
+	})
+}
+
+// load fetches key from the source and caches the result. When another
+// instance holds the reload lock and wait is set, it first gives that instance
+// a chance to populate the cache.
+func (r *readThrough[T]) load(ctx context.Context, key string, fetch func(context.Context) (T, error), wait bool) (T, error) {
+	if r.opts.Locker != nil {
+		unlock, ok, err := r.opts.Locker.TryLock(ctx, "lock:"+key, lockTTL)
+		switch {
+		case err != nil:
+			// The lock is an optimization; without it we just load the key ourselves.
+		case ok:
+			defer unlock(ctx)
+		case !wait:
+			var zero T
+			return zero, nil
+		default:
+			if value, found := r.waitForFill(ctx, key); found {
+				return value, nil
+			}
+		}
+	}
+
+	value, err := fetch(ctx)
+	if errors.Is(err, r.notFound) {
+		if r.opts.NegativeTTL > 0 {
+			r.store(ctx, key, entry[T]{NotFound: true}, r.opts.NegativeTTL)
+		}
+		return value, err
+	}
+	if err != nil {
+		return value, err
+	}
+
+	r.store(ctx, key, entry[T]{Value: value}, r.opts.TTL)
+	return value, nil
+}
+
+// waitForFill polls the cache while another instance reloads key.
+func (r *readThrough[T]) waitForFill(ctx context.Context, key string) (T, bool) {
+	for range lockPollAttempts {
+		time.Sleep(lockPollInterval)
+		if value, result := r.lookup(ctx, key); result == cacheHit || result == cacheStaleHit {
+			return value, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// lookup reads key from the cache.
+func (r *readThrough[T]) lookup(ctx context.Context, key string) (T, lookupResult) {
+	var e entry[T]
+	cached, err := r.cache.Get(ctx, key)
+	if errors.Is(err, domain.ErrCacheUnavailable) {
+		return e.Value, cacheUnavailable
+	}
+	if err != nil || cached == "" {
+		return e.Value, cacheMiss
+	}
+	// Entries written before FreshUntil existed decode with a zero time; treat them as misses.
+	if err := json.Unmarshal([]byte(cached), &e); err != nil || e.FreshUntil.IsZero() {
+		return e.Value, cacheMiss
+	}
+	if e.NotFound {
+		return e.Value, cacheNegativeHit
+	}
+	if time.Now().After(e.FreshUntil) {
+		return e.Value, cacheStaleHit
+	}
+	return e.Value, cacheHit
+}
+
+// store writes e with a jittered TTL. With stale-while-revalidate enabled the
+// key outlives its freshness by StaleTTL so it can be served while reloading.
+func (r *readThrough[T]) store(ctx context.Context, key string, e entry[T], ttl time.Duration) {
+	ttl = jitter(ttl)
+	e.FreshUntil = time.Now().Add(ttl)
+	if !e.NotFound {
+		ttl += r.opts.StaleTTL
+	}
+	r.cache.Set(ctx, key, e, ttl)
+}
+
+func jitter(ttl time.Duration) time.Duration {
+	delta := (rand.Float64()*2 - 1) * ttlJitter * float64(ttl)
+	return ttl + time.Duration(delta)
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+	"wallet/internal/infrastructure/cache/cachetest"
+)
+
+// benchmarkUsers is how many distinct users the benchmark readers look up.
+const benchmarkUsers = 16
+
+// BenchmarkReadThrough runs concurrent readers over a few hot users, with and
+// without the cache in front of a repository that takes a database round trip
+// per lookup, and reports how many lookups reach the database per read.
+func BenchmarkReadThrough(b *testing.B) {
+	users := make([]domain.User, benchmarkUsers)
+	for i := range users {
+		users[i] = domain.User{ID: fmt.Sprintf("user-%d", i), Username: fmt.Sprintf("user%d", i)}
+	}
+
+	for _, bc := range []struct {
+		name   string
+		cached bool
+	}{
+		{"uncached", false},
+		{"cached", true},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			next := newCountingUserRepository(users...)
+			next.delay = 50 * time.Microsecond
+			var repo domain.UserRepository = next
+			if bc.cached {
+				repo = NewCachedUserRepository(cachetest.NewMemory(), next, Options{TTL: time.Minute})
+			}
+
+			ctx := context.Background()
+			b.SetParallelism(8)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					if _, err := repo.FindByID(ctx, users[i%len(users)].ID); err != nil {
+						b.Error(err)
+						return
+					}
+					i++
+				}
+			})
+			b.ReportMetric(float64(next.calls.Load())/float64(b.N), "db-calls/op")
+		})
+	}
+}
@@ -2,55 +2,108 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"time"
 	"wallet/internal/domain"
 )
 
 type cachedUserRepository struct {
 	cacheRepo domain.CacheRepository
 	nextRepo  domain.UserRepository // The "next" repository in the chain (Postgres)
+	users     *readThrough[domain.User]
+	usernames *readThrough[string] // username → user ID
 }
 
-func NewCachedUserRepository(cache domain.CacheRepository, next domain.UserRepository) domain.UserRepository {
+// NewCachedUserRepository caches users by ID and the username→ID mapping, as
+// well as "not found" results, according to opts.
+func NewCachedUserRepository(cache domain.CacheRepository, next domain.UserRepository, opts Options) domain.UserRepository {
 	return &cachedUserRepository{
 		cacheRepo: cache,
 		nextRepo:  next,
+		users:     newReadThrough[domain.User](cache, opts, domain.ErrUserNotFound),
+		usernames: newReadThrough[string](cache, opts, domain.ErrUserNotFound),
 	}
 }
 
+func userKey(id string) string {
+	return fmt.Sprintf("user:%s", id)
+}
+
+func usernameKey(username string) string {
+	return fmt.Sprintf("user:username:%s", username)
+}
+
 func (c *cachedUserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
-	// 1. First, try to get the user from the cache.
-	cacheKey := fmt.Sprintf("user:%s", id)
-	cachedUserJSON, err := c.cacheRepo.Get(ctx, cacheKey)
-
-	// 2. Cache Hit: If found, deserialize and return it.
-	if err == nil && cachedUserJSON != "" {
-		var user domain.User
-		if err := json.Unmarshal([]byte(cachedUserJSON), &user); err == nil {
-			return &user, nil
+	user, err := c.users.get(ctx, userKey(id), func(ctx context.Context) (domain.User, error) {
+		found, err := c.nextRepo.FindByID(ctx, id)
+		if err != nil {
+			return domain.User{}, err
 		}
+		return *found, nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return &user, nil
+}
 
-	// 3. Cache Miss: If not in cache, get it from the database.
-	user, err := c.nextRepo.FindByID(ctx, id)
+func (c *cachedUserRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	// The username key only stores the ID; the user itself comes from the ID key,
+	// so there is a single cached copy of each user to keep fresh.
+	id, err := c.usernames.get(ctx, usernameKey(username), func(ctx context.Context) (string, error) {
+		found, err := c.nextRepo.FindByUsername(ctx, username)
+		if err != nil {
+			return "", err
+		}
+		c.users.set(ctx, userKey(found.ID), *found)
+		return found.ID, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	user, err := c.FindByID(ctx, id)
+	if err == nil && user.Username == username {
+		return user, nil
+	}
 
-	// 4. Store the result in the cache for next time.
-	c.cacheRepo.Set(ctx, cacheKey, user, 5*time.Minute) // Cache for 5 minutes
+	// The mapping is stale (user renamed or gone); resolve it from the database.
+	c.cacheRepo.Delete(ctx, usernameKey(username))
+	return c.nextRepo.FindByUsername(ctx, username)
+}
 
-	return user, nil
+// List is used for exports and batch jobs, which must see every user as stored.
+func (c *cachedUserRepository) List(ctx context.Context, afterID string, limit int) ([]domain.User, error) {
+	return c.nextRepo.List(ctx, afterID, limit)
 }
 
-// For methods that change data, we just pass them through and could optionally invalidate the cache.
+// Save writes to the database and then invalidates every key that may describe
+// the user, including cached "not found" results. We invalidate instead of
+// writing through because Save usually runs inside a transaction that may
+// still roll back, and we wait for the commit so a concurrent reader can't
+// cache the pre-commit state again.
 func (c *cachedUserRepository) Save(ctx context.Context, user *domain.User) error {
-	return c.nextRepo.Save(ctx, user)
+	if err := c.nextRepo.Save(ctx, user); err != nil {
+		return err
+	}
+	domain.AfterCommit(ctx, func(ctx context.Context) {
+		c.invalidate(ctx, user)
+	})
+	return nil
 }
 
-func (c *cachedUserRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
-	// This could also be cached, but we'll leave it for simplicity.
-	return c.nextRepo.FindByUsername(ctx, username)
+// UpdateTier invalidates the cached user once the change commits, like Save.
+// The username key only maps to the ID, so it stays valid.
+func (c *cachedUserRepository) UpdateTier(ctx context.Context, id, tier string) error {
+	if err := c.nextRepo.UpdateTier(ctx, id, tier); err != nil {
+		return err
+	}
+	domain.AfterCommit(ctx, func(ctx context.Context) {
+		c.cacheRepo.Delete(ctx, userKey(id))
+	})
+	return nil
+}
+
+// invalidate drops the cached entries for user. A failure here is not returned:
+// the write already succeeded, and the entries expire on their own after the TTL.
+func (c *cachedUserRepository) invalidate(ctx context.Context, user *domain.User) {
+	c.cacheRepo.Delete(ctx, userKey(user.ID), usernameKey(user.Username))
 }
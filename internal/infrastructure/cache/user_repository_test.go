@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+	"wallet/internal/infrastructure/cache/cachetest"
+)
+
+var testOptions = Options{TTL: time.Minute, NegativeTTL: time.Minute}
+
+func TestCachedUserRepositoryMissLoadsAndCaches(t *testing.T) {
+	ctx := context.Background()
+	cache := cachetest.NewMemory()
+	next := newCountingUserRepository(domain.User{ID: "u1", Username: "jdoe", Name: "John Doe"})
+	repo := NewCachedUserRepository(cache, next, testOptions)
+
+	user, err := repo.FindByID(ctx, "u1")
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if user.Username != "jdoe" {
+		t.Errorf("username = %q, want jdoe", user.Username)
+	}
+	if got := next.calls.Load(); got != 1 {
+		t.Errorf("database calls = %d, want 1", got)
+	}
+	if !cache.Has(userKey("u1")) {
+		t.Error("user was not cached after a miss")
+	}
+}
+
+func TestCachedUserRepositoryHitSkipsDatabase(t *testing.T) {
+	ctx := context.Background()
+	cache := cachetest.NewMemory()
+	next := newCountingUserRepository(domain.User{ID: "u1", Username: "jdoe"})
+	repo := NewCachedUserRepository(cache, next, testOptions)
+
+	for range 3 {
+		if _, err := repo.FindByID(ctx, "u1"); err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+	}
+	if _, err := repo.FindByUsername(ctx, "jdoe"); err != nil {
+		t.Fatalf("FindByUsername: %v", err)
+	}
+	if _, err := repo.FindByUsername(ctx, "jdoe"); err != nil {
+		t.Fatalf("FindByUsername: %v", err)
+	}
+	// One load by ID, one to map the username; everything else is a hit.
+	if got := next.calls.Load(); got != 2 {
+		t.Errorf("database calls = %d, want 2", got)
+	}
+}
+
+func TestCachedUserRepositoryNegativeHit(t *testing.T) {
+	ctx := context.Background()
+	next := newCountingUserRepository()
+	repo := NewCachedUserRepository(cachetest.NewMemory(), next, testOptions)
+
+	for range 3 {
+		if _, err := repo.FindByID(ctx, "missing"); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("FindByID error = %v, want ErrUserNotFound", err)
+		}
+	}
+	if got := next.calls.Load(); got != 1 {
+		t.Errorf("database calls = %d, want 1: not found should be cached", got)
+	}
+}
+
+func TestCachedUserRepositoryNegativeCachingDisabled(t *testing.T) {
+	ctx := context.Background()
+	next := newCountingUserRepository()
+	repo := NewCachedUserRepository(cachetest.NewMemory(), next, Options{TTL: time.Minute})
+
+	for range 2 {
+		if _, err := repo.FindByID(ctx, "missing"); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("FindByID error = %v, want ErrUserNotFound", err)
+		}
+	}
+	if got := next.calls.Load(); got != 2 {
+		t.Errorf("database calls = %d, want 2 without NegativeTTL", got)
+	}
+}
+
+func TestCachedUserRepositorySaveInvalidatesAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	cache := cachetest.NewMemory()
+	next := newCountingUserRepository(domain.User{ID: "u1", Username: "jdoe", Name: "John Doe"})
+	repo := NewCachedUserRepository(cache, next, testOptions)
+
+	if _, err := repo.FindByUsername(ctx, "jdoe"); err != nil {
+		t.Fatalf("FindByUsername: %v", err)
+	}
+
+	txCtx, commit := domain.NewTransactionContext(ctx)
+	if err := repo.Save(txCtx, &domain.User{ID: "u1", Username: "jdoe", Name: "Jane Doe"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	// Until the commit other readers must keep seeing the committed state, and
+	// must not be able to cache the uncommitted one.
+	if !cache.Has(userKey("u1")) {
+		t.Fatal("user was invalidated before the transaction committed")
+	}
+
+	commit(ctx)
+	if cache.Has(userKey("u1")) || cache.Has(usernameKey("jdoe")) {
+		t.Fatal("user keys still cached after the commit")
+	}
+	user, err := repo.FindByID(ctx, "u1")
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if user.Name != "Jane Doe" {
+		t.Errorf("name = %q, want the saved Jane Doe", user.Name)
+	}
+}
+
+func TestCachedUserRepositorySaveClearsNegativeEntry(t *testing.T) {
+	ctx := context.Background()
+	next := newCountingUserRepository()
+	repo := NewCachedUserRepository(cachetest.NewMemory(), next, testOptions)
+
+	if _, err := repo.FindByUsername(ctx, "jdoe"); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("FindByUsername error = %v, want ErrUserNotFound", err)
+	}
+	if err := repo.Save(ctx, &domain.User{ID: "u1", Username: "jdoe"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := repo.FindByUsername(ctx, "jdoe"); err != nil {
+		t.Fatalf("FindByUsername after Save: %v", err)
+	}
+}
+
+func TestCachedUserRepositoryRollbackKeepsCache(t *testing.T) {
+	ctx := context.Background()
+	cache := cachetest.NewMemory()
+	repo := NewCachedUserRepository(cache, newCountingUserRepository(domain.User{ID: "u1", Username: "jdoe"}), testOptions)
+
+	if _, err := repo.FindByID(ctx, "u1"); err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	txCtx, _ := domain.NewTransactionContext(ctx) // never committed
+	if err := repo.Save(txCtx, &domain.User{ID: "u1", Username: "jdoe"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if !cache.Has(userKey("u1")) {
+		t.Error("user was invalidated by a transaction that never committed")
+	}
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"wallet/internal/domain"
+)
+
+// cachedWalletRepository caches wallets for display only. Balances read inside
+// a transaction are the ones money moves are computed from, so those reads
+// always go to the database, and writes invalidate the cache only once the
+// transaction has committed.
+type cachedWalletRepository struct {
+	cacheRepo   domain.CacheRepository
+	nextRepo    domain.WalletRepository
+	wallets     *readThrough[domain.Wallet]
+	userWallets *readThrough[string] // user ID → wallet ID
+}
+
+func NewCachedWalletRepository(cache domain.CacheRepository, next domain.WalletRepository, opts Options) domain.WalletRepository {
+	return &cachedWalletRepository{
+		cacheRepo:   cache,
+		nextRepo:    next,
+		wallets:     newReadThrough[domain.Wallet](cache, opts, domain.ErrWalletNotFound),
+		userWallets: newReadThrough[string](cache, opts, domain.ErrWalletNotFound),
+	}
+}
+
+func walletKey(id string) string {
+	return fmt.Sprintf("wallet:%s", id)
+}
+
+func userWalletKey(userID string) string {
+	return fmt.Sprintf("wallet:user:%s", userID)
+}
+
+func (c *cachedWalletRepository) FindByID(ctx context.Context, id string) (*domain.Wallet, error) {
+	if domain.InTransaction(ctx) {
+		return c.nextRepo.FindByID(ctx, id)
+	}
+
+	wallet, err := c.wallets.get(ctx, walletKey(id), func(ctx context.Context) (domain.Wallet, error) {
+		found, err := c.nextRepo.FindByID(ctx, id)
+		if err != nil {
+			return domain.Wallet{}, err
+		}
+		return *found, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (c *cachedWalletRepository) FindByUserID(ctx context.Context, userID string) (*domain.Wallet, error) {
+	if domain.InTransaction(ctx) {
+		return c.nextRepo.FindByUserID(ctx, userID)
+	}
+
+	id, err := c.userWallets.get(ctx, userWalletKey(userID), func(ctx context.Context) (string, error) {
+		found, err := c.nextRepo.FindByUserID(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		c.wallets.set(ctx, walletKey(found.ID), *found)
+		return found.ID, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := c.FindByID(ctx, id)
+	if err == nil && wallet.UserID == userID {
+		return wallet, nil
+	}
+
+	// The mapping is stale; resolve it from the database.
+	c.cacheRepo.Delete(ctx, userWalletKey(userID))
+	return c.nextRepo.FindByUserID(ctx, userID)
+}
+
+// FindByUserIDAndCurrency is only used to resolve transfer recipients, which
+// must not be sent to a wallet that no longer exists, so it isn't cached.
+func (c *cachedWalletRepository) FindByUserIDAndCurrency(ctx context.Context, userID, currency string) (*domain.Wallet, error) {
+	return c.nextRepo.FindByUserIDAndCurrency(ctx, userID, currency)
+}
+
+// List is used for exports and batch jobs, which must see every wallet as stored.
+func (c *cachedWalletRepository) List(ctx context.Context, afterID string, limit int) ([]domain.Wallet, error) {
+	return c.nextRepo.List(ctx, afterID, limit)
+}
+
+func (c *cachedWalletRepository) Save(ctx context.Context, wallet *domain.Wallet) error {
+	if err := c.nextRepo.Save(ctx, wallet); err != nil {
+		return err
+	}
+	c.invalidateAfterCommit(ctx, wallet)
+	return nil
+}
+
+func (c *cachedWalletRepository) Update(ctx context.Context, wallet *domain.Wallet) error {
+	if err := c.nextRepo.Update(ctx, wallet); err != nil {
+		return err
+	}
+	c.invalidateAfterCommit(ctx, wallet)
+	return nil
+}
+
+// invalidateAfterCommit drops the cached entries for wallet once the
+// transaction commits. Invalidating earlier would let a concurrent display read
+// cache the pre-commit balance again; on rollback nothing changed, so there is
+// nothing to drop.
+func (c *cachedWalletRepository) invalidateAfterCommit(ctx context.Context, wallet *domain.Wallet) {
+	domain.AfterCommit(ctx, func(ctx context.Context) {
+		c.cacheRepo.Delete(ctx, walletKey(wallet.ID), userWalletKey(wallet.UserID))
+	})
+}
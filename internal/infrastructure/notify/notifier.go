@@ -0,0 +1,69 @@
+// Package notify delivers user notifications.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+	"wallet/internal/domain"
+)
+
+// webhookTimeout bounds a single delivery, so a slow receiver can't hold up
+// the job that triggered the notification.
+const webhookTimeout = 5 * time.Second
+
+type logNotifier struct {
+	logger *slog.Logger
+}
+
+// NewLogNotifier logs notifications instead of delivering them. It is used
+// when no delivery channel is configured.
+func NewLogNotifier(logger *slog.Logger) domain.Notifier {
+	return &logNotifier{logger: logger}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	n.logger.InfoContext(ctx, "notification",
+		"kind", notification.Kind,
+		"user_id", notification.UserID,
+		"message", notification.Message,
+	)
+	return nil
+}
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier POSTs every notification as JSON to url, e.g. the
+// service that sends e-mails and push notifications.
+func NewWebhookNotifier(url string) domain.Notifier {
+	return &webhookNotifier{url: url, client: &http.Client{Timeout: webhookTimeout}}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification webhook answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresAdjustmentRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresAdjustmentRequestRepository(db *gorm.DB) domain.AdjustmentRequestRepository {
+	return &postgresAdjustmentRequestRepository{db: db}
+}
+
+func (r *postgresAdjustmentRequestRepository) Save(ctx context.Context, request *domain.AdjustmentRequest) error {
+	return mapError(conn(ctx, r.db).Create(request).Error)
+}
+
+func (r *postgresAdjustmentRequestRepository) FindByID(ctx context.Context, id string) (*domain.AdjustmentRequest, error) {
+	return r.find(conn(ctx, r.db), id)
+}
+
+func (r *postgresAdjustmentRequestRepository) FindByIDForUpdate(ctx context.Context, id string) (*domain.AdjustmentRequest, error) {
+	return r.find(conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func (r *postgresAdjustmentRequestRepository) find(db *gorm.DB, id string) (*domain.AdjustmentRequest, error) {
+	var request domain.AdjustmentRequest
+	err := db.Where("id = ?", id).First(&request).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrAdjustmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *postgresAdjustmentRequestRepository) List(ctx context.Context, status domain.AdjustmentRequestStatus) ([]domain.AdjustmentRequest, error) {
+	query := conn(ctx, r.db).Order("created_at")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var requests []domain.AdjustmentRequest
+	err := query.Find(&requests).Error
+	return requests, err
+}
+
+// Resolve only updates a request that is still pending, so an adjustment is
+// never decided on twice.
+func (r *postgresAdjustmentRequestRepository) Resolve(ctx context.Context, request *domain.AdjustmentRequest) error {
+	result := conn(ctx, r.db).Model(&domain.AdjustmentRequest{}).
+		Where("id = ? AND status = ?", request.ID, domain.AdjustmentPending).
+		Updates(map[string]interface{}{
+			"status":      request.Status,
+			"reviewed_by": request.ReviewedBy,
+			"reviewed_at": request.ReviewedAt,
+			"note":        request.Note,
+			"movement_id": request.MovementID,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return mapError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrAdjustmentNotPending
+	}
+	return nil
+}
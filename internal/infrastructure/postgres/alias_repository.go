@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresAliasRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresAliasRepository(db *gorm.DB) domain.AliasRepository {
+	return &postgresAliasRepository{db: db}
+}
+
+func (r *postgresAliasRepository) Save(ctx context.Context, alias *domain.UserAlias) error {
+	return mapError(conn(ctx, r.db).Create(alias).Error)
+}
+
+func (r *postgresAliasRepository) Update(ctx context.Context, alias *domain.UserAlias) error {
+	return mapError(conn(ctx, r.db).Model(alias).Select("verified_at", "code_hash", "code_expires_at", "code_attempts").Updates(alias).Error)
+}
+
+func (r *postgresAliasRepository) ListByUser(ctx context.Context, userID string) ([]domain.UserAlias, error) {
+	var aliases []domain.UserAlias
+	err := conn(ctx, r.db).Where("user_id = ?", userID).Order("created_at").Find(&aliases).Error
+	return aliases, err
+}
+
+func (r *postgresAliasRepository) FindByIDForUpdate(ctx context.Context, userID, id string) (*domain.UserAlias, error) {
+	var alias domain.UserAlias
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", id, userID).First(&alias).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrAliasNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+func (r *postgresAliasRepository) FindByValue(ctx context.Context, kind domain.AliasKind, value string) (*domain.UserAlias, error) {
+	var alias domain.UserAlias
+	err := conn(ctx, r.db).Where("kind = ? AND value = ? AND verified_at IS NOT NULL", kind, value).First(&alias).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrAliasNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+func (r *postgresAliasRepository) Delete(ctx context.Context, userID, id string) error {
+	result := conn(ctx, r.db).Where("id = ? AND user_id = ?", id, userID).Delete(&domain.UserAlias{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrAliasNotFound
+	}
+	return nil
+}
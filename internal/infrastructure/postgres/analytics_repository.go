@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// insightsQuery sums the rollups of the wallets a user owns by currency.
+const insightsQuery = `
+SELECT w.currency, r.month, r.dimension, r.key,
+	SUM(r.inflow) AS inflow, SUM(r.outflow) AS outflow, SUM(r.count) AS count
+FROM analytics_rollups r
+JOIN wallets w ON w.id = r.wallet_id
+WHERE w.user_id = ? AND r.month >= ? AND r.month < ?
+GROUP BY w.currency, r.month, r.dimension, r.key
+HAVING SUM(r.count) <> 0
+ORDER BY w.currency, r.month, r.dimension, r.key`
+
+type postgresAnalyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresAnalyticsRepository keeps the rollups on the primary: they are
+// updated continuously and small enough to read from there.
+func NewPostgresAnalyticsRepository(db *gorm.DB) domain.AnalyticsRepository {
+	return &postgresAnalyticsRepository{db: db}
+}
+
+func (r *postgresAnalyticsRepository) ClaimEvents(ctx context.Context, limit int) ([]domain.Movement, error) {
+	var movements []domain.Movement
+	err := conn(ctx, r.db).Raw(`
+		SELECT m.* FROM movements m
+		WHERE m.id IN (
+			SELECT movement_id FROM movement_events
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		ORDER BY m.created_at, m.id`, limit).
+		Scan(&movements).Error
+	return movements, err
+}
+
+func (r *postgresAnalyticsRepository) DeleteEvents(ctx context.Context, movementIDs []string) error {
+	if len(movementIDs) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Where("movement_id IN ?", movementIDs).Delete(&domain.MovementEvent{}).Error
+}
+
+// AddToRollups upserts the rows in key order, so concurrent batches touching
+// the same rows lock them in the same order and can't deadlock.
+func (r *postgresAnalyticsRepository) AddToRollups(ctx context.Context, deltas []domain.AnalyticsRollup) error {
+	for _, delta := range sortRollups(deltas) {
+		err := conn(ctx, r.db).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "wallet_id"}, {Name: "month"}, {Name: "dimension"}, {Name: "key"}},
+			DoUpdates: clause.Set{
+				{Column: clause.Column{Name: "inflow"}, Value: gorm.Expr("analytics_rollups.inflow + EXCLUDED.inflow")},
+				{Column: clause.Column{Name: "outflow"}, Value: gorm.Expr("analytics_rollups.outflow + EXCLUDED.outflow")},
+				{Column: clause.Column{Name: "count"}, Value: gorm.Expr("analytics_rollups.count + EXCLUDED.count")},
+			},
+		}).Create(&delta).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortRollups returns deltas in key order, with the deltas of the same row
+// merged into one.
+func sortRollups(deltas []domain.AnalyticsRollup) []domain.AnalyticsRollup {
+	sorted := slices.Clone(deltas)
+	slices.SortFunc(sorted, func(a, b domain.AnalyticsRollup) int {
+		return cmp.Or(
+			cmp.Compare(a.WalletID, b.WalletID),
+			a.Month.Compare(b.Month),
+			cmp.Compare(a.Dimension, b.Dimension),
+			cmp.Compare(a.Key, b.Key),
+		)
+	})
+
+	merged := sorted[:0]
+	for _, delta := range sorted {
+		if n := len(merged); n > 0 && merged[n-1].WalletID == delta.WalletID && merged[n-1].Month.Equal(delta.Month) &&
+			merged[n-1].Dimension == delta.Dimension && merged[n-1].Key == delta.Key {
+			merged[n-1].Inflow += delta.Inflow
+			merged[n-1].Outflow += delta.Outflow
+			merged[n-1].Count += delta.Count
+			continue
+		}
+		merged = append(merged, delta)
+	}
+	return merged
+}
+
+func (r *postgresAnalyticsRepository) SaveCategories(ctx context.Context, categories []domain.MovementCategory) error {
+	if len(categories) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(&categories).Error
+}
+
+func (r *postgresAnalyticsRepository) FindCategoryForUpdate(ctx context.Context, movementID string) (*domain.MovementCategory, error) {
+	var category domain.MovementCategory
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("movement_id = ?", movementID).
+		First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrMovementNotCategorized
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *postgresAnalyticsRepository) UpdateCategory(ctx context.Context, category *domain.MovementCategory) error {
+	return conn(ctx, r.db).Model(&domain.MovementCategory{}).
+		Where("movement_id = ?", category.MovementID).
+		Updates(map[string]interface{}{
+			"category":   category.Category,
+			"source":     category.Source,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *postgresAnalyticsRepository) SaveMerchant(ctx context.Context, merchant *domain.Merchant) error {
+	return mapError(conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "wallet_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "category", "updated_at"}),
+	}).Create(merchant).Error)
+}
+
+func (r *postgresAnalyticsRepository) FindMerchants(ctx context.Context, walletIDs []string) (map[string]domain.Merchant, error) {
+	merchants := make(map[string]domain.Merchant)
+	if len(walletIDs) == 0 {
+		return merchants, nil
+	}
+	var found []domain.Merchant
+	if err := conn(ctx, r.db).Where("wallet_id IN ?", walletIDs).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	for _, merchant := range found {
+		merchants[merchant.WalletID] = merchant
+	}
+	return merchants, nil
+}
+
+func (r *postgresAnalyticsRepository) CounterpartyNames(ctx context.Context, walletIDs []string) (map[string]string, error) {
+	names := make(map[string]string)
+	if len(walletIDs) == 0 {
+		return names, nil
+	}
+	var rows []struct {
+		WalletID string
+		Merchant *string
+		Owner    string
+	}
+	err := conn(ctx, r.db).Raw(`
+		SELECT w.id AS wallet_id, mc.name AS merchant, u.name AS owner
+		FROM wallets w
+		JOIN users u ON u.id = w.user_id
+		LEFT JOIN merchants mc ON mc.wallet_id = w.id
+		WHERE w.id IN ?`, walletIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if row.Merchant != nil {
+			names[row.WalletID] = *row.Merchant
+		} else {
+			names[row.WalletID] = domain.MaskName(row.Owner)
+		}
+	}
+	return names, nil
+}
+
+func (r *postgresAnalyticsRepository) ListInsights(ctx context.Context, userID string, from, to time.Time) ([]domain.InsightRow, error) {
+	var rows []domain.InsightRow
+	err := conn(ctx, r.db).Raw(insightsQuery, userID, from, to).Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type postgresAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresAuditRepository(db *gorm.DB) domain.AuditRepository {
+	return &postgresAuditRepository{db: db}
+}
+
+func (r *postgresAuditRepository) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	if entry.Details == "" {
+		entry.Details = "{}"
+	}
+	return conn(ctx, r.db).Create(entry).Error
+}
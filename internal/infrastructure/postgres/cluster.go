@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// replicaLagQuery returns the status of the replica's WAL receiver and how far
+// behind the primary the replica is, in seconds. A replica that has replayed
+// everything it received counts as fully caught up even if the primary has
+// been idle for a while, which only holds while it is still receiving WAL:
+// the status tells the two apart. It is NULL when the receiver isn't running,
+// and for roles without pg_read_all_stats.
+const replicaLagQuery = `
+SELECT
+	(SELECT status FROM pg_stat_wal_receiver) AS receiver_status,
+	COALESCE(
+		CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+		END, 0) AS lag_seconds`
+
+// walReceiverStreaming is the WAL receiver status of a replica connected to
+// its primary.
+const walReceiverStreaming = "streaming"
+
+// Cluster routes queries between the primary and an optional read replica.
+// Writes and everything inside WithTransaction go to the primary; read-only
+// lookups go to the replica while it is healthy and within maxLag.
+type Cluster struct {
+	primary *gorm.DB
+	replica *gorm.DB
+	maxLag  time.Duration
+	logger  *slog.Logger
+
+	replicaUsable atomic.Bool
+}
+
+// NewCluster creates a Cluster. replica may be nil, in which case every query
+// goes to the primary. The replica is not used until MonitorReplica has
+// checked it at least once.
+func NewCluster(primary, replica *gorm.DB, maxLag time.Duration, logger *slog.Logger) *Cluster {
+	return &Cluster{primary: primary, replica: replica, maxLag: maxLag, logger: logger}
+}
+
+// Primary returns the primary connection.
+func (c *Cluster) Primary() *gorm.DB {
+	return c.primary
+}
+
+// read returns the connection for a read-only query.
+func (c *Cluster) read(ctx context.Context) *gorm.DB {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return conn(ctx, c.primary)
+	}
+	if !c.readsReplica(ctx) {
+		return c.primary.WithContext(ctx)
+	}
+	return c.replica.WithContext(ctx)
+}
+
+// readsReplica reports whether read sends the queries of ctx to the replica.
+// A row missing there may just not have been replicated yet, so callers that
+// remember "not found" results confirm them on the primary.
+func (c *Cluster) readsReplica(ctx context.Context) bool {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return false
+	}
+	return c.replica != nil && c.replicaUsable.Load() && !domain.ReadYourWrites(ctx)
+}
+
+// write returns the connection for a query that changes data.
+func (c *Cluster) write(ctx context.Context) *gorm.DB {
+	return conn(ctx, c.primary)
+}
+
+// MonitorReplica checks the replica every interval until ctx is done, and
+// sends reads back to the primary while it is down or lagging beyond maxLag.
+func (c *Cluster) MonitorReplica(ctx context.Context, interval time.Duration) {
+	if c.replica == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.checkReplica(ctx, interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PingReplica reports an error if the replica is down or lagging, for readiness.
+func (c *Cluster) PingReplica(ctx context.Context) error {
+	lag, err := c.replicaLag(ctx)
+	if err != nil {
+		return err
+	}
+	if lag > c.maxLag {
+		return &ReplicaLagError{Lag: lag, MaxLag: c.maxLag}
+	}
+	return nil
+}
+
+func (c *Cluster) checkReplica(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := c.PingReplica(ctx)
+	usable := err == nil
+	if c.replicaUsable.Swap(usable) == usable {
+		return
+	}
+	if usable {
+		c.logger.Info("read replica is healthy, routing reads to it")
+	} else {
+		c.logger.Warn("read replica is unhealthy, routing reads to the primary", "error", err)
+	}
+}
+
+// replicaLag returns how far behind the primary the replica is. A replica
+// that stopped receiving WAL would report no lag at all while falling further
+// behind, so it is reported as an error instead.
+func (c *Cluster) replicaLag(ctx context.Context) (time.Duration, error) {
+	var row struct {
+		ReceiverStatus sql.NullString
+		LagSeconds     sql.NullFloat64
+	}
+	if err := c.replica.WithContext(ctx).Raw(replicaLagQuery).Scan(&row).Error; err != nil {
+		return 0, err
+	}
+	if row.ReceiverStatus.String != walReceiverStreaming {
+		return 0, &ReplicaNotStreamingError{Status: row.ReceiverStatus.String}
+	}
+	return time.Duration(row.LagSeconds.Float64 * float64(time.Second)), nil
+}
+
+// Close closes the connections of the cluster.
+func (c *Cluster) Close() error {
+	if c.replica != nil {
+		if sqlDB, err := c.replica.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+	sqlDB, err := c.primary.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// ReplicaLagError is returned when the replica is too far behind the primary.
+type ReplicaLagError struct {
+	Lag    time.Duration
+	MaxLag time.Duration
+}
+
+func (e *ReplicaLagError) Error() string {
+	return fmt.Sprintf("replica lag %s exceeds %s", e.Lag, e.MaxLag)
+}
+
+// ReplicaNotStreamingError is returned when the replica isn't receiving WAL
+// from the primary, so its lag can't be trusted.
+type ReplicaNotStreamingError struct {
+	// Status is the WAL receiver status; empty when it isn't running or the
+	// replica's role can't read it.
+	Status string
+}
+
+func (e *ReplicaNotStreamingError) Error() string {
+	if e.Status == "" {
+		return "replica is not receiving WAL: no WAL receiver running, or its status is not readable (grant pg_read_all_stats)"
+	}
+	return fmt.Sprintf("replica is not receiving WAL: WAL receiver is %s", e.Status)
+}
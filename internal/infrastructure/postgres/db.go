@@ -1 +1,29 @@
 package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey is the context key under which WithTransaction stores the *gorm.DB of
+// the running transaction.
+type txKey struct{}
+
+// conn returns the transaction bound to ctx, or db when there is none, so that
+// every repository call made inside WithTransaction joins the transaction.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// Ping checks that the database behind db accepts connections.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
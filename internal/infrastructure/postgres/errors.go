@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"wallet/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes (SQLSTATE) for integrity constraint violations.
+const (
+	codeForeignKeyViolation = "23503"
+	codeUniqueViolation     = "23505"
+	codeCheckViolation      = "23514"
+
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+// constraintErrors maps constraint names to the domain error they enforce.
+// Unique constraints on single columns are matched by column name instead
+// (see mapError), since their names depend on how the table was created.
+var constraintErrors = map[string]error{
+	"wallets_balance_within_overdraft":     domain.ErrInsufficientFunds,
+	"wallets_overdraft_limit_non_negative": domain.ErrInvalidOverdraftLimit,
+	"wallets_user_id_currency_key":         domain.ErrWalletAlreadyExists,
+	"fk_wallets_currencies":                domain.ErrUnsupportedCurrency,
+	"fk_wallets_users":                     domain.ErrUserNotFound,
+	"fk_wallets_products":                  domain.ErrUnknownProduct,
+
+	"idx_reconciliation_runs_scheduled_for":      domain.ErrReconciliationAlreadyRan,
+	"idx_user_aliases_kind_value":                domain.ErrAliasTaken,
+	"idx_user_aliases_user_id_kind_value":        domain.ErrAliasTaken,
+	"fk_user_aliases_users":                      domain.ErrUserNotFound,
+	"idx_wallet_members_wallet_user":             domain.ErrAlreadyMember,
+	"fk_wallet_members_users":                    domain.ErrUserNotFound,
+	"fk_wallet_members_wallets":                  domain.ErrWalletNotFound,
+	"pockets_balance_non_negative":               domain.ErrInsufficientFunds,
+	"idx_pockets_wallet_id_name":                 domain.ErrPocketNameTaken,
+	"fk_pockets_wallets":                         domain.ErrWalletNotFound,
+	"fk_merchants_wallets":                       domain.ErrWalletNotFound,
+	"fk_screening_hits_users":                    domain.ErrUserNotFound,
+	"fk_adjustment_requests_wallets":             domain.ErrWalletNotFound,
+	"adjustment_requests_reviewer_not_requester": domain.ErrSelfReview,
+}
+
+// mapError translates constraint violations and concurrency conflicts reported
+// by PostgreSQL into typed domain errors, so callers can answer with a 4xx (or
+// retry) instead of a generic 500. Any other error is returned unchanged.
+func mapError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case codeSerializationFailure, codeDeadlockDetected:
+		return fmt.Errorf("%w: %s", domain.ErrTransactionConflict, pgErr.Message)
+	case codeForeignKeyViolation, codeUniqueViolation, codeCheckViolation:
+	default:
+		return err
+	}
+
+	if mapped, ok := constraintErrors[pgErr.ConstraintName]; ok {
+		return mapped
+	}
+	if pgErr.Code == codeUniqueViolation && pgErr.TableName == "users" {
+		switch {
+		case strings.Contains(pgErr.ConstraintName, "username"):
+			return domain.ErrUsernameTaken
+		case strings.Contains(pgErr.ConstraintName, "dni"):
+			return domain.ErrDNITaken
+		}
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"wallet/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestMapError(t *testing.T) {
+	errOther := errors.New("connection reset")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"overdraft exceeded", &pgconn.PgError{Code: codeCheckViolation, ConstraintName: "wallets_balance_within_overdraft"}, domain.ErrInsufficientFunds},
+		{"negative overdraft limit", &pgconn.PgError{Code: codeCheckViolation, ConstraintName: "wallets_overdraft_limit_non_negative"}, domain.ErrInvalidOverdraftLimit},
+		{"second wallet in a currency", &pgconn.PgError{Code: codeUniqueViolation, ConstraintName: "wallets_user_id_currency_key"}, domain.ErrWalletAlreadyExists},
+		{"unknown currency", &pgconn.PgError{Code: codeForeignKeyViolation, ConstraintName: "fk_wallets_currencies"}, domain.ErrUnsupportedCurrency},
+		{"unknown owner", &pgconn.PgError{Code: codeForeignKeyViolation, ConstraintName: "fk_wallets_users"}, domain.ErrUserNotFound},
+		{"username taken", &pgconn.PgError{Code: codeUniqueViolation, TableName: "users", ConstraintName: "users_username_key"}, domain.ErrUsernameTaken},
+		{"username taken, GORM constraint name", &pgconn.PgError{Code: codeUniqueViolation, TableName: "users", ConstraintName: "uni_users_username"}, domain.ErrUsernameTaken},
+		{"dni taken", &pgconn.PgError{Code: codeUniqueViolation, TableName: "users", ConstraintName: "users_dni_key"}, domain.ErrDNITaken},
+		{"wrapped violation", fmt.Errorf("update wallet: %w", &pgconn.PgError{Code: codeCheckViolation, ConstraintName: "wallets_balance_within_overdraft"}), domain.ErrInsufficientFunds},
+		{"serialization failure", &pgconn.PgError{Code: codeSerializationFailure}, domain.ErrTransactionConflict},
+		{"deadlock", &pgconn.PgError{Code: codeDeadlockDetected}, domain.ErrTransactionConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mapError(tt.err); !errors.Is(got, tt.want) {
+				t.Errorf("mapError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	unchanged := []struct {
+		name string
+		err  error
+	}{
+		{"not a PostgreSQL error", errOther},
+		{"unknown constraint", &pgconn.PgError{Code: codeCheckViolation, ConstraintName: "something_else"}},
+		{"unique violation elsewhere", &pgconn.PgError{Code: codeUniqueViolation, TableName: "wallets", ConstraintName: "wallets_username_key"}},
+		{"other SQLSTATE", &pgconn.PgError{Code: "42P01", ConstraintName: "wallets_balance_within_overdraft"}},
+	}
+	for _, tt := range unchanged {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mapError(tt.err); got != tt.err {
+				t.Errorf("mapError() = %v, want the error unchanged", got)
+			}
+		})
+	}
+}
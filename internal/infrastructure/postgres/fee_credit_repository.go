@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type postgresFeeCreditRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresFeeCreditRepository(db *gorm.DB) domain.FeeCreditRepository {
+	return &postgresFeeCreditRepository{db: db}
+}
+
+func (r *postgresFeeCreditRepository) Save(ctx context.Context, credit *domain.FeeCredit) error {
+	return mapError(conn(ctx, r.db).Create(credit).Error)
+}
+
+func (r *postgresFeeCreditRepository) Claim(ctx context.Context, limit int) ([]domain.Movement, error) {
+	var movements []domain.Movement
+	err := conn(ctx, r.db).Raw(`
+		SELECT m.* FROM movements m
+		WHERE m.id IN (
+			SELECT movement_id FROM fee_credits
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		ORDER BY m.created_at, m.id`, limit).
+		Scan(&movements).Error
+	return movements, err
+}
+
+func (r *postgresFeeCreditRepository) Delete(ctx context.Context, movementIDs []string) error {
+	if len(movementIDs) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Where("movement_id IN ?", movementIDs).Delete(&domain.FeeCredit{}).Error
+}
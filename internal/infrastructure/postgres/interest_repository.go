@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// interestCandidatesQuery reads a page of wallets whose product pays interest,
+// with the balance after their last movement before the end of the day. The
+// ledger, unlike wallets.balance, tells the balance of any past day, so late
+// or repeated runs accrue on the right balance. Wallets without a movement
+// before then have the opening balance recorded when the ledger was
+// introduced, if any.
+const interestCandidatesQuery = `
+SELECT w.id AS wallet_id, p.interest_rate AS rate, COALESCE((
+	SELECT m.balance_after FROM movements m
+	WHERE m.wallet_id = w.id AND m.created_at < ?
+	ORDER BY m.created_at DESC, m.id DESC
+	LIMIT 1
+), (
+	SELECT m.balance_after FROM movements m
+	WHERE m.wallet_id = w.id AND m.type = 'opening_balance'
+	ORDER BY m.created_at, m.id
+	LIMIT 1
+), 0) AS balance
+FROM wallets w
+JOIN wallet_products p ON p.code = w.product
+WHERE p.interest_rate > 0 AND w.id > ?
+ORDER BY w.id
+LIMIT ?`
+
+type postgresInterestRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresInterestRepository reads balances from the primary: a lagging
+// replica could miss the last movements of the day being accrued.
+func NewPostgresInterestRepository(db *gorm.DB) domain.InterestRepository {
+	return &postgresInterestRepository{db: db}
+}
+
+func (r *postgresInterestRepository) ListCandidates(ctx context.Context, day time.Time, afterID string, limit int) ([]domain.InterestCandidate, error) {
+	if afterID == "" {
+		afterID = firstUUID
+	}
+	var candidates []domain.InterestCandidate
+	err := conn(ctx, r.db).Raw(interestCandidatesQuery, day.AddDate(0, 0, 1), afterID, limit).Scan(&candidates).Error
+	return candidates, err
+}
+
+func (r *postgresInterestRepository) SaveAccruals(ctx context.Context, accruals []domain.InterestAccrual) (int, error) {
+	if len(accruals) == 0 {
+		return 0, nil
+	}
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "wallet_id"}, {Name: "day"}}, DoNothing: true}).
+		Create(&accruals)
+	return int(result.RowsAffected), result.Error
+}
+
+func (r *postgresInterestRepository) LastAccruedDay(ctx context.Context) (*time.Time, error) {
+	var day sql.NullTime
+	if err := conn(ctx, r.db).Raw(`SELECT MAX(day) FROM interest_accruals`).Scan(&day).Error; err != nil {
+		return nil, err
+	}
+	if !day.Valid {
+		return nil, nil
+	}
+	return &day.Time, nil
+}
+
+func (r *postgresInterestRepository) ListWalletsToPost(ctx context.Context, before time.Time, afterID string, limit int) ([]string, error) {
+	if afterID == "" {
+		afterID = firstUUID
+	}
+	var ids []string
+	err := conn(ctx, r.db).Model(&domain.InterestAccrual{}).
+		Distinct("wallet_id").
+		Where("posted_at IS NULL AND day < ? AND wallet_id > ?", before, afterID).
+		Order("wallet_id").
+		Limit(limit).
+		Pluck("wallet_id", &ids).Error
+	return ids, err
+}
+
+func (r *postgresInterestRepository) LockPending(ctx context.Context, walletID string, before time.Time) ([]domain.InterestAccrual, error) {
+	var accruals []domain.InterestAccrual
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("wallet_id = ? AND posted_at IS NULL AND day < ?", walletID, before).
+		Order("day").
+		Find(&accruals).Error
+	return accruals, err
+}
+
+func (r *postgresInterestRepository) MarkPosted(ctx context.Context, ids []string, movementID *string, postedAt time.Time) error {
+	return conn(ctx, r.db).Model(&domain.InterestAccrual{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"movement_id": movementID, "posted_at": postedAt}).Error
+}
+
+func (r *postgresInterestRepository) Pending(ctx context.Context, walletID string) (float64, *time.Time, error) {
+	var row struct {
+		Pending float64
+		Last    sql.NullTime
+	}
+	err := conn(ctx, r.db).Raw(`
+		SELECT COALESCE(SUM(amount) FILTER (WHERE posted_at IS NULL), 0) AS pending, MAX(day) AS last
+		FROM interest_accruals WHERE wallet_id = ?`, walletID).Scan(&row).Error
+	if err != nil || !row.Last.Valid {
+		return row.Pending, nil, err
+	}
+	return row.Pending, &row.Last.Time, nil
+}
+
+func (r *postgresInterestRepository) FindProduct(ctx context.Context, code string) (*domain.WalletProduct, error) {
+	var product domain.WalletProduct
+	err := conn(ctx, r.db).Where("code = ?", code).First(&product).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrUnknownProduct
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+	"wallet/db/migration"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// Wallets without a movement before the day accrued earn interest on their
+// opening balance, not on nothing.
+func TestInterestCandidatesFallBackToOpeningBalance(t *testing.T) {
+	db := openTestSchema(t)
+	ctx := context.Background()
+
+	migrator, err := NewMigrator(db, migration.FS, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	user := &domain.User{ID: uuid.New().String(), Username: "alice", Name: "Alice", DNI: "1"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	opened := &domain.Wallet{ID: uuid.New().String(), UserID: user.ID, Currency: "USD", Balance: 1000, Status: domain.WalletActive, Product: domain.ProductSavings}
+	empty := &domain.Wallet{ID: uuid.New().String(), UserID: user.ID, Currency: "USD", Status: domain.WalletActive, Product: domain.ProductSavings}
+	for _, w := range []*domain.Wallet{opened, empty} {
+		if err := db.Create(w).Error; err != nil {
+			t.Fatalf("create wallet: %v", err)
+		}
+	}
+	// The opening balance is stamped after the day accrued.
+	opening := &domain.Movement{
+		ID: uuid.New().String(), WalletID: opened.ID, Type: domain.MovementOpeningBalance,
+		Amount: 1000, BalanceAfter: 1000, Actor: "system", CreatedAt: day.AddDate(0, 0, 5),
+	}
+	if err := db.Create(opening).Error; err != nil {
+		t.Fatalf("create movement: %v", err)
+	}
+
+	candidates, err := NewPostgresInterestRepository(db).ListCandidates(ctx, day, "", 10)
+	if err != nil {
+		t.Fatalf("ListCandidates: %v", err)
+	}
+	balances := make(map[string]float64)
+	for _, c := range candidates {
+		balances[c.WalletID] = c.Balance
+	}
+	if got, ok := balances[opened.ID]; !ok || got != 1000 {
+		t.Errorf("wallet with an opening balance: balance %v (listed %t), want 1000", got, ok)
+	}
+	if got, ok := balances[empty.ID]; !ok || got != 0 {
+		t.Errorf("wallet without movements: balance %v (listed %t), want 0", got, ok)
+	}
+}
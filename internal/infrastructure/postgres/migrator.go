@@ -0,0 +1,300 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// migrationLockID is the advisory lock key held while migrating, so replicas
+// of the service starting together don't apply the same migration twice.
+const migrationLockID = 7_305_117_001
+
+// The version table uses golang-migrate's layout, so `make migrateup` and the
+// binary can be used interchangeably on the same database.
+const createVersionTable = `CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`
+
+// adoptedTable is the table GORM's AutoMigrate created before the schema was
+// versioned. Finding it in an unversioned database means the first migration
+// is already in place.
+const adoptedTable = "users"
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// ErrDirtyDatabase is returned when a previous migration failed half-way and
+// the schema must be fixed by hand before migrating again.
+var ErrDirtyDatabase = errors.New("database is in a dirty migration state")
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version uint64
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationStatus describes a migration and whether it has been applied.
+type MigrationStatus struct {
+	Version uint64 `json:"version"`
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+}
+
+// Migrator applies the SQL migrations found in a filesystem.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// NewMigrator loads the migrations from files (see db/migration).
+func NewMigrator(db *gorm.DB, files fs.FS, logger *slog.Logger) (*Migrator, error) {
+	migrations, err := loadMigrations(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations, logger: logger}, nil
+}
+
+func loadMigrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		body, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if match[3] == "up" {
+			m.up = string(body)
+		} else {
+			m.down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.To(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down rolls back the last steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			if m.migrations[i].Version > current {
+				continue
+			}
+			if err := m.apply(ctx, conn, m.migrations[i], false); err != nil {
+				return err
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// To migrates up or down until version is the latest applied migration.
+// Version 0 rolls back everything.
+func (m *Migrator) To(ctx context.Context, version uint64) error {
+	if version != 0 && m.find(version) < 0 {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if version >= current {
+			for _, mig := range m.migrations {
+				if mig.Version > current && mig.Version <= version {
+					if err := m.apply(ctx, conn, mig, true); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if mig.Version <= current && mig.Version > version {
+				if err := m.apply(ctx, conn, mig, false); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Status lists every known migration and whether it is applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			statuses = append(statuses, MigrationStatus{Version: mig.Version, Name: mig.Name, Applied: mig.Version <= current})
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+func (m *Migrator) find(version uint64) int {
+	for i, mig := range m.migrations {
+		if mig.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// apply runs one migration and records the new version in the same
+// transaction, so a failure leaves neither the schema nor the version changed.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration, up bool) error {
+	body, newVersion, direction := mig.up, mig.Version, "up"
+	if !up {
+		if mig.down == "" {
+			return fmt.Errorf("migration %d_%s has no down file", mig.Version, mig.Name)
+		}
+		body, newVersion, direction = mig.down, 0, "down"
+		if i := m.find(mig.Version); i > 0 {
+			newVersion = m.migrations[i-1].Version
+		}
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, body); err != nil {
+		return fmt.Errorf("migration %d_%s %s: %w", mig.Version, mig.Name, direction, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	if newVersion > 0 {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, newVersion); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	m.logger.Info("applied migration", "version", mig.Version, "name", mig.Name, "direction", direction)
+	return nil
+}
+
+func (m *Migrator) currentVersion(ctx context.Context, conn *sql.Conn) (uint64, error) {
+	var version uint64
+	var dirty bool
+	err := conn.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("%w at version %d", ErrDirtyDatabase, version)
+	}
+	return version, nil
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory
+// lock. Session-level advisory locks belong to a connection, hence sql.Conn.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	if _, err := conn.ExecContext(ctx, createVersionTable); err != nil {
+		return err
+	}
+	if err := m.adopt(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// adopt records the first migration as applied on a database that AutoMigrate
+// created before the migrations existed, so its tables aren't created twice.
+// The later migrations bring the schema in line from there.
+func (m *Migrator) adopt(ctx context.Context, conn *sql.Conn) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	var versioned, existing bool
+	err := conn.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM schema_migrations), to_regclass($1) IS NOT NULL`, adoptedTable,
+	).Scan(&versioned, &existing)
+	if err != nil || versioned || !existing {
+		return err
+	}
+
+	first := m.migrations[0]
+	if _, err := conn.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, first.Version); err != nil {
+		return err
+	}
+	m.logger.Info("adopted unversioned database", "version", first.Version, "name", first.Name)
+	return nil
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type postgresMovementRepository struct {
+	db *Cluster
+}
+
+// NewPostgresMovementRepository serves ledger history from the read replica
+// when one is available (see Cluster).
+func NewPostgresMovementRepository(db *Cluster) domain.MovementRepository {
+	return &postgresMovementRepository{db: db}
+}
+
+// Save runs in a transaction of its own (a savepoint inside the caller's), so
+// a movement is never saved without its event.
+func (r *postgresMovementRepository) Save(ctx context.Context, movement *domain.Movement) error {
+	return mapError(r.db.write(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(movement).Error; err != nil {
+			return err
+		}
+		return tx.Create(&domain.MovementEvent{MovementID: movement.ID}).Error
+	}))
+}
+
+func (r *postgresMovementRepository) FindByID(ctx context.Context, id string) (*domain.Movement, error) {
+	var movement domain.Movement
+	err := r.db.read(ctx).Where("id = ?", id).First(&movement).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrMovementNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &movement, nil
+}
+
+func (r *postgresMovementRepository) ListByWallet(ctx context.Context, walletID string, from, to time.Time) ([]domain.Movement, error) {
+	var movements []domain.Movement
+	err := r.db.read(ctx).
+		Where("wallet_id = ? AND created_at >= ? AND created_at < ?", walletID, from, to).
+		Order("created_at, id").
+		Find(&movements).Error
+	return movements, err
+}
+
+func (r *postgresMovementRepository) ListByWalletAfter(ctx context.Context, walletID string, from, to time.Time, after *domain.Movement, limit int) ([]domain.Movement, error) {
+	query := r.db.read(ctx).
+		Where("wallet_id = ? AND created_at >= ? AND created_at < ?", walletID, from, to)
+	if after != nil {
+		query = query.Where("(created_at, id) > (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	var movements []domain.Movement
+	err := query.Order("created_at, id").Limit(limit).Find(&movements).Error
+	return movements, err
+}
+
+func (r *postgresMovementRepository) BalanceAt(ctx context.Context, walletID string, at time.Time) (float64, error) {
+	var balances []float64
+	err := r.db.read(ctx).Model(&domain.Movement{}).
+		Where("wallet_id = ? AND created_at < ?", walletID, at).
+		Order("created_at DESC, id DESC").
+		Limit(1).
+		Pluck("balance_after", &balances).Error
+	if err != nil || len(balances) == 0 {
+		return 0, err
+	}
+	return balances[0], nil
+}
+
+func (r *postgresMovementRepository) SpentBy(ctx context.Context, walletID, actor string, since time.Time) (float64, error) {
+	var spent float64
+	err := r.db.read(ctx).Model(&domain.Movement{}).
+		Where("wallet_id = ? AND actor = ? AND created_at >= ? AND type IN ?",
+			walletID, actor, since, []domain.MovementType{domain.MovementTransferOut, domain.MovementFee}).
+		Select("COALESCE(-SUM(amount), 0)").
+		Scan(&spent).Error
+	return spent, err
+}
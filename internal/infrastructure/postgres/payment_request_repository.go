@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresPaymentRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresPaymentRequestRepository(db *gorm.DB) domain.PaymentRequestRepository {
+	return &postgresPaymentRequestRepository{db: db}
+}
+
+func (r *postgresPaymentRequestRepository) Save(ctx context.Context, request *domain.PaymentRequest) error {
+	return mapError(conn(ctx, r.db).Create(request).Error)
+}
+
+func (r *postgresPaymentRequestRepository) FindByID(ctx context.Context, id string) (*domain.PaymentRequest, error) {
+	return r.find(conn(ctx, r.db).Where("id = ?", id))
+}
+
+func (r *postgresPaymentRequestRepository) FindByIDForUpdate(ctx context.Context, id string) (*domain.PaymentRequest, error) {
+	return r.find(conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id))
+}
+
+func (r *postgresPaymentRequestRepository) FindByReviewIDForUpdate(ctx context.Context, reviewID string) (*domain.PaymentRequest, error) {
+	return r.find(conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).Where("review_id = ?", reviewID))
+}
+
+func (r *postgresPaymentRequestRepository) find(db *gorm.DB) (*domain.PaymentRequest, error) {
+	var request domain.PaymentRequest
+	err := db.First(&request).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrPaymentRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *postgresPaymentRequestRepository) ListOpen(ctx context.Context, walletID string, now time.Time) ([]domain.PaymentRequest, error) {
+	var requests []domain.PaymentRequest
+	err := conn(ctx, r.db).
+		Where("(requester_wallet_id = ? OR payer_wallet_id = ?) AND status = ? AND expires_at > ?",
+			walletID, walletID, domain.PaymentRequestPending, now).
+		Order("created_at DESC").
+		Find(&requests).Error
+	return requests, err
+}
+
+// Resolve only updates a request that is still in status from, so a request
+// resolved by a concurrent transaction is never resolved twice.
+func (r *postgresPaymentRequestRepository) Resolve(ctx context.Context, request *domain.PaymentRequest, from domain.PaymentRequestStatus) error {
+	result := conn(ctx, r.db).Model(&domain.PaymentRequest{}).
+		Where("id = ? AND status = ?", request.ID, from).
+		Updates(map[string]interface{}{
+			"status":      request.Status,
+			"resolved_at": request.ResolvedAt,
+			"resolved_by": request.ResolvedBy,
+			"review_id":   request.ReviewID,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return mapError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrPaymentRequestNotPending
+	}
+	return nil
+}
+
+// ExpireDue skips requests locked by a transaction resolving them; they are
+// either resolved or expired on the next call.
+func (r *postgresPaymentRequestRepository) ExpireDue(ctx context.Context, now time.Time) ([]domain.PaymentRequest, error) {
+	var expired []domain.PaymentRequest
+	err := conn(ctx, r.db).Raw(`
+		UPDATE payment_requests SET status = ?, resolved_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM payment_requests
+			WHERE status = ? AND expires_at <= ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		domain.PaymentRequestExpired, now, now, domain.PaymentRequestPending, now).
+		Scan(&expired).Error
+	return expired, err
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type postgresPocketRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresPocketRepository(db *gorm.DB) domain.PocketRepository {
+	return &postgresPocketRepository{db: db}
+}
+
+func (r *postgresPocketRepository) Save(ctx context.Context, pocket *domain.Pocket) error {
+	return mapError(conn(ctx, r.db).Create(pocket).Error)
+}
+
+func (r *postgresPocketRepository) FindByID(ctx context.Context, id string) (*domain.Pocket, error) {
+	var pocket domain.Pocket
+	err := conn(ctx, r.db).Where("id = ?", id).First(&pocket).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrPocketNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pocket, nil
+}
+
+func (r *postgresPocketRepository) ListByWallet(ctx context.Context, walletID string) ([]domain.Pocket, error) {
+	var pockets []domain.Pocket
+	err := conn(ctx, r.db).
+		Where("wallet_id = ? AND status = ?", walletID, domain.PocketOpen).
+		Order("created_at, id").
+		Find(&pockets).Error
+	return pockets, err
+}
+
+func (r *postgresPocketRepository) Update(ctx context.Context, pocket *domain.Pocket) error {
+	now := time.Now()
+	result := conn(ctx, r.db).Model(&domain.Pocket{}).
+		Where("id = ?", pocket.ID).
+		Updates(map[string]interface{}{
+			"balance":    pocket.Balance,
+			"status":     pocket.Status,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return mapError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrPocketNotFound
+	}
+	pocket.UpdatedAt = now
+	return nil
+}
+
+func (r *postgresPocketRepository) SaveMovement(ctx context.Context, movement *domain.PocketMovement) error {
+	return mapError(conn(ctx, r.db).Create(movement).Error)
+}
+
+func (r *postgresPocketRepository) ListMovements(ctx context.Context, pocketID string) ([]domain.PocketMovement, error) {
+	var movements []domain.PocketMovement
+	err := conn(ctx, r.db).Where("pocket_id = ?", pocketID).Order("created_at, id").Find(&movements).Error
+	return movements, err
+}
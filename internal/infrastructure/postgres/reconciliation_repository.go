@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"wallet/internal/domain"
+)
+
+// ledgerBalancesQuery reads a page of wallets with the sum of their movements.
+// It is a single statement, so each balance and its movements come from the
+// same snapshot, and it takes no row locks.
+const ledgerBalancesQuery = `
+SELECT w.id AS wallet_id, w.currency, w.balance, COALESCE(SUM(m.amount), 0) AS ledger_balance
+FROM wallets w
+LEFT JOIN movements m ON m.wallet_id = w.id
+WHERE w.id > ?
+GROUP BY w.id
+ORDER BY w.id
+LIMIT ?`
+
+// currencyTotalsQuery sums, per currency, every balance and every movement
+// that isn't a transfer or a fee. Those only move money between wallets, so
+// both sums must match once the fees taken from payers but not yet credited to
+// the house wallets are counted in the balances.
+const currencyTotalsQuery = `
+SELECT b.currency, b.balance + COALESCE(p.pending_fees, 0) AS balance, COALESCE(i.inflows, 0) AS inflows
+FROM (SELECT currency, SUM(balance) AS balance FROM wallets GROUP BY currency) b
+LEFT JOIN (
+	SELECT w.currency, -SUM(m.amount) AS pending_fees
+	FROM fee_credits f JOIN movements m ON m.id = f.movement_id JOIN wallets w ON w.id = m.wallet_id
+	GROUP BY w.currency
+) p ON p.currency = b.currency
+LEFT JOIN (
+	SELECT w.currency, SUM(m.amount) AS inflows
+	FROM movements m JOIN wallets w ON w.id = m.wallet_id
+	WHERE m.type NOT IN ('transfer_in', 'transfer_out', 'fee', 'fee_income')
+	GROUP BY w.currency
+) i ON i.currency = b.currency
+ORDER BY b.currency`
+
+// firstUUID sorts before every other UUID.
+const firstUUID = "00000000-0000-0000-0000-000000000000"
+
+type postgresReconciliationRepository struct {
+	db *Cluster
+}
+
+// NewPostgresReconciliationRepository reads balances from the read replica
+// when one is available (see Cluster), keeping the scan off the primary.
+func NewPostgresReconciliationRepository(db *Cluster) domain.ReconciliationRepository {
+	return &postgresReconciliationRepository{db: db}
+}
+
+func (r *postgresReconciliationRepository) StartRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	return mapError(r.db.write(ctx).Create(run).Error)
+}
+
+func (r *postgresReconciliationRepository) FinishRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	return r.db.write(ctx).Model(run).Select("status", "wallets_checked", "discrepancies", "error", "finished_at").Updates(run).Error
+}
+
+func (r *postgresReconciliationRepository) SaveDiscrepancies(ctx context.Context, discrepancies []domain.ReconciliationDiscrepancy) error {
+	if len(discrepancies) == 0 {
+		return nil
+	}
+	return r.db.write(ctx).Create(&discrepancies).Error
+}
+
+func (r *postgresReconciliationRepository) LedgerBalances(ctx context.Context, afterWalletID string, limit int) ([]domain.WalletLedgerBalance, error) {
+	if afterWalletID == "" {
+		afterWalletID = firstUUID
+	}
+	var balances []domain.WalletLedgerBalance
+	err := r.db.read(ctx).Raw(ledgerBalancesQuery, afterWalletID, limit).Scan(&balances).Error
+	return balances, err
+}
+
+func (r *postgresReconciliationRepository) CurrencyTotals(ctx context.Context) ([]domain.CurrencyTotal, error) {
+	var totals []domain.CurrencyTotal
+	err := r.db.read(ctx).Raw(currencyTotalsQuery).Scan(&totals).Error
+	return totals, err
+}
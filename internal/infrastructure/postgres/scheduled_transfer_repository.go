@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresScheduledTransferRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresScheduledTransferRepository(db *gorm.DB) domain.ScheduledTransferRepository {
+	return &postgresScheduledTransferRepository{db: db}
+}
+
+func (r *postgresScheduledTransferRepository) Save(ctx context.Context, schedule *domain.ScheduledTransfer) error {
+	return mapError(conn(ctx, r.db).Create(schedule).Error)
+}
+
+func (r *postgresScheduledTransferRepository) FindByID(ctx context.Context, id string) (*domain.ScheduledTransfer, error) {
+	return r.find(conn(ctx, r.db), id)
+}
+
+func (r *postgresScheduledTransferRepository) FindByIDForUpdate(ctx context.Context, id string) (*domain.ScheduledTransfer, error) {
+	return r.find(conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func (r *postgresScheduledTransferRepository) find(db *gorm.DB, id string) (*domain.ScheduledTransfer, error) {
+	var schedule domain.ScheduledTransfer
+	err := db.Where("id = ?", id).First(&schedule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *postgresScheduledTransferRepository) ListByWallet(ctx context.Context, walletID string) ([]domain.ScheduledTransfer, error) {
+	var schedules []domain.ScheduledTransfer
+	err := conn(ctx, r.db).Where("from_wallet_id = ?", walletID).Order("created_at DESC").Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *postgresScheduledTransferRepository) Update(ctx context.Context, schedule *domain.ScheduledTransfer) error {
+	return mapError(conn(ctx, r.db).Model(schedule).
+		Select("amount", "end_at", "status", "next_run_at", "last_run_at", "updated_at").
+		Updates(schedule).Error)
+}
+
+func (r *postgresScheduledTransferRepository) ClaimDue(ctx context.Context, now time.Time) (*domain.ScheduledTransfer, error) {
+	var schedules []domain.ScheduledTransfer
+	err := conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_run_at <= ?", domain.ScheduleActive, now).
+		Order("next_run_at").
+		Limit(1).
+		Find(&schedules).Error
+	if err != nil || len(schedules) == 0 {
+		return nil, err
+	}
+	return &schedules[0], nil
+}
+
+func (r *postgresScheduledTransferRepository) SaveExecution(ctx context.Context, execution *domain.ScheduledTransferExecution) error {
+	return conn(ctx, r.db).Create(execution).Error
+}
+
+func (r *postgresScheduledTransferRepository) ListExecutions(ctx context.Context, scheduleID string, limit int) ([]domain.ScheduledTransferExecution, error) {
+	var executions []domain.ScheduledTransferExecution
+	err := conn(ctx, r.db).
+		Where("schedule_id = ?", scheduleID).
+		Order("scheduled_for DESC").
+		Limit(limit).
+		Find(&executions).Error
+	return executions, err
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// models lists the domain models stored by this package. VerifySchema checks
+// the migrated tables against them, so a new model or column must come with a
+// migration.
+var models = []interface{}{
+	&domain.User{},
+	&domain.Wallet{},
+	&domain.Currency{},
+	&domain.Movement{},
+	&domain.AuditEntry{},
+	&domain.ReconciliationRun{},
+	&domain.ReconciliationDiscrepancy{},
+	&domain.TransferBatch{},
+	&domain.TransferBatchItem{},
+	&domain.ScheduledTransfer{},
+	&domain.ScheduledTransferExecution{},
+	&domain.WalletProduct{},
+	&domain.InterestAccrual{},
+	&domain.PaymentRequest{},
+	&domain.UserAlias{},
+	&domain.WalletMember{},
+	&domain.Pocket{},
+	&domain.PocketMovement{},
+	&domain.MovementEvent{},
+	&domain.Merchant{},
+	&domain.MovementCategory{},
+	&domain.AnalyticsRollup{},
+	&domain.TransferReview{},
+	&domain.ScreeningHit{},
+	&domain.AdjustmentRequest{},
+	&domain.FeeCredit{},
+}
+
+// typeAliases maps the names PostgreSQL reports to the ones GORM generates.
+var typeAliases = map[string]string{
+	"numeric":                  "decimal",
+	"character varying":        "varchar",
+	"int2":                     "smallint",
+	"int4":                     "integer",
+	"int8":                     "bigint",
+	"bool":                     "boolean",
+	"timestamp with time zone": "timestamptz",
+}
+
+// VerifySchema reports every column of the GORM models that is missing from
+// the database or whose type or nullability differs from the model.
+func VerifySchema(ctx context.Context, db *gorm.DB) error {
+	db = db.WithContext(ctx)
+
+	var problems []error
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		table := stmt.Schema.Table
+
+		columns, err := db.Migrator().ColumnTypes(model)
+		if err != nil {
+			return fmt.Errorf("read columns of %s: %w", table, err)
+		}
+		if len(columns) == 0 {
+			problems = append(problems, fmt.Errorf("table %s does not exist", table))
+			continue
+		}
+		byName := make(map[string]gorm.ColumnType, len(columns))
+		for _, c := range columns {
+			byName[c.Name()] = c
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			column, ok := byName[field.DBName]
+			if !ok {
+				problems = append(problems, fmt.Errorf("%s.%s is missing", table, field.DBName))
+				continue
+			}
+
+			want := normalizeType(db.Dialector.DataTypeOf(field))
+			if got := columnType(column); got != want {
+				problems = append(problems, fmt.Errorf("%s.%s is %s, model expects %s", table, field.DBName, got, want))
+			}
+			if nullable, ok := column.Nullable(); ok && nullable && (field.NotNull || field.PrimaryKey) {
+				problems = append(problems, fmt.Errorf("%s.%s is nullable, model expects NOT NULL", table, field.DBName))
+			}
+		}
+	}
+	return errors.Join(problems...)
+}
+
+func columnType(column gorm.ColumnType) string {
+	name := normalizeType(column.DatabaseTypeName())
+	switch name {
+	case "varchar":
+		if length, ok := column.Length(); ok && length > 0 {
+			return fmt.Sprintf("varchar(%d)", length)
+		}
+	case "decimal":
+		if precision, scale, ok := column.DecimalSize(); ok && precision > 0 {
+			return fmt.Sprintf("decimal(%d,%d)", precision, scale)
+		}
+	}
+	return name
+}
+
+// normalizeType lowercases t, drops spaces and resolves aliases of its base
+// name, e.g. "NUMERIC(15, 2)" becomes "decimal(15,2)".
+func normalizeType(t string) string {
+	t = strings.ToLower(t)
+	base, args, _ := strings.Cut(t, "(")
+	if alias, ok := typeAliases[strings.TrimSpace(base)]; ok {
+		base = alias
+	}
+	base = strings.ReplaceAll(base, " ", "")
+	if args == "" {
+		return base
+	}
+	return base + "(" + strings.ReplaceAll(args, " ", "")
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"wallet/db/migration"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDBSourceEnv names the PostgreSQL DSN the tests that need a database
+// run against; they are skipped when it is unset. Each test works in a schema
+// of its own, dropped afterwards, so any database the role can create schemas
+// in will do.
+const testDBSourceEnv = "TEST_DB_SOURCE"
+
+// openTestSchema connects to TEST_DB_SOURCE with a new, empty schema as the
+// search path.
+func openTestSchema(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv(testDBSourceEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDBSourceEnv)
+	}
+	config := &gorm.Config{Logger: logger.Discard}
+
+	admin, err := gorm.Open(postgres.Open(dsn), config)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	schema := fmt.Sprintf("schema_test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	// search_path is a connection parameter, so every pooled connection,
+	// including the migrator's own, starts in the test schema.
+	if strings.Contains(dsn, "://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn += sep + "search_path=" + schema
+	} else {
+		dsn += " search_path=" + schema
+	}
+	db, err := gorm.Open(postgres.Open(dsn), config)
+	if err != nil {
+		t.Fatalf("connect to schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// The embedded migrations must produce exactly the columns the GORM models
+// expect, and roll back cleanly so they can be applied again.
+func TestMigrationsMatchModels(t *testing.T) {
+	db := openTestSchema(t)
+	ctx := context.Background()
+
+	migrator, err := NewMigrator(db, migration.FS, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	if err := VerifySchema(ctx, db); err != nil {
+		t.Fatalf("schema differs from the models:\n%v", err)
+	}
+
+	if err := migrator.To(ctx, 0); err != nil {
+		t.Fatalf("migrate down: %v", err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrate up again: %v", err)
+	}
+	if err := VerifySchema(ctx, db); err != nil {
+		t.Fatalf("schema differs from the models after migrating down and up:\n%v", err)
+	}
+}
+
+// A database AutoMigrate created before the migrations existed has the users
+// and wallets tables but no version and no foreign key between them. Migrating
+// adopts it instead of failing on the existing tables.
+func TestMigrationsAdoptAutoMigratedDatabase(t *testing.T) {
+	db := openTestSchema(t)
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		`CREATE TABLE "users" ("id" uuid PRIMARY KEY, "username" varchar(255) UNIQUE NOT NULL, "name" varchar(255) NOT NULL, "dni" varchar(255) UNIQUE NOT NULL, "created_at" timestamptz, "updated_at" timestamptz)`,
+		`CREATE TABLE "wallets" ("id" uuid PRIMARY KEY, "user_id" uuid NOT NULL, "currency" varchar(3) NOT NULL DEFAULT 'USD', "balance" decimal(15,2) NOT NULL DEFAULT 0, "created_at" timestamptz, "updated_at" timestamptz)`,
+		`CREATE INDEX "idx_wallets_user_id" ON "wallets" ("user_id")`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("create AutoMigrate tables: %v", err)
+		}
+	}
+
+	migrator, err := NewMigrator(db, migration.FS, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	if err := VerifySchema(ctx, db); err != nil {
+		t.Fatalf("schema differs from the models:\n%v", err)
+	}
+
+	var foreignKeys int64
+	err = db.Raw(`SELECT count(*) FROM pg_constraint WHERE conname = 'fk_wallets_users' AND conrelid = '"wallets"'::regclass`).Scan(&foreignKeys).Error
+	if err != nil {
+		t.Fatalf("look up foreign key: %v", err)
+	}
+	if foreignKeys != 1 {
+		t.Errorf("fk_wallets_users constraints = %d, want 1", foreignKeys)
+	}
+}
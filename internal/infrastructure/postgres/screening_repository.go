@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresScreeningRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresScreeningRepository reads hits from the primary: they decide
+// whether money can move, so they must never be stale.
+func NewPostgresScreeningRepository(db *gorm.DB) domain.ScreeningRepository {
+	return &postgresScreeningRepository{db: db}
+}
+
+func (r *postgresScreeningRepository) SaveHits(ctx context.Context, hits []domain.ScreeningHit) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	return mapError(conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "entry_id"}},
+		DoNothing: true,
+	}).Create(&hits).Error)
+}
+
+func (r *postgresScreeningRepository) ListByUsers(ctx context.Context, userIDs []string) ([]domain.ScreeningHit, error) {
+	var hits []domain.ScreeningHit
+	if len(userIDs) == 0 {
+		return hits, nil
+	}
+	err := conn(ctx, r.db).Where("user_id IN ?", userIDs).Order("created_at").Find(&hits).Error
+	return hits, err
+}
+
+func (r *postgresScreeningRepository) FindByID(ctx context.Context, id string) (*domain.ScreeningHit, error) {
+	return r.find(conn(ctx, r.db), id)
+}
+
+func (r *postgresScreeningRepository) FindByIDForUpdate(ctx context.Context, id string) (*domain.ScreeningHit, error) {
+	return r.find(conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func (r *postgresScreeningRepository) find(db *gorm.DB, id string) (*domain.ScreeningHit, error) {
+	var hit domain.ScreeningHit
+	err := db.Where("id = ?", id).First(&hit).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrHitNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hit, nil
+}
+
+func (r *postgresScreeningRepository) List(ctx context.Context, status domain.ScreeningHitStatus) ([]domain.ScreeningHit, error) {
+	query := conn(ctx, r.db).Order("created_at")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var hits []domain.ScreeningHit
+	err := query.Find(&hits).Error
+	return hits, err
+}
+
+// Resolve only updates a hit that is still pending, so a hit is never decided
+// on twice.
+func (r *postgresScreeningRepository) Resolve(ctx context.Context, hit *domain.ScreeningHit) error {
+	result := conn(ctx, r.db).Model(&domain.ScreeningHit{}).
+		Where("id = ? AND status = ?", hit.ID, domain.HitPending).
+		Updates(map[string]interface{}{
+			"status":      hit.Status,
+			"reviewed_by": hit.ReviewedBy,
+			"reviewed_at": hit.ReviewedAt,
+			"note":        hit.Note,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return mapError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrHitNotPending
+	}
+	return nil
+}
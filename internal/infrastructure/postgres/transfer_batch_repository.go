@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresTransferBatchRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresTransferBatchRepository(db *gorm.DB) domain.TransferBatchRepository {
+	return &postgresTransferBatchRepository{db: db}
+}
+
+func (r *postgresTransferBatchRepository) Save(ctx context.Context, batch *domain.TransferBatch, items []domain.TransferBatchItem) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(&items, 500).Error
+	})
+}
+
+func (r *postgresTransferBatchRepository) FindByID(ctx context.Context, id string) (*domain.TransferBatch, error) {
+	var batch domain.TransferBatch
+	err := conn(ctx, r.db).Where("id = ?", id).First(&batch).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrTransferBatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *postgresTransferBatchRepository) ListItems(ctx context.Context, batchID string) ([]domain.TransferBatchItem, error) {
+	var items []domain.TransferBatchItem
+	err := conn(ctx, r.db).Where("batch_id = ?", batchID).Order("position").Find(&items).Error
+	return items, err
+}
+
+// Claim locks the candidate row with SKIP LOCKED, so workers polling at the
+// same time each get a different batch instead of waiting on each other.
+func (r *postgresTransferBatchRepository) Claim(ctx context.Context, staleBefore time.Time) (*domain.TransferBatch, error) {
+	var claimed *domain.TransferBatch
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var batches []domain.TransferBatch
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND claimed_at < ?)",
+				domain.TransferBatchPending, domain.TransferBatchProcessing, staleBefore).
+			Order("created_at").
+			Limit(1).
+			Find(&batches).Error
+		if err != nil || len(batches) == 0 {
+			return err
+		}
+
+		batch := &batches[0]
+		now := time.Now()
+		batch.Status = domain.TransferBatchProcessing
+		batch.ClaimedAt = &now
+		if err := tx.Model(batch).Select("status", "claimed_at").Updates(batch).Error; err != nil {
+			return err
+		}
+		claimed = batch
+		return nil
+	})
+	return claimed, err
+}
+
+func (r *postgresTransferBatchRepository) Touch(ctx context.Context, batchID string) error {
+	return conn(ctx, r.db).Model(&domain.TransferBatch{}).
+		Where("id = ?", batchID).
+		Update("claimed_at", time.Now()).Error
+}
+
+// UpdateItem only changes pending items: when two workers race on a batch
+// whose claim went stale, the loser's transaction is rolled back instead of
+// applying the transfer twice.
+func (r *postgresTransferBatchRepository) UpdateItem(ctx context.Context, item *domain.TransferBatchItem) error {
+	result := conn(ctx, r.db).Model(&domain.TransferBatchItem{}).
+		Where("id = ? AND status = ?", item.ID, domain.TransferItemPending).
+		Updates(map[string]interface{}{"status": item.Status, "error": item.Error, "review_id": item.ReviewID, "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTransferBatchItemProcessed
+	}
+	return nil
+}
+
+func (r *postgresTransferBatchRepository) Finish(ctx context.Context, batch *domain.TransferBatch) error {
+	return conn(ctx, r.db).Model(batch).
+		Select("status", "succeeded", "failed", "held", "finished_at").
+		Updates(batch).Error
+}
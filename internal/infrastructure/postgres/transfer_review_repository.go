@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// transferHistoryQuery reads the sender's transfers in one pass over its
+// movements; the known recipient check isn't bounded by the lookback.
+const transferHistoryQuery = `
+SELECT
+	COUNT(*) FILTER (WHERE created_at >= ?) AS recent,
+	COUNT(*) FILTER (WHERE created_at >= ?) AS count,
+	COALESCE(AVG(-amount) FILTER (WHERE created_at >= ?), 0) AS average,
+	EXISTS (
+		SELECT 1 FROM movements
+		WHERE wallet_id = ? AND type = ? AND counterparty_wallet_id = ?
+	) AS known_recipient
+FROM movements
+WHERE wallet_id = ? AND type = ? AND created_at >= LEAST(?::timestamptz, ?::timestamptz)`
+
+type postgresTransferReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresTransferReviewRepository(db *gorm.DB) domain.TransferReviewRepository {
+	return &postgresTransferReviewRepository{db: db}
+}
+
+func (r *postgresTransferReviewRepository) Save(ctx context.Context, review *domain.TransferReview) error {
+	return mapError(conn(ctx, r.db).Create(review).Error)
+}
+
+func (r *postgresTransferReviewRepository) FindByID(ctx context.Context, id string) (*domain.TransferReview, error) {
+	return r.find(conn(ctx, r.db), id)
+}
+
+func (r *postgresTransferReviewRepository) FindByIDForUpdate(ctx context.Context, id string) (*domain.TransferReview, error) {
+	return r.find(conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func (r *postgresTransferReviewRepository) find(db *gorm.DB, id string) (*domain.TransferReview, error) {
+	var review domain.TransferReview
+	err := db.Where("id = ?", id).First(&review).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *postgresTransferReviewRepository) List(ctx context.Context, status domain.TransferReviewStatus) ([]domain.TransferReview, error) {
+	query := conn(ctx, r.db).Order("created_at")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var reviews []domain.TransferReview
+	err := query.Find(&reviews).Error
+	return reviews, err
+}
+
+// Resolve only updates a review that is still pending, so a transfer is never
+// decided on twice.
+func (r *postgresTransferReviewRepository) Resolve(ctx context.Context, review *domain.TransferReview) error {
+	result := conn(ctx, r.db).Model(&domain.TransferReview{}).
+		Where("id = ? AND status = ?", review.ID, domain.ReviewPending).
+		Updates(map[string]interface{}{
+			"status":      review.Status,
+			"reviewed_by": review.ReviewedBy,
+			"reviewed_at": review.ReviewedAt,
+			"note":        review.Note,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return mapError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrReviewNotPending
+	}
+	return nil
+}
+
+func (r *postgresTransferReviewRepository) TransferHistory(ctx context.Context, walletID, toWalletID string, recentSince, historySince time.Time) (*domain.TransferHistory, error) {
+	var history domain.TransferHistory
+	err := conn(ctx, r.db).Raw(transferHistoryQuery,
+		recentSince, historySince, historySince,
+		walletID, domain.MovementTransferOut, toWalletID,
+		walletID, domain.MovementTransferOut, recentSince, historySince).
+		Scan(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
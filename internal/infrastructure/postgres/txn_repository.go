@@ -16,8 +16,24 @@ func NewPostgresTxnRepository(db *gorm.DB) domain.TxnRepository {
 }
 
 func (r *postgresTxnRepository) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// We're not using txCtx here, as GORM's Transaction handles context.
-		return fn(ctx)
+	// Nested calls join the outer transaction (as a savepoint), and their
+	// after-commit work waits for the outer commit.
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.Transaction(func(nested *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, nested))
+		})
+	}
+
+	txCtx, commit := domain.NewTransactionContext(ctx)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Repositories pick the transaction up from the context (see conn).
+		return fn(context.WithValue(txCtx, txKey{}, tx))
 	})
+	if err != nil {
+		// Conflicts may surface on any statement or on COMMIT itself.
+		return mapError(err)
+	}
+
+	commit(ctx)
+	return nil
 }
@@ -9,38 +9,68 @@ import (
 )
 
 type postgresUserRepository struct {
-	db *gorm.DB
+	db *Cluster
 }
 
-func NewPostgresUserRepository(db *gorm.DB) domain.UserRepository {
+// NewPostgresUserRepository serves user lookups from the read replica when
+// one is available (see Cluster). A user the replica doesn't know may have
+// just been created, so a miss there is looked up again on the primary before
+// reporting ErrUserNotFound, which callers may cache.
+func NewPostgresUserRepository(db *Cluster) domain.UserRepository {
 	return &postgresUserRepository{db: db}
 }
 
 // FindByID implements domain.UserRepository.
 func (p *postgresUserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
-	var user domain.User
-	if err := p.db.WithContext(ctx).Where("id = ?", id).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
-		}
-		return nil, err
-	}
-	return &user, nil
+	return p.first(ctx, "id = ?", id)
 }
 
 // FindByUsername implements domain.UserRepository.
 func (p *postgresUserRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return p.first(ctx, "username = ?", username)
+}
+
+// first returns the user matching query, confirming a miss on the replica
+// against the primary.
+func (p *postgresUserRepository) first(ctx context.Context, query string, args ...interface{}) (*domain.User, error) {
 	var user domain.User
-	if err := p.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+	err := p.db.read(ctx).Where(query, args...).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) && p.db.readsReplica(ctx) {
+		err = p.db.write(ctx).Where(query, args...).First(&user).Error
+	}
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, err
 	}
 	return &user, nil
 }
 
+// List implements domain.UserRepository.
+func (p *postgresUserRepository) List(ctx context.Context, afterID string, limit int) ([]domain.User, error) {
+	var users []domain.User
+	query := p.db.read(ctx).Order("id").Limit(limit)
+	if afterID != "" {
+		query = query.Where("id > ?", afterID)
+	}
+	err := query.Find(&users).Error
+	return users, err
+}
+
 // Save implements domain.UserRepository.
 func (p *postgresUserRepository) Save(ctx context.Context, user *domain.User) error {
-	return p.db.WithContext(ctx).Create(user).Error
+	return mapError(p.db.write(ctx).Create(user).Error)
+}
+
+// UpdateTier implements domain.UserRepository.
+func (p *postgresUserRepository) UpdateTier(ctx context.Context, id, tier string) error {
+	result := p.db.write(ctx).Model(&domain.User{}).Where("id = ?", id).Update("tier", tier)
+	if result.Error != nil {
+		return mapError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
 }
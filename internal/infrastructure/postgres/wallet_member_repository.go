@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"wallet/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type postgresWalletMemberRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresWalletMemberRepository(db *gorm.DB) domain.WalletMemberRepository {
+	return &postgresWalletMemberRepository{db: db}
+}
+
+func (r *postgresWalletMemberRepository) Save(ctx context.Context, member *domain.WalletMember) error {
+	return mapError(conn(ctx, r.db).Create(member).Error)
+}
+
+func (r *postgresWalletMemberRepository) Find(ctx context.Context, walletID, userID string) (*domain.WalletMember, error) {
+	var member domain.WalletMember
+	err := conn(ctx, r.db).Where("wallet_id = ? AND user_id = ?", walletID, userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *postgresWalletMemberRepository) ListByWallet(ctx context.Context, walletID string) ([]domain.WalletMember, error) {
+	var members []domain.WalletMember
+	err := conn(ctx, r.db).Where("wallet_id = ?", walletID).Order("created_at, id").Find(&members).Error
+	return members, err
+}
+
+func (r *postgresWalletMemberRepository) Activate(ctx context.Context, walletID, userID string) error {
+	result := conn(ctx, r.db).Model(&domain.WalletMember{}).
+		Where("wallet_id = ? AND user_id = ? AND status = ?", walletID, userID, domain.MemberInvited).
+		Update("status", domain.MemberActive)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *postgresWalletMemberRepository) Delete(ctx context.Context, walletID, userID string) error {
+	result := conn(ctx, r.db).Where("wallet_id = ? AND user_id = ?", walletID, userID).Delete(&domain.WalletMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrMemberNotFound
+	}
+	return nil
+}
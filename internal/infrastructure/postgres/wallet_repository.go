@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"time"
 	"wallet/internal/domain"
 
 	"gorm.io/gorm"
@@ -17,14 +18,14 @@ func NewPostgresWalletRepository(db *gorm.DB) domain.WalletRepository {
 }
 
 func (r *postgresWalletRepository) Save(ctx context.Context, wallet *domain.Wallet) error {
-	return r.db.WithContext(ctx).Create(wallet).Error
+	return mapError(conn(ctx, r.db).Create(wallet).Error)
 }
 
 func (r *postgresWalletRepository) FindByID(ctx context.Context, id string) (*domain.Wallet, error) {
 	var wallet domain.Wallet
-	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&wallet).Error; err != nil {
+	if err := conn(ctx, r.db).Where("id = ?", id).First(&wallet).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("wallet not found")
+			return nil, domain.ErrWalletNotFound
 		}
 		return nil, err
 	}
@@ -33,15 +34,60 @@ func (r *postgresWalletRepository) FindByID(ctx context.Context, id string) (*do
 
 func (r *postgresWalletRepository) FindByUserID(ctx context.Context, userID string) (*domain.Wallet, error) {
 	var wallet domain.Wallet
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&wallet).Error; err != nil {
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).First(&wallet).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("wallet not found for user")
+			return nil, domain.ErrWalletNotFound
 		}
 		return nil, err
 	}
 	return &wallet, nil
 }
 
+func (r *postgresWalletRepository) FindByUserIDAndCurrency(ctx context.Context, userID, currency string) (*domain.Wallet, error) {
+	var wallet domain.Wallet
+	if err := conn(ctx, r.db).Where("user_id = ? AND currency = ?", userID, currency).First(&wallet).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrWalletNotFound
+		}
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (r *postgresWalletRepository) List(ctx context.Context, afterID string, limit int) ([]domain.Wallet, error) {
+	var wallets []domain.Wallet
+	query := conn(ctx, r.db).Order("id").Limit(limit)
+	if afterID != "" {
+		query = query.Where("id > ?", afterID)
+	}
+	err := query.Find(&wallets).Error
+	return wallets, err
+}
+
+// Update is a conditional UPDATE ... WHERE version = ?, so a wallet read
+// before a concurrent change can't overwrite it.
 func (r *postgresWalletRepository) Update(ctx context.Context, wallet *domain.Wallet) error {
-	return r.db.WithContext(ctx).Save(wallet).Error
+	now := time.Now()
+	result := conn(ctx, r.db).Model(&domain.Wallet{}).
+		Where("id = ? AND version = ?", wallet.ID, wallet.Version).
+		Updates(map[string]interface{}{
+			"currency":        wallet.Currency,
+			"balance":         wallet.Balance,
+			"pocketed":        wallet.Pocketed,
+			"overdraft_limit": wallet.OverdraftLimit,
+			"status":          wallet.Status,
+			"product":         wallet.Product,
+			"version":         gorm.Expr("version + 1"),
+			"updated_at":      now,
+		})
+	if result.Error != nil {
+		return mapError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrConcurrentModification
+	}
+
+	wallet.Version++
+	wallet.UpdatedAt = now
+	return nil
 }
@@ -13,16 +13,10 @@ type redisCacheRepository struct {
 	client *redis.Client
 }
 
-func NewRedisCacheRepository(addr string) (domain.CacheRepository, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr: addr,
-	})
-
-	if err := client.Ping(context.Background()).Err(); err != nil {
-		return nil, err
-	}
-
-	return &redisCacheRepository{client: client}, nil
+// NewRedisCacheRepository stores values as JSON in Redis. Wrap it with
+// NewCircuitBreakerCacheRepository to stop calling a cache that is down.
+func NewRedisCacheRepository(client *redis.Client) domain.CacheRepository {
+	return &redisCacheRepository{client: client}
 }
 
 func (r *redisCacheRepository) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
@@ -37,3 +31,11 @@ func (r *redisCacheRepository) Set(ctx context.Context, key string, value interf
 func (r *redisCacheRepository) Get(ctx context.Context, key string) (string, error) {
 	return r.client.Get(ctx, key).Result()
 }
+
+func (r *redisCacheRepository) Delete(ctx context.Context, keys ...string) error {
+	return r.client.Del(ctx, keys...).Err()
+}
+
+func (r *redisCacheRepository) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
@@ -0,0 +1,216 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case stateOpen:
+		return "open"
+	case stateHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreakerCacheRepository protects callers from a failing cache. After
+// failureThreshold consecutive failures it opens and every call fails fast with
+// domain.ErrCacheUnavailable. Once openTimeout has elapsed a single probe call
+// is let through: success closes the breaker, failure keeps it open.
+type circuitBreakerCacheRepository struct {
+	next             domain.CacheRepository
+	logger           *slog.Logger
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreakerCacheRepository(next domain.CacheRepository, logger *slog.Logger, failureThreshold int, openTimeout time.Duration) domain.CacheRepository {
+	return &circuitBreakerCacheRepository{
+		next:             next,
+		logger:           logger,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+	}
+}
+
+func (b *circuitBreakerCacheRepository) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if !b.allow() {
+		return domain.ErrCacheUnavailable
+	}
+	err := b.next.Set(ctx, key, value, ttl)
+	b.record(err)
+	return err
+}
+
+func (b *circuitBreakerCacheRepository) Get(ctx context.Context, key string) (string, error) {
+	if !b.allow() {
+		return "", domain.ErrCacheUnavailable
+	}
+	value, err := b.next.Get(ctx, key)
+	b.record(err)
+	return value, err
+}
+
+func (b *circuitBreakerCacheRepository) Delete(ctx context.Context, keys ...string) error {
+	if !b.allow() {
+		return domain.ErrCacheUnavailable
+	}
+	err := b.next.Delete(ctx, keys...)
+	b.record(err)
+	return err
+}
+
+// Ping goes through the breaker too, so readiness reports the cache as down
+// while the breaker is open and can act as the recovery probe.
+func (b *circuitBreakerCacheRepository) Ping(ctx context.Context) error {
+	if !b.allow() {
+		return domain.ErrCacheUnavailable
+	}
+	err := b.next.Ping(ctx)
+	b.record(err)
+	return err
+}
+
+// circuitBreakerLocker takes its locks through the breaker of the cache they
+// live in, so lock calls fail fast while the cache is down, and their
+// failures count towards opening it.
+type circuitBreakerLocker struct {
+	breaker *circuitBreakerCacheRepository
+	next    domain.Locker
+}
+
+// NewCircuitBreakerLocker guards next with the breaker of cache, which must
+// come from NewCircuitBreakerCacheRepository and wrap the same Redis. While
+// the breaker is open TryLock returns domain.ErrCacheUnavailable. Any other
+// cache is taken as having no breaker, and next is returned as is.
+func NewCircuitBreakerLocker(cache domain.CacheRepository, next domain.Locker) domain.Locker {
+	breaker, ok := cache.(*circuitBreakerCacheRepository)
+	if !ok {
+		return next
+	}
+	return &circuitBreakerLocker{breaker: breaker, next: next}
+}
+
+func (l *circuitBreakerLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, bool, error) {
+	if !l.breaker.allow() {
+		return nil, false, domain.ErrCacheUnavailable
+	}
+	unlock, ok, err := l.next.TryLock(ctx, key, ttl)
+	l.breaker.record(err)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	guarded := func(ctx context.Context) error {
+		// An open breaker leaves the lock to expire on its own after ttl.
+		if !l.breaker.allow() {
+			return domain.ErrCacheUnavailable
+		}
+		err := unlock(ctx)
+		l.breaker.record(err)
+		return err
+	}
+	return guarded, true, nil
+}
+
+// allow reports whether a call may reach the cache.
+func (b *circuitBreakerCacheRepository) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.transition(stateHalfOpen)
+		b.probing = true
+		return true
+	case stateHalfOpen:
+		// Only one probe at a time while we find out whether the cache recovered.
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record updates the breaker with the outcome of a call.
+func (b *circuitBreakerCacheRepository) record(err error) {
+	// A miss or a caller giving up says nothing about the health of the cache.
+	failed := err != nil &&
+		!errors.Is(err, redis.Nil) &&
+		!errors.Is(err, context.Canceled)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateHalfOpen:
+		b.probing = false
+		if failed {
+			b.open(err)
+			return
+		}
+		b.failures = 0
+		b.transition(stateClosed)
+	case stateClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.failureThreshold {
+			b.open(err)
+		}
+	}
+}
+
+func (b *circuitBreakerCacheRepository) open(cause error) {
+	b.openedAt = time.Now()
+	b.logger.Warn("cache circuit breaker opened, bypassing cache",
+		"failures", b.failures,
+		"retry_in", b.openTimeout.String(),
+		"error", cause,
+	)
+	b.transition(stateOpen)
+}
+
+// transition must be called with mu held.
+func (b *circuitBreakerCacheRepository) transition(to breakerState) {
+	if b.state == to {
+		return
+	}
+	b.logger.Info("cache circuit breaker state changed",
+		"component", "redis",
+		"from", b.state.String(),
+		"to", to.String(),
+	)
+	b.state = to
+}
@@ -0,0 +1,260 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var errDown = errors.New("connection refused")
+
+// downCache fails every call, like a Redis that went away.
+type downCache struct{}
+
+func (downCache) Set(context.Context, string, interface{}, time.Duration) error { return errDown }
+func (downCache) Get(context.Context, string) (string, error)                   { return "", errDown }
+func (downCache) Delete(context.Context, ...string) error                       { return errDown }
+func (downCache) Ping(context.Context) error                                    { return errDown }
+
+// countingLocker records the calls reaching Redis.
+type countingLocker struct {
+	calls int
+	err   error
+}
+
+func (l *countingLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, bool, error) {
+	l.calls++
+	if l.err != nil {
+		return nil, false, l.err
+	}
+	return func(context.Context) error { return nil }, true, nil
+}
+
+func newTestBreaker() domain.CacheRepository {
+	return NewCircuitBreakerCacheRepository(downCache{}, slog.New(slog.NewTextHandler(io.Discard, nil)), 2, time.Minute)
+}
+
+func TestCircuitBreakerLockerFailsFastWhileOpen(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestBreaker()
+	next := &countingLocker{}
+	locker := NewCircuitBreakerLocker(cache, next)
+
+	for range 2 {
+		cache.Get(ctx, "key")
+	}
+	_, ok, err := locker.TryLock(ctx, "lock:key", time.Second)
+	if ok || !errors.Is(err, domain.ErrCacheUnavailable) {
+		t.Fatalf("TryLock = %v, %v; want ErrCacheUnavailable", ok, err)
+	}
+	if next.calls != 0 {
+		t.Errorf("Redis got %d lock calls while the breaker was open", next.calls)
+	}
+}
+
+func TestCircuitBreakerLockerFailuresOpenTheBreaker(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestBreaker()
+	locker := NewCircuitBreakerLocker(cache, &countingLocker{err: errDown})
+
+	for range 2 {
+		if _, _, err := locker.TryLock(ctx, "lock:key", time.Second); !errors.Is(err, errDown) {
+			t.Fatalf("TryLock error = %v, want %v", err, errDown)
+		}
+	}
+	if _, err := cache.Get(ctx, "key"); !errors.Is(err, domain.ErrCacheUnavailable) {
+		t.Errorf("Get error = %v, want ErrCacheUnavailable once lock failures opened the breaker", err)
+	}
+}
+
+func TestCircuitBreakerLockerWithoutBreaker(t *testing.T) {
+	next := &countingLocker{}
+	if got := NewCircuitBreakerLocker(downCache{}, next); got != domain.Locker(next) {
+		t.Errorf("NewCircuitBreakerLocker wrapped a cache without a breaker")
+	}
+}
+
+// scriptedCache answers every call with err and counts the calls reaching it.
+type scriptedCache struct {
+	err   error
+	calls int
+}
+
+func (c *scriptedCache) Set(context.Context, string, interface{}, time.Duration) error {
+	c.calls++
+	return c.err
+}
+
+func (c *scriptedCache) Get(context.Context, string) (string, error) {
+	c.calls++
+	return "", c.err
+}
+
+func (c *scriptedCache) Delete(context.Context, ...string) error {
+	c.calls++
+	return c.err
+}
+
+func (c *scriptedCache) Ping(context.Context) error {
+	c.calls++
+	return c.err
+}
+
+// breakerStep is one call through the breaker, made with the cache answering
+// err, after the open timeout has elapsed when cooldown is set.
+type breakerStep struct {
+	call     string // get, set, delete or ping
+	err      error
+	cooldown bool
+	// What the caller gets, whether the call reached the cache, and the
+	// state of the breaker after it.
+	wantErr     error
+	wantReached bool
+	wantState   breakerState
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	fail := func(call string, state breakerState) breakerStep {
+		return breakerStep{call: call, err: errDown, wantErr: errDown, wantReached: true, wantState: state}
+	}
+	succeed := func(call string, state breakerState) breakerStep {
+		return breakerStep{call: call, wantReached: true, wantState: state}
+	}
+	failFast := func(call string) breakerStep {
+		return breakerStep{call: call, wantErr: domain.ErrCacheUnavailable, wantState: stateOpen}
+	}
+	probe := func(step breakerStep) breakerStep {
+		step.cooldown = true
+		return step
+	}
+
+	tests := []struct {
+		name  string
+		steps []breakerStep
+	}{
+		{"stays closed below the threshold", []breakerStep{
+			fail("get", stateClosed), fail("set", stateClosed),
+			succeed("get", stateClosed),
+			fail("get", stateClosed), fail("delete", stateClosed),
+		}},
+		{"misses and cancellations are not failures", []breakerStep{
+			{call: "get", err: redis.Nil, wantErr: redis.Nil, wantReached: true, wantState: stateClosed},
+			{call: "get", err: redis.Nil, wantErr: redis.Nil, wantReached: true, wantState: stateClosed},
+			{call: "get", err: context.Canceled, wantErr: context.Canceled, wantReached: true, wantState: stateClosed},
+			{call: "get", err: redis.Nil, wantErr: redis.Nil, wantReached: true, wantState: stateClosed},
+		}},
+		{"opens after consecutive failures and fails fast", []breakerStep{
+			fail("get", stateClosed), fail("set", stateClosed), fail("get", stateOpen),
+			failFast("get"), failFast("set"), failFast("delete"), failFast("ping"),
+		}},
+		{"a successful probe closes it", []breakerStep{
+			fail("get", stateClosed), fail("get", stateClosed), fail("get", stateOpen),
+			failFast("get"),
+			probe(succeed("get", stateClosed)),
+			succeed("set", stateClosed),
+		}},
+		{"a failed probe opens it again", []breakerStep{
+			fail("get", stateClosed), fail("get", stateClosed), fail("get", stateOpen),
+			probe(fail("ping", stateOpen)),
+			failFast("get"),
+			probe(succeed("ping", stateClosed)),
+		}},
+		{"a probe answering a miss closes it", []breakerStep{
+			fail("get", stateClosed), fail("get", stateClosed), fail("get", stateOpen),
+			probe(breakerStep{call: "get", err: redis.Nil, wantErr: redis.Nil, wantReached: true, wantState: stateClosed}),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := &scriptedCache{}
+			b := NewCircuitBreakerCacheRepository(cache, slog.New(slog.NewTextHandler(io.Discard, nil)), 3, time.Minute).(*circuitBreakerCacheRepository)
+
+			for i, step := range tt.steps {
+				if step.cooldown {
+					b.openedAt = time.Now().Add(-b.openTimeout)
+				}
+				cache.err = step.err
+				calls := cache.calls
+
+				var err error
+				switch step.call {
+				case "get":
+					_, err = b.Get(ctx, "key")
+				case "set":
+					err = b.Set(ctx, "key", "value", time.Minute)
+				case "delete":
+					err = b.Delete(ctx, "key")
+				case "ping":
+					err = b.Ping(ctx)
+				}
+
+				if !errors.Is(err, step.wantErr) || (err == nil) != (step.wantErr == nil) {
+					t.Errorf("step %d (%s): error = %v, want %v", i, step.call, err, step.wantErr)
+				}
+				if reached := cache.calls > calls; reached != step.wantReached {
+					t.Errorf("step %d (%s): reached the cache = %v, want %v", i, step.call, reached, step.wantReached)
+				}
+				if b.state != step.wantState {
+					t.Errorf("step %d (%s): state = %s, want %s", i, step.call, b.state, step.wantState)
+				}
+			}
+		})
+	}
+}
+
+// blockingCache holds every Get until release is closed.
+type blockingCache struct {
+	scriptedCache
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingCache) Get(ctx context.Context, key string) (string, error) {
+	c.entered <- struct{}{}
+	<-c.release
+	return "", nil
+}
+
+// While half open, a single probe reaches the cache; the other calls keep
+// failing fast until it answers.
+func TestCircuitBreakerLetsOneProbeThrough(t *testing.T) {
+	ctx := context.Background()
+	cache := &blockingCache{entered: make(chan struct{}), release: make(chan struct{})}
+	b := NewCircuitBreakerCacheRepository(cache, slog.New(slog.NewTextHandler(io.Discard, nil)), 1, time.Minute).(*circuitBreakerCacheRepository)
+
+	cache.err = errDown
+	b.Set(ctx, "key", "value", time.Minute)
+	if b.state != stateOpen {
+		t.Fatalf("state = %s after a failure with a threshold of 1, want open", b.state)
+	}
+	b.openedAt = time.Now().Add(-b.openTimeout)
+
+	done := make(chan error)
+	go func() {
+		_, err := b.Get(ctx, "key")
+		done <- err
+	}()
+	<-cache.entered
+
+	if _, err := b.Get(ctx, "key"); !errors.Is(err, domain.ErrCacheUnavailable) {
+		t.Errorf("Get during the probe = %v, want ErrCacheUnavailable", err)
+	}
+	if err := b.Set(ctx, "key", "value", time.Minute); !errors.Is(err, domain.ErrCacheUnavailable) {
+		t.Errorf("Set during the probe = %v, want ErrCacheUnavailable", err)
+	}
+
+	close(cache.release)
+	if err := <-done; err != nil {
+		t.Fatalf("probe = %v, want it to succeed", err)
+	}
+	if b.state != stateClosed {
+		t.Errorf("state = %s after a successful probe, want closed", b.state)
+	}
+}
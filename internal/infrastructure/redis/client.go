@@ -0,0 +1,20 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NewClient creates a Redis client shared by the cache and the locker. It does
+// not require Redis to be reachable: the client connects lazily, and short
+// timeouts keep an outage from adding latency to requests.
+func NewClient(addr string) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:         addr,
+		DialTimeout:  250 * time.Millisecond,
+		ReadTimeout:  200 * time.Millisecond,
+		WriteTimeout: 200 * time.Millisecond,
+		MaxRetries:   1,
+	})
+}
@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// unlockScript deletes the lock only if it still holds our token, so an
+// expired lock that someone else re-acquired is never released by us.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type redisLocker struct {
+	client *redis.Client
+}
+
+// NewRedisLocker implements domain.Locker with SET NX PX, so only one
+// instance of the service holds a given key at a time.
+func NewRedisLocker(client *redis.Client) domain.Locker {
+	return &redisLocker{client: client}
+}
+
+func (l *redisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, bool, error) {
+	token := uuid.New().String()
+	ok, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	unlock := func(ctx context.Context) error {
+		return unlockScript.Run(ctx, l.client, []string{key}, token).Err()
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// AccessLog writes one structured log line per request with its latency,
+// status code and the principal that performed it. It must be registered
+// after RequestID so the line carries the request ID.
+func AccessLog(logger *slog.Logger) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			// Let Fiber's error handler decide the final response, but log the status it will use.
+			status = fiber.StatusInternalServerError
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				status = fe.Code
+			}
+		}
+
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		logger.Log(c.Context(), level, "http request",
+			"method", c.Method(),
+			"path", c.Path(),
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds())/1000,
+			"principal", domain.ActorFromContext(c.Context()),
+			"ip", c.IP(),
+			"bytes_out", len(c.Response().Body()),
+		)
+		return err
+	}
+}
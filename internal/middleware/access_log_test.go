@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"wallet/internal/domain"
+	"wallet/internal/logging"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Every request is logged once with its status, principal and request ID, at
+// error level when it failed on the server.
+func TestAccessLog(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(logging.NewContextHandler(slog.NewJSONHandler(&out, nil)))
+
+	app := fiber.New()
+	app.Use(RequestID())
+	app.Use(AccessLog(logger))
+	app.Use(func(c fiber.Ctx) error {
+		c.SetContext(domain.WithActor(c.Context(), domain.UserActor("alice")))
+		return c.Next()
+	})
+	app.Get("/ok", func(c fiber.Ctx) error { return c.SendString("ok") })
+	app.Get("/teapot", func(c fiber.Ctx) error { return fiber.NewError(fiber.StatusTeapot) })
+	app.Get("/boom", func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusServiceUnavailable) })
+
+	tests := []struct {
+		path   string
+		status int
+		level  string
+	}{
+		{"/ok", fiber.StatusOK, "INFO"},
+		{"/teapot", fiber.StatusTeapot, "INFO"},
+		{"/boom", fiber.StatusServiceUnavailable, "ERROR"},
+	}
+	for _, tt := range tests {
+		out.Reset()
+		req := httptest.NewRequest(fiber.MethodGet, tt.path, nil)
+		req.Header.Set(fiber.HeaderXRequestID, "req-1")
+		if _, err := app.Test(req); err != nil {
+			t.Fatalf("GET %s: %v", tt.path, err)
+		}
+
+		var line struct {
+			Level     string `json:"level"`
+			Msg       string `json:"msg"`
+			Path      string `json:"path"`
+			Status    int    `json:"status"`
+			Principal string `json:"principal"`
+			RequestID string `json:"request_id"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+			t.Fatalf("GET %s logged %q, want one JSON line: %v", tt.path, out.String(), err)
+		}
+		want := line
+		want.Level, want.Msg, want.Path, want.Status, want.Principal, want.RequestID = tt.level, "http request", tt.path, tt.status, "user:alice", "req-1"
+		if line != want {
+			t.Errorf("GET %s logged %+v, want %+v", tt.path, line, want)
+		}
+	}
+}
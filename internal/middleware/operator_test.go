@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http/httptest"
+	"testing"
+	"wallet/internal/domain"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Admin requests need an operator's token, even when a user was already
+// authenticated.
+func TestOperator(t *testing.T) {
+	sum := sha256.Sum256([]byte("carol-token"))
+	tokens := map[string][]byte{"carol": sum[:]}
+
+	tests := []struct {
+		name      string
+		asUser    bool
+		header    string
+		wantCode  int
+		wantActor string
+	}{
+		{"valid", false, "Bearer carol-token", fiber.StatusOK, domain.OperatorActor("carol")},
+		{"valid as a user", true, "Bearer carol-token", fiber.StatusOK, domain.OperatorActor("carol")},
+		{"missing", false, "", fiber.StatusUnauthorized, ""},
+		{"missing as a user", true, "", fiber.StatusUnauthorized, ""},
+		{"invalid", false, "Bearer mallory-token", fiber.StatusUnauthorized, ""},
+		{"invalid as a user", true, "Bearer mallory-token", fiber.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			if tt.asUser {
+				app.Use(func(c fiber.Ctx) error {
+					c.SetContext(domain.WithActor(context.Background(), domain.UserActor(aliceID)))
+					return c.Next()
+				})
+			}
+			app.Use(Operator(tokens))
+			app.Get("/", func(c fiber.Ctx) error {
+				return c.SendString(domain.ActorFromContext(c.Context()))
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if resp.StatusCode != tt.wantCode {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+			if tt.wantActor == "" {
+				return
+			}
+			body := make([]byte, 256)
+			n, _ := resp.Body.Read(body)
+			if got := string(body[:n]); got != tt.wantActor {
+				t.Errorf("actor = %q, want %q", got, tt.wantActor)
+			}
+		})
+	}
+}
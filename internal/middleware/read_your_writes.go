@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"strings"
+	"wallet/internal/domain"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// HeaderReadYourWrites lets a client ask for its reads to be served by the
+// primary database, e.g. right after a write it wants to see reflected.
+const HeaderReadYourWrites = "X-Read-Your-Writes"
+
+// ReadYourWrites marks the request context with domain.WithReadYourWrites when
+// the client sends "X-Read-Your-Writes: true".
+func ReadYourWrites() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if strings.EqualFold(c.Get(HeaderReadYourWrites), "true") {
+			c.SetContext(domain.WithReadYourWrites(c.Context()))
+		}
+		return c.Next()
+	}
+}
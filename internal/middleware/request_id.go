@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"regexp"
+	"wallet/internal/logging"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// validRequestID restricts client supplied IDs so they are safe to log and echo back.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,128}$`)
+
+// RequestID accepts the X-Request-ID header sent by the client (or generates a
+// new one), stores it in the request context and in a per-request Sentry hub,
+// and echoes it in the response.
+func RequestID() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		id := c.Get(fiber.HeaderXRequestID)
+		if !validRequestID.MatchString(id) {
+			id = uuid.New().String()
+		}
+		c.Set(fiber.HeaderXRequestID, id)
+
+		ctx := logging.WithRequestID(c.Context(), id)
+
+		// Clone the hub so the tag only applies to events raised by this request.
+		hub := sentry.CurrentHub().Clone()
+		hub.Scope().SetTag("request_id", id)
+		ctx = sentry.SetHubOnContext(ctx, hub)
+
+		c.SetContext(ctx)
+		return c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"wallet/internal/logging"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// The request ID sent by the client is kept when safe to log, replaced
+// otherwise, and both echoed and stored in the request context.
+func TestRequestID(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequestID())
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(logging.RequestIDFromContext(c.Context()))
+	})
+
+	tests := []struct {
+		name string
+		sent string
+		kept bool
+	}{
+		{"valid", "req-42.a_b", true},
+		{"missing", "", false},
+		{"unsafe", "<script>alert(1)</script>", false},
+		{"too long", strings.Repeat("a", 129), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.sent != "" {
+				req.Header[fiber.HeaderXRequestID] = []string{tt.sent}
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			echoed := resp.Header.Get(fiber.HeaderXRequestID)
+			body := make([]byte, 256)
+			n, _ := resp.Body.Read(body)
+			if got := string(body[:n]); got != echoed {
+				t.Errorf("request ID in the context = %q, echoed %q", got, echoed)
+			}
+			if tt.kept && echoed != tt.sent {
+				t.Errorf("echoed %q, want the client's %q", echoed, tt.sent)
+			}
+			if !tt.kept && uuid.Validate(echoed) != nil {
+				t.Errorf("echoed %q, want a generated UUID", echoed)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"strings"
+	"wallet/internal/auth"
+	"wallet/internal/domain"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// User authenticates the user making the request with the user token in the
+// Authorization header (see auth.UserTokens), and sets them as the actor of
+// the request context. Their operations are attributed to them and limited to
+// the wallets they are members of. Requests without a token remain anonymous;
+// with a nil tokens, every token is refused.
+func User(tokens *auth.UserTokens) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || token == "" {
+			return c.Next()
+		}
+		if tokens == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": auth.ErrInvalidUserToken.Error()})
+		}
+		userID, err := tokens.Verify(token)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+		c.SetContext(domain.WithActor(c.Context(), domain.UserActor(userID)))
+		return c.Next()
+	}
+}
+
+// RequireUser refuses requests that User didn't authenticate. Use cases let
+// anonymous callers act on no wallet, but answering them here tells the
+// client what is missing.
+func RequireUser() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if _, ok := domain.UserFromContext(c.Context()); !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "a user token is required"})
+		}
+		return c.Next()
+	}
+}
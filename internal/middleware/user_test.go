@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+	"wallet/internal/auth"
+	"wallet/internal/domain"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const aliceID = "6f1c2a9e-3b4d-4e8a-9c1f-2d7b5e0a4c31"
+
+// userApp answers with the actor of the request, behind User and, when
+// required, RequireUser.
+func userApp(tokens *auth.UserTokens, required bool) *fiber.App {
+	app := fiber.New()
+	app.Use(User(tokens))
+	if required {
+		app.Use(RequireUser())
+	}
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(domain.ActorFromContext(c.Context()))
+	})
+	return app
+}
+
+// Only a token signed with the key makes a request act as a user; a missing
+// one leaves it anonymous unless a user is required.
+func TestUser(t *testing.T) {
+	tokens := auth.NewUserTokens([]byte("key"))
+	valid := "Bearer " + tokens.Sign(aliceID, time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name      string
+		tokens    *auth.UserTokens
+		required  bool
+		header    string
+		wantCode  int
+		wantActor string
+	}{
+		{"valid", tokens, true, valid, fiber.StatusOK, domain.UserActor(aliceID)},
+		{"anonymous", tokens, false, "", fiber.StatusOK, domain.AnonymousActor},
+		{"required", tokens, true, "", fiber.StatusUnauthorized, ""},
+		{"not a bearer", tokens, true, "Basic " + aliceID, fiber.StatusUnauthorized, ""},
+		{"bare user ID", tokens, false, "Bearer " + aliceID, fiber.StatusUnauthorized, ""},
+		{"other key", tokens, false, "Bearer " + auth.NewUserTokens([]byte("other")).Sign(aliceID, time.Now().Add(time.Hour)), fiber.StatusUnauthorized, ""},
+		{"expired", tokens, false, "Bearer " + tokens.Sign(aliceID, time.Now().Add(-time.Second)), fiber.StatusUnauthorized, ""},
+		{"no key", nil, false, valid, fiber.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.header)
+			}
+			resp, err := userApp(tt.tokens, tt.required).Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if resp.StatusCode != tt.wantCode {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+			if tt.wantActor == "" {
+				return
+			}
+			body := make([]byte, 256)
+			n, _ := resp.Body.Read(body)
+			if got := string(body[:n]); got != tt.wantActor {
+				t.Errorf("actor = %q, want %q", got, tt.wantActor)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package statement
+
+import (
+	"encoding/csv"
+	"fmt"
+	"time"
+	"wallet/internal/domain"
+)
+
+const csvComment = "# "
+
+// Entries of a CSV statement, in the first column.
+const (
+	entryOpening  = "opening"
+	entryMovement = "movement"
+	entryClosing  = "closing"
+)
+
+var csvHeader = []string{"entry", "date", "movement_id", "type", "reference", "counterparty_wallet_id", "amount", "balance"}
+
+// csvRenderer writes a statement as CSV. The statement details and the
+// signature are comment lines starting with "# ".
+type csvRenderer struct {
+	out       *signingWriter
+	w         *csv.Writer
+	statement *domain.Statement
+}
+
+func newCSVRenderer(out *signingWriter) *csvRenderer {
+	return &csvRenderer{out: out, w: csv.NewWriter(out)}
+}
+
+func (r *csvRenderer) Begin(s *domain.Statement) error {
+	r.statement = s
+	_, err := fmt.Fprintf(r.out, "%sstatement %s of wallet %s (%s) from %s to %s, generated %s\n",
+		csvComment, s.ID, s.WalletID, s.Currency, formatTime(s.From), formatTime(s.To), formatTime(s.GeneratedAt))
+	if err != nil {
+		return err
+	}
+	if err := r.w.Write(csvHeader); err != nil {
+		return err
+	}
+	return r.w.Write([]string{entryOpening, formatTime(s.From), "", "", "", "", "", formatAmount(s.OpeningBalance)})
+}
+
+func (r *csvRenderer) Movement(m *domain.Movement) error {
+	counterparty := ""
+	if m.CounterpartyWalletID != nil {
+		counterparty = *m.CounterpartyWalletID
+	}
+	return r.w.Write([]string{
+		entryMovement, formatTime(m.CreatedAt), m.ID, string(m.Type), m.Reference, counterparty,
+		formatAmount(m.Amount), formatAmount(m.BalanceAfter),
+	})
+}
+
+func (r *csvRenderer) End(closingBalance float64) error {
+	if err := r.w.Write([]string{entryClosing, formatTime(r.statement.To), "", "", "", "", "", formatAmount(closingBalance)}); err != nil {
+		return err
+	}
+	r.w.Flush()
+	if err := r.w.Error(); err != nil {
+		return err
+	}
+	return r.out.sign(csvComment)
+}
+
+func formatAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
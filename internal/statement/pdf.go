@@ -0,0 +1,224 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"wallet/internal/domain"
+)
+
+const pdfComment = "%"
+
+// A4 page layout, in points.
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	margin       = 50
+	lineHeight   = 14
+	fontSize     = 9
+	titleSize    = 14
+	amountColumn = 430 // right edge of the amount column
+	balanceEdge  = pageWidth - margin
+)
+
+// PDF objects with fixed numbers; pages get the following ones.
+const (
+	catalogObject = 1
+	pagesObject   = 2
+	fontObject    = 3
+	boldObject    = 4
+)
+
+// pdfRenderer writes a statement as a PDF document, one page at a time: only
+// the page being laid out is held in memory. The page tree is written last,
+// which PDF allows since objects are located through the cross-reference table.
+type pdfRenderer struct {
+	out       *signingWriter
+	statement *domain.Statement
+
+	offsets []int64 // byte offset of every object, indexed by number - 1
+	pages   []int   // object numbers of the pages written so far
+	page    bytes.Buffer
+	y       float64
+}
+
+func newPDFRenderer(out *signingWriter) *pdfRenderer {
+	return &pdfRenderer{out: out}
+}
+
+func (r *pdfRenderer) Begin(s *domain.Statement) error {
+	r.statement = s
+	r.offsets = make([]int64, boldObject)
+
+	if _, err := io.WriteString(r.out, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"); err != nil {
+		return err
+	}
+	if err := r.writeObject(catalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObject)); err != nil {
+		return err
+	}
+	if err := r.writeObject(fontObject, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"); err != nil {
+		return err
+	}
+	if err := r.writeObject(boldObject, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>"); err != nil {
+		return err
+	}
+
+	r.newPage()
+	r.text(margin, "F2", titleSize, "Wallet statement")
+	r.y -= lineHeight
+	r.text(margin, "F1", fontSize, "Wallet: "+s.WalletID+" ("+s.Currency+")")
+	r.text(margin, "F1", fontSize, "Period: "+formatTime(s.From)+" to "+formatTime(s.To))
+	r.text(margin, "F1", fontSize, "Statement: "+s.ID+", generated "+formatTime(s.GeneratedAt))
+	r.y -= lineHeight
+	r.row("F2", formatTime(s.From), "Opening balance", "", formatAmount(s.OpeningBalance))
+	return nil
+}
+
+func (r *pdfRenderer) Movement(m *domain.Movement) error {
+	if r.y < margin+lineHeight {
+		if err := r.flushPage(); err != nil {
+			return err
+		}
+		r.newPage()
+	}
+
+	description := string(m.Type)
+	if m.CounterpartyWalletID != nil {
+		description += " " + *m.CounterpartyWalletID
+	}
+	if m.Reference != "" {
+		description += " - " + m.Reference
+	}
+	r.row("F1", formatTime(m.CreatedAt), truncate(description, 60), formatAmount(m.Amount), formatAmount(m.BalanceAfter))
+	return nil
+}
+
+func (r *pdfRenderer) End(closingBalance float64) error {
+	if r.y < margin+2*lineHeight {
+		if err := r.flushPage(); err != nil {
+			return err
+		}
+		r.newPage()
+	}
+	r.y -= lineHeight / 2
+	r.row("F2", formatTime(r.statement.To), "Closing balance", "", formatAmount(closingBalance))
+	if err := r.flushPage(); err != nil {
+		return err
+	}
+
+	kids := make([]string, len(r.pages))
+	for i, page := range r.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	pages := fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(r.pages))
+	if err := r.writeObject(pagesObject, pages); err != nil {
+		return err
+	}
+
+	xref := r.out.n
+	var b strings.Builder
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(r.offsets)+1)
+	for _, offset := range r.offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(r.offsets)+1, catalogObject, xref)
+	if _, err := io.WriteString(r.out, b.String()); err != nil {
+		return err
+	}
+	return r.out.sign(pdfComment)
+}
+
+func (r *pdfRenderer) newPage() {
+	r.page.Reset()
+	r.y = pageHeight - margin
+}
+
+// flushPage writes the page laid out so far, with its number at the bottom.
+func (r *pdfRenderer) flushPage() error {
+	r.y = margin / 2
+	r.text(margin, "F1", fontSize, fmt.Sprintf("Page %d", len(r.pages)+1))
+
+	content := r.reserveObject()
+	if err := r.writeObject(content, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", r.page.Len(), r.page.String())); err != nil {
+		return err
+	}
+	page := r.reserveObject()
+	r.pages = append(r.pages, page)
+	return r.writeObject(page, fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+		pagesObject, pageWidth, pageHeight, fontObject, boldObject, content))
+}
+
+// row lays out a line of the movements table; amounts are right-aligned.
+func (r *pdfRenderer) row(font, date, description, amount, balance string) {
+	r.textAt(margin, r.y, font, fontSize, date)
+	r.textAt(margin+95, r.y, font, fontSize, description)
+	r.textAt(amountColumn-textWidth(amount, fontSize), r.y, font, fontSize, amount)
+	r.textAt(balanceEdge-textWidth(balance, fontSize), r.y, font, fontSize, balance)
+	r.y -= lineHeight
+}
+
+func (r *pdfRenderer) text(x float64, font string, size float64, s string) {
+	r.textAt(x, r.y, font, size, s)
+	r.y -= lineHeight
+}
+
+func (r *pdfRenderer) textAt(x, y float64, font string, size float64, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(&r.page, "BT /%s %g Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapeText(s))
+}
+
+func (r *pdfRenderer) reserveObject() int {
+	r.offsets = append(r.offsets, 0)
+	return len(r.offsets)
+}
+
+func (r *pdfRenderer) writeObject(number int, body string) error {
+	r.offsets[number-1] = r.out.n
+	_, err := fmt.Fprintf(r.out, "%d 0 obj\n%s\nendobj\n", number, body)
+	return err
+}
+
+// escapeText escapes s for a PDF string literal. Characters outside Latin-1
+// can't be shown with the standard fonts and are replaced with "?".
+func escapeText(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case c < 0x20 || c > 0xff:
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(c))
+		}
+	}
+	return b.String()
+}
+
+// textWidth is the width of an amount in Helvetica, whose digits share one width.
+func textWidth(s string, size float64) float64 {
+	var units float64
+	for _, c := range s {
+		switch c {
+		case '.', ',', ' ':
+			units += 278
+		case '-':
+			units += 333
+		default:
+			units += 556
+		}
+	}
+	return units * size / 1000
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
@@ -0,0 +1,71 @@
+package statement
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+)
+
+// signaturePrefix starts the last line of a signed statement. It is preceded
+// by the comment marker of the format ("# " for CSV, "%" for PDF), so readers
+// ignore it.
+const signaturePrefix = "signature: hmac-sha256="
+
+// ErrInvalidSignature is returned by Verify when a statement was altered,
+// truncated or signed with another key.
+var ErrInvalidSignature = errors.New("statement signature is invalid")
+
+// signingWriter forwards writes to w and keeps an HMAC of everything written.
+type signingWriter struct {
+	w   io.Writer
+	mac hash.Hash
+	n   int64
+}
+
+func newSigningWriter(w io.Writer, key []byte) *signingWriter {
+	return &signingWriter{w: w, mac: hmac.New(sha256.New, key)}
+}
+
+func (s *signingWriter) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	s.mac.Write(p[:n])
+	s.n += int64(n)
+	return n, err
+}
+
+// sign appends the signature line of everything written so far.
+func (s *signingWriter) sign(commentMarker string) error {
+	line := commentMarker + signaturePrefix + hex.EncodeToString(s.mac.Sum(nil)) + "\n"
+	_, err := io.WriteString(s.w, line)
+	return err
+}
+
+// Verify checks the signature line that ends a statement.
+func Verify(document, key []byte) error {
+	body := bytes.TrimSuffix(document, []byte("\n"))
+	i := bytes.LastIndexByte(body, '\n')
+	if i < 0 {
+		return ErrInvalidSignature
+	}
+	body, last := document[:i+1], body[i+1:]
+
+	marker, encoded, ok := bytes.Cut(last, []byte(signaturePrefix))
+	if !ok || (string(marker) != csvComment && string(marker) != pdfComment) {
+		return ErrInvalidSignature
+	}
+	signature, err := hex.DecodeString(string(encoded))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// Package statement renders wallet statements as CSV or PDF documents and
+// signs them, so a statement can later be checked for tampering with Verify.
+package statement
+
+import (
+	"errors"
+	"io"
+	"wallet/internal/domain"
+)
+
+// Statement formats.
+const (
+	FormatCSV = "csv"
+	FormatPDF = "pdf"
+)
+
+// ErrUnsupportedFormat is returned by NewRenderer for an unknown format.
+var ErrUnsupportedFormat = errors.New("unsupported statement format, use csv or pdf")
+
+// NewRenderer returns a renderer that streams a statement in format to w and
+// ends it with an HMAC-SHA256 signature computed with key.
+func NewRenderer(format string, w io.Writer, key []byte) (domain.StatementRenderer, error) {
+	out := newSigningWriter(w, key)
+	switch format {
+	case FormatCSV:
+		return newCSVRenderer(out), nil
+	case FormatPDF:
+		return newPDFRenderer(out), nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ContentType returns the MIME type of format, or ErrUnsupportedFormat.
+func ContentType(format string) (string, error) {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8", nil
+	case FormatPDF:
+		return "application/pdf", nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
@@ -0,0 +1,128 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+)
+
+var testKey = []byte("statement-signing-key")
+
+// render writes the statement of a wallet receiving n recharges of 10.
+func render(t *testing.T, format string, n int) []byte {
+	t.Helper()
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	s := &domain.Statement{
+		ID: "st-1", WalletID: "w-1", Currency: "USD",
+		From: from, To: from.AddDate(0, 1, 0), OpeningBalance: 5, GeneratedAt: from.AddDate(0, 1, 1),
+	}
+
+	var out bytes.Buffer
+	r, err := NewRenderer(format, &out, testKey)
+	if err != nil {
+		t.Fatalf("NewRenderer(%q): %v", format, err)
+	}
+	if err := r.Begin(s); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	balance := s.OpeningBalance
+	for i := range n {
+		balance += 10
+		m := &domain.Movement{
+			ID: fmt.Sprintf("m-%d", i), WalletID: s.WalletID, Type: domain.MovementRecharge,
+			Amount: 10, BalanceAfter: balance, CreatedAt: from.Add(time.Duration(i) * time.Hour),
+		}
+		if err := r.Movement(m); err != nil {
+			t.Fatalf("Movement %d: %v", i, err)
+		}
+	}
+	if err := r.End(balance); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	return out.Bytes()
+}
+
+func TestStatementsVerifyUntilAltered(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatPDF} {
+		// Enough movements for the PDF to run over several pages.
+		document := render(t, format, 150)
+
+		tests := []struct {
+			name     string
+			document []byte
+			key      []byte
+			want     error
+		}{
+			{"as signed", document, testKey, nil},
+			{"another key", document, []byte("some-other-key"), ErrInvalidSignature},
+			{"amount changed", bytes.Replace(document, []byte("1505.00"), []byte("1905.00"), 1), testKey, ErrInvalidSignature},
+			{"truncated", document[:len(document)/2], testKey, ErrInvalidSignature},
+			{"signature removed", document[:bytes.LastIndex(bytes.TrimSuffix(document, []byte("\n")), []byte("\n"))+1], testKey, ErrInvalidSignature},
+			{"empty", nil, testKey, ErrInvalidSignature},
+		}
+		for _, tt := range tests {
+			t.Run(format+"/"+tt.name, func(t *testing.T) {
+				if err := Verify(tt.document, tt.key); !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+					t.Errorf("Verify() = %v, want %v", err, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestCSVStatement(t *testing.T) {
+	document := render(t, FormatCSV, 2)
+
+	lines := strings.Split(strings.TrimSuffix(string(document), "\n"), "\n")
+	if !strings.HasPrefix(lines[0], "# statement st-1 of wallet w-1 (USD)") {
+		t.Errorf("first line = %q, want the statement details as a comment", lines[0])
+	}
+	if last := lines[len(lines)-1]; !strings.HasPrefix(last, csvComment+signaturePrefix) {
+		t.Errorf("last line = %q, want the signature as a comment", last)
+	}
+
+	r := csv.NewReader(bytes.NewReader(document))
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	var got []string
+	for _, record := range records[1:] {
+		got = append(got, record[0]+" "+record[len(record)-1])
+	}
+	want := []string{"opening 5.00", "movement 15.00", "movement 25.00", "closing 25.00"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("entries = %q, want %q", got, want)
+	}
+}
+
+func TestPDFStatementIsADocument(t *testing.T) {
+	document := render(t, FormatPDF, 150)
+
+	if !bytes.HasPrefix(document, []byte("%PDF-1.4\n")) {
+		t.Errorf("document starts with %q, want a PDF header", document[:min(len(document), 9)])
+	}
+	// Readers stop at the end-of-file marker; the signature is a comment after it.
+	eof := bytes.LastIndex(document, []byte("%%EOF\n"))
+	if eof < 0 || !bytes.HasPrefix(document[eof+len("%%EOF\n"):], []byte(pdfComment+signaturePrefix)) {
+		t.Error("the signature does not follow the end-of-file marker")
+	}
+	if pages := bytes.Count(document, []byte("/Type /Page ")); pages < 2 {
+		t.Errorf("%d pages, want the movements spread over several", pages)
+	}
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	if _, err := NewRenderer("xlsx", &bytes.Buffer{}, testKey); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("NewRenderer(xlsx) = %v, want ErrUnsupportedFormat", err)
+	}
+	if _, err := ContentType("xlsx"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("ContentType(xlsx) = %v, want ErrUnsupportedFormat", err)
+	}
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// AdjustmentUsecase puts manual adjustments under four eyes: an operator
+// requests a credit or debit, and another operator approves or rejects it.
+// Only operators can take part; users and background jobs are refused. Makers
+// and checkers are told apart by their verified identity, so operators whose
+// identity wasn't verified are refused as well.
+type AdjustmentUsecase interface {
+	// Request proposes to credit (positive amount) or debit (negative amount)
+	// a wallet. Nothing moves until another operator approves it.
+	Request(ctx context.Context, walletID string, amount float64, reason string) (*domain.AdjustmentRequest, error)
+	// List returns the requests in status, oldest first; all of them when
+	// status is empty.
+	List(ctx context.Context, status domain.AdjustmentRequestStatus) ([]domain.AdjustmentRequest, error)
+	Get(ctx context.Context, id string) (*domain.AdjustmentRequest, error)
+	// Approve applies the adjustment with WalletUsecase.Adjust, in the same
+	// transaction as the decision. If it can't be applied the request ends
+	// failed, with the reason in its note.
+	Approve(ctx context.Context, id, note string) (*domain.AdjustmentRequest, error)
+	Reject(ctx context.Context, id, note string) (*domain.AdjustmentRequest, error)
+}
+
+type adjustmentUsecase struct {
+	requestRepo   domain.AdjustmentRequestRepository
+	walletRepo    domain.WalletRepository
+	walletUsecase WalletUsecase
+	auditRepo     domain.AuditRepository
+	txnRepo       domain.TxnRepository
+}
+
+func NewAdjustmentUsecase(rr domain.AdjustmentRequestRepository, wr domain.WalletRepository, wu WalletUsecase, ar domain.AuditRepository, tr domain.TxnRepository) AdjustmentUsecase {
+	return &adjustmentUsecase{
+		requestRepo:   rr,
+		walletRepo:    wr,
+		walletUsecase: wu,
+		auditRepo:     ar,
+		txnRepo:       tr,
+	}
+}
+
+func (u *adjustmentUsecase) Request(ctx context.Context, walletID string, amount float64, reason string) (*domain.AdjustmentRequest, error) {
+	if err := verifiedOperator(ctx); err != nil {
+		return nil, err
+	}
+	if amount == 0 {
+		return nil, fmt.Errorf("%w: amount cannot be zero", domain.ErrInvalidAdjustment)
+	}
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", domain.ErrInvalidAdjustment)
+	}
+	if _, err := u.walletRepo.FindByID(ctx, walletID); err != nil {
+		return nil, err
+	}
+
+	request := &domain.AdjustmentRequest{
+		ID:          uuid.New().String(),
+		WalletID:    walletID,
+		Amount:      amount,
+		Reason:      reason,
+		Status:      domain.AdjustmentPending,
+		RequestedBy: domain.ActorFromContext(ctx),
+	}
+	err := u.txnRepo.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := u.requestRepo.Save(txCtx, request); err != nil {
+			return err
+		}
+		return u.auditRepo.Record(txCtx, newAuditEntry(txCtx, domain.AuditAdjustmentRequested, domain.AuditEntityAdjustment, request.ID, reason, map[string]interface{}{
+			"wallet_id": walletID,
+			"amount":    amount,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (u *adjustmentUsecase) List(ctx context.Context, status domain.AdjustmentRequestStatus) ([]domain.AdjustmentRequest, error) {
+	if _, ok := domain.OperatorFromContext(ctx); !ok {
+		return nil, domain.ErrAdjustmentByOperator
+	}
+	return u.requestRepo.List(ctx, status)
+}
+
+func (u *adjustmentUsecase) Get(ctx context.Context, id string) (*domain.AdjustmentRequest, error) {
+	if _, ok := domain.OperatorFromContext(ctx); !ok {
+		return nil, domain.ErrAdjustmentByOperator
+	}
+	return u.requestRepo.FindByID(ctx, id)
+}
+
+func (u *adjustmentUsecase) Approve(ctx context.Context, id, note string) (*domain.AdjustmentRequest, error) {
+	return u.resolve(ctx, id, domain.AdjustmentApproved, domain.AuditAdjustmentApproved, note)
+}
+
+func (u *adjustmentUsecase) Reject(ctx context.Context, id, note string) (*domain.AdjustmentRequest, error) {
+	return u.resolve(ctx, id, domain.AdjustmentRejected, domain.AuditAdjustmentRejected, note)
+}
+
+// resolve decides on a pending request with it locked. An approved request is
+// applied in a savepoint: if that fails for a reason other than a conflict,
+// the request ends failed instead.
+func (u *adjustmentUsecase) resolve(ctx context.Context, id string, status domain.AdjustmentRequestStatus, action, note string) (*domain.AdjustmentRequest, error) {
+	if err := verifiedOperator(ctx); err != nil {
+		return nil, err
+	}
+
+	var request *domain.AdjustmentRequest
+	err := withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+		var err error
+		if request, err = u.requestRepo.FindByIDForUpdate(txCtx, id); err != nil {
+			return err
+		}
+		if err := request.Resolve(status, domain.ActorFromContext(txCtx), note, time.Now()); err != nil {
+			return err
+		}
+
+		if status == domain.AdjustmentApproved {
+			movement, applyErr := u.walletUsecase.Adjust(withAdjustmentApproved(txCtx), request.WalletID, request.Amount, request.Reason)
+			if isRetryable(applyErr) || ctx.Err() != nil {
+				return applyErr
+			}
+			if applyErr != nil {
+				request.Status, request.Note = domain.AdjustmentFailed, applyErr.Error()
+			} else {
+				request.MovementID = &movement.ID
+			}
+		}
+		if err := u.requestRepo.Resolve(txCtx, request); err != nil {
+			return err
+		}
+
+		return u.auditRepo.Record(txCtx, newAuditEntry(txCtx, action, domain.AuditEntityAdjustment, request.ID, note, map[string]interface{}{
+			"wallet_id":    request.WalletID,
+			"amount":       request.Amount,
+			"requested_by": request.RequestedBy,
+			"status":       request.Status,
+			"movement_id":  request.MovementID,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// verifiedOperator refuses anyone but an operator whose identity was verified.
+func verifiedOperator(ctx context.Context) error {
+	if _, ok := domain.OperatorFromContext(ctx); !ok {
+		return domain.ErrAdjustmentByOperator
+	}
+	if !domain.ActorVerified(ctx) {
+		return domain.ErrOperatorNotVerified
+	}
+	return nil
+}
+
+type adjustmentApprovedKey struct{}
+
+// withAdjustmentApproved marks the adjustment made with ctx as approved by a
+// second operator, which WalletUsecase.Adjust requires.
+func withAdjustmentApproved(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adjustmentApprovedKey{}, true)
+}
+
+func adjustmentApproved(ctx context.Context) bool {
+	approved, _ := ctx.Value(adjustmentApprovedKey{}).(bool)
+	return approved
+}
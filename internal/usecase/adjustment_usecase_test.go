@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"wallet/internal/domain"
+)
+
+// Makers and checkers are told apart by their verified identity: an operator
+// who only claims a name can neither request nor decide.
+func TestAdjustmentsNeedVerifiedOperators(t *testing.T) {
+	f := newWalletFixture(0, 0)
+	adjustments := NewAdjustmentUsecase(newMemAdjustmentRepo(), f.wallets, f.usecase(nil, nil), f.audit, fakeTxnRepo{})
+
+	alice := domain.WithVerifiedActor(context.Background(), domain.OperatorActor("alice"))
+	bob := domain.WithVerifiedActor(context.Background(), domain.OperatorActor("bob"))
+	claimsBob := domain.WithActor(context.Background(), domain.OperatorActor("bob"))
+
+	if _, err := adjustments.Request(claimsBob, "w-alice", 10, "refund"); !errors.Is(err, domain.ErrOperatorNotVerified) {
+		t.Errorf("Request by an unverified operator: %v, want ErrOperatorNotVerified", err)
+	}
+	request, err := adjustments.Request(alice, "w-alice", 10, "refund")
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+
+	if _, err := adjustments.Approve(claimsBob, request.ID, ""); !errors.Is(err, domain.ErrOperatorNotVerified) {
+		t.Errorf("Approve by an unverified operator: %v, want ErrOperatorNotVerified", err)
+	}
+	// A verified identity doesn't carry over to whoever the operator acts for.
+	if _, err := adjustments.Approve(domain.WithActor(alice, domain.OperatorActor("bob")), request.ID, ""); !errors.Is(err, domain.ErrOperatorNotVerified) {
+		t.Errorf("Approve by the maker claiming another name: %v, want ErrOperatorNotVerified", err)
+	}
+	if _, err := adjustments.Approve(alice, request.ID, ""); !errors.Is(err, domain.ErrSelfReview) {
+		t.Errorf("Approve by the maker: %v, want ErrSelfReview", err)
+	}
+
+	approved, err := adjustments.Approve(bob, request.ID, "")
+	if err != nil || approved.Status != domain.AdjustmentApproved {
+		t.Fatalf("Approve by another operator = %+v, %v; want it approved", approved, err)
+	}
+	if got := f.wallets.get("w-alice").Balance; got != 10 {
+		t.Errorf("balance = %v, want 10", got)
+	}
+}
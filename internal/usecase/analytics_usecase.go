@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+	"wallet/internal/domain"
+)
+
+const (
+	// maxInsightMonths caps how many months insights cover.
+	maxInsightMonths = 24
+	// topCounterparties is how many counterparties insights list.
+	topCounterparties = 5
+)
+
+// AnalyticsUsecase categorizes movements and sums them up into rollups, from
+// which it reports spending insights.
+type AnalyticsUsecase interface {
+	// ProcessEvents counts the movements added to the ledger since the last
+	// call in the rollups, and returns how many it counted.
+	ProcessEvents(ctx context.Context) (int, error)
+	// Insights sums up the movements of the wallets a user owns over the last
+	// months, the current one included.
+	Insights(ctx context.Context, userID string, months int) (*domain.UserInsights, error)
+	// Categorize files a movement under a category of the user's choice.
+	Categorize(ctx context.Context, movementID, category string) (*domain.MovementCategory, error)
+	// SetMerchant marks a wallet as a merchant's. Its category applies to the
+	// transfers with it from then on.
+	SetMerchant(ctx context.Context, walletID, name, category string) (*domain.Merchant, error)
+}
+
+type analyticsUsecase struct {
+	analyticsRepo domain.AnalyticsRepository
+	walletRepo    domain.WalletRepository
+	movementRepo  domain.MovementRepository
+	memberRepo    domain.WalletMemberRepository
+	auditRepo     domain.AuditRepository
+	txnRepo       domain.TxnRepository
+	batchSize     int
+	logger        *slog.Logger
+}
+
+// NewAnalyticsUsecase processes movement events batchSize at a time.
+func NewAnalyticsUsecase(anr domain.AnalyticsRepository, wr domain.WalletRepository, mr domain.MovementRepository, mbr domain.WalletMemberRepository, ar domain.AuditRepository, tr domain.TxnRepository, batchSize int, logger *slog.Logger) AnalyticsUsecase {
+	return &analyticsUsecase{
+		analyticsRepo: anr,
+		walletRepo:    wr,
+		movementRepo:  mr,
+		memberRepo:    mbr,
+		auditRepo:     ar,
+		txnRepo:       tr,
+		batchSize:     batchSize,
+		logger:        logger,
+	}
+}
+
+// ProcessEvents handles the events in batches, each in a transaction that
+// claims them, updates the rollups and deletes them, so every movement is
+// counted exactly once however many instances run it.
+func (u *analyticsUsecase) ProcessEvents(ctx context.Context) (int, error) {
+	processed := 0
+	for ctx.Err() == nil {
+		var claimed int
+		err := u.txnRepo.WithTransaction(ctx, func(txCtx context.Context) error {
+			movements, err := u.analyticsRepo.ClaimEvents(txCtx, u.batchSize)
+			if err != nil {
+				return err
+			}
+			claimed = len(movements)
+			return u.count(txCtx, movements)
+		})
+		if err != nil {
+			return processed, err
+		}
+		processed += claimed
+		if claimed < u.batchSize {
+			break
+		}
+	}
+	if processed > 0 {
+		u.logger.InfoContext(ctx, "updated analytics rollups", "movements", processed)
+	}
+	return processed, ctx.Err()
+}
+
+// count categorizes movements and adds them to the rollups.
+func (u *analyticsUsecase) count(ctx context.Context, movements []domain.Movement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	var counterparties []string
+	for _, movement := range movements {
+		if movement.CounterpartyWalletID != nil {
+			counterparties = append(counterparties, *movement.CounterpartyWalletID)
+		}
+	}
+	merchants, err := u.analyticsRepo.FindMerchants(ctx, counterparties)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(movements))
+	categories := make([]domain.MovementCategory, len(movements))
+	var deltas []domain.AnalyticsRollup
+	for i := range movements {
+		movement := &movements[i]
+		var merchant *domain.Merchant
+		if movement.CounterpartyWalletID != nil {
+			if found, ok := merchants[*movement.CounterpartyWalletID]; ok {
+				merchant = &found
+			}
+		}
+		ids[i] = movement.ID
+		categories[i] = domain.Categorize(movement, merchant)
+		deltas = append(deltas, domain.RollupDeltas(movement, categories[i].Category, 1)...)
+	}
+
+	if err := u.analyticsRepo.SaveCategories(ctx, categories); err != nil {
+		return err
+	}
+	if err := u.analyticsRepo.AddToRollups(ctx, deltas); err != nil {
+		return err
+	}
+	return u.analyticsRepo.DeleteEvents(ctx, ids)
+}
+
+func (u *analyticsUsecase) Insights(ctx context.Context, userID string, months int) (*domain.UserInsights, error) {
+	if months == 0 {
+		months = 6
+	}
+	if months < 1 || months > maxInsightMonths {
+		return nil, fmt.Errorf("%w: months must be between 1 and %d", domain.ErrInvalidInsightsRange, maxInsightMonths)
+	}
+	if err := authorizeUser(ctx, userID, domain.ErrOtherUser); err != nil {
+		return nil, err
+	}
+
+	to := domain.MonthOf(time.Now()).AddDate(0, 1, 0)
+	from := to.AddDate(0, -months, 0)
+	rows, err := u.analyticsRepo.ListInsights(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	insights := &domain.UserInsights{UserID: userID, From: from, To: to, Currencies: []domain.CurrencyInsights{}}
+	for start := 0; start < len(rows); {
+		end := start
+		for end < len(rows) && rows[end].Currency == rows[start].Currency {
+			end++
+		}
+		currency, err := u.currencyInsights(ctx, rows[start:end], from, to)
+		if err != nil {
+			return nil, err
+		}
+		insights.Currencies = append(insights.Currencies, *currency)
+		start = end
+	}
+	return insights, nil
+}
+
+// currencyInsights sums up the rows of one currency, which come sorted by month.
+func (u *analyticsUsecase) currencyInsights(ctx context.Context, rows []domain.InsightRow, from, to time.Time) (*domain.CurrencyInsights, error) {
+	insights := &domain.CurrencyInsights{
+		Currency:          rows[0].Currency,
+		TopCounterparties: []domain.CounterpartyTotal{},
+		Categories:        []domain.CategoryTotal{},
+	}
+	byMonth := make(map[time.Time]*domain.MonthlyTotal)
+	for month := from; month.Before(to); month = month.AddDate(0, 1, 0) {
+		insights.Months = append(insights.Months, domain.MonthlyTotal{Month: month})
+	}
+	for i := range insights.Months {
+		byMonth[insights.Months[i].Month] = &insights.Months[i]
+	}
+
+	counterparties := make(map[string]*domain.CounterpartyTotal)
+	categories := make(map[string]*domain.CategoryTotal)
+	for _, row := range rows {
+		switch row.Dimension {
+		case domain.RollupTotal:
+			if total, ok := byMonth[domain.MonthOf(row.Month)]; ok {
+				total.In, total.Out, total.Count = cents(row.Inflow), cents(row.Outflow), row.Count
+				total.Net = cents(row.Inflow - row.Outflow)
+			}
+		case domain.RollupCounterparty:
+			total, ok := counterparties[row.Key]
+			if !ok {
+				total = &domain.CounterpartyTotal{WalletID: row.Key}
+				counterparties[row.Key] = total
+			}
+			total.In, total.Out, total.Count = cents(total.In+row.Inflow), cents(total.Out+row.Outflow), total.Count+row.Count
+		case domain.RollupCategory:
+			total, ok := categories[row.Key]
+			if !ok {
+				total = &domain.CategoryTotal{Category: row.Key}
+				categories[row.Key] = total
+			}
+			total.In, total.Out, total.Count = cents(total.In+row.Inflow), cents(total.Out+row.Outflow), total.Count+row.Count
+		}
+	}
+
+	for _, total := range categories {
+		insights.Categories = append(insights.Categories, *total)
+	}
+	slices.SortFunc(insights.Categories, func(a, b domain.CategoryTotal) int {
+		return cmp.Or(cmp.Compare(b.Out, a.Out), cmp.Compare(b.In, a.In), cmp.Compare(a.Category, b.Category))
+	})
+
+	for _, total := range counterparties {
+		insights.TopCounterparties = append(insights.TopCounterparties, *total)
+	}
+	slices.SortFunc(insights.TopCounterparties, func(a, b domain.CounterpartyTotal) int {
+		return cmp.Or(cmp.Compare(b.In+b.Out, a.In+a.Out), cmp.Compare(a.WalletID, b.WalletID))
+	})
+	if len(insights.TopCounterparties) > topCounterparties {
+		insights.TopCounterparties = insights.TopCounterparties[:topCounterparties]
+	}
+	ids := make([]string, len(insights.TopCounterparties))
+	for i, total := range insights.TopCounterparties {
+		ids[i] = total.WalletID
+	}
+	names, err := u.analyticsRepo.CounterpartyNames(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range insights.TopCounterparties {
+		insights.TopCounterparties[i].Name = names[insights.TopCounterparties[i].WalletID]
+	}
+	return insights, nil
+}
+
+// Categorize moves the movement from its category rollup to the new one in
+// the same transaction, with the movement's category locked.
+func (u *analyticsUsecase) Categorize(ctx context.Context, movementID, category string) (*domain.MovementCategory, error) {
+	category, err := domain.NormalizeCategory(category)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *domain.MovementCategory
+	err = u.txnRepo.WithTransaction(ctx, func(txCtx context.Context) error {
+		movement, err := u.movementRepo.FindByID(txCtx, movementID)
+		if err != nil {
+			return err
+		}
+		if _, err := authorizeMember(txCtx, u.memberRepo, movement.WalletID, isMember, domain.ErrNotWalletMember); err != nil {
+			return err
+		}
+		if current, err = u.analyticsRepo.FindCategoryForUpdate(txCtx, movementID); err != nil {
+			return err
+		}
+		if current.Category == category {
+			return nil
+		}
+
+		deltas := append(categoryDeltas(movement, current.Category, -1), categoryDeltas(movement, category, 1)...)
+		current.Category, current.Source = category, domain.CategoryByUser
+		if err := u.analyticsRepo.UpdateCategory(txCtx, current); err != nil {
+			return err
+		}
+		return u.analyticsRepo.AddToRollups(txCtx, deltas)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+func (u *analyticsUsecase) SetMerchant(ctx context.Context, walletID, name, category string) (*domain.Merchant, error) {
+	if name == "" {
+		return nil, errors.New("merchant name is required")
+	}
+	category, err := domain.NormalizeCategory(category)
+	if err != nil {
+		return nil, err
+	}
+
+	merchant := &domain.Merchant{WalletID: walletID, Name: name, Category: category}
+	err = u.txnRepo.WithTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := u.walletRepo.FindByID(txCtx, walletID); err != nil {
+			return err
+		}
+		if err := u.analyticsRepo.SaveMerchant(txCtx, merchant); err != nil {
+			return err
+		}
+		return u.auditRepo.Record(txCtx, newAuditEntry(txCtx, domain.AuditMerchantSet, domain.AuditEntityWallet, walletID, "", map[string]interface{}{
+			"name":     name,
+			"category": category,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merchant, nil
+}
+
+// categoryDeltas returns the change counting movement under category makes to
+// its category rollup.
+func categoryDeltas(movement *domain.Movement, category string, sign int) []domain.AnalyticsRollup {
+	var deltas []domain.AnalyticsRollup
+	for _, delta := range domain.RollupDeltas(movement, category, sign) {
+		if delta.Dimension == domain.RollupCategory {
+			deltas = append(deltas, delta)
+		}
+	}
+	return deltas
+}
+
+// cents rounds an amount summed in floating point back to cents.
+func cents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+)
+
+type rollupKey struct {
+	walletID  string
+	month     time.Time
+	dimension domain.RollupDimension
+	key       string
+}
+
+// memAnalyticsRepo keeps the rollups in memory, with the owner and currency
+// of each wallet to sum them up by user.
+type memAnalyticsRepo struct {
+	events     []domain.Movement
+	rollups    map[rollupKey]domain.AnalyticsRollup
+	categories map[string]domain.MovementCategory
+	merchants  map[string]domain.Merchant
+	names      map[string]string
+	owners     map[string][2]string // Wallet ID to owner and currency
+}
+
+func newMemAnalyticsRepo() *memAnalyticsRepo {
+	return &memAnalyticsRepo{
+		rollups:    make(map[rollupKey]domain.AnalyticsRollup),
+		categories: make(map[string]domain.MovementCategory),
+		merchants:  make(map[string]domain.Merchant),
+		names:      make(map[string]string),
+		owners:     make(map[string][2]string),
+	}
+}
+
+func (r *memAnalyticsRepo) ClaimEvents(ctx context.Context, limit int) ([]domain.Movement, error) {
+	return slices.Clone(r.events[:min(limit, len(r.events))]), nil
+}
+
+func (r *memAnalyticsRepo) DeleteEvents(ctx context.Context, movementIDs []string) error {
+	r.events = slices.DeleteFunc(r.events, func(m domain.Movement) bool { return slices.Contains(movementIDs, m.ID) })
+	return nil
+}
+
+func (r *memAnalyticsRepo) AddToRollups(ctx context.Context, deltas []domain.AnalyticsRollup) error {
+	for _, delta := range deltas {
+		key := rollupKey{delta.WalletID, delta.Month, delta.Dimension, delta.Key}
+		rollup := r.rollups[key]
+		rollup.Inflow += delta.Inflow
+		rollup.Outflow += delta.Outflow
+		rollup.Count += delta.Count
+		r.rollups[key] = rollup
+	}
+	return nil
+}
+
+func (r *memAnalyticsRepo) SaveCategories(ctx context.Context, categories []domain.MovementCategory) error {
+	for _, category := range categories {
+		r.categories[category.MovementID] = category
+	}
+	return nil
+}
+
+func (r *memAnalyticsRepo) FindCategoryForUpdate(ctx context.Context, movementID string) (*domain.MovementCategory, error) {
+	category, ok := r.categories[movementID]
+	if !ok {
+		return nil, domain.ErrMovementNotCategorized
+	}
+	return &category, nil
+}
+
+func (r *memAnalyticsRepo) UpdateCategory(ctx context.Context, category *domain.MovementCategory) error {
+	r.categories[category.MovementID] = *category
+	return nil
+}
+
+func (r *memAnalyticsRepo) SaveMerchant(ctx context.Context, merchant *domain.Merchant) error {
+	r.merchants[merchant.WalletID] = *merchant
+	return nil
+}
+
+func (r *memAnalyticsRepo) FindMerchants(ctx context.Context, walletIDs []string) (map[string]domain.Merchant, error) {
+	merchants := make(map[string]domain.Merchant)
+	for _, id := range walletIDs {
+		if merchant, ok := r.merchants[id]; ok {
+			merchants[id] = merchant
+		}
+	}
+	return merchants, nil
+}
+
+func (r *memAnalyticsRepo) CounterpartyNames(ctx context.Context, walletIDs []string) (map[string]string, error) {
+	names := make(map[string]string)
+	for _, id := range walletIDs {
+		names[id] = r.names[id]
+	}
+	return names, nil
+}
+
+func (r *memAnalyticsRepo) ListInsights(ctx context.Context, userID string, from, to time.Time) ([]domain.InsightRow, error) {
+	sums := make(map[domain.InsightRow]*domain.InsightRow)
+	var rows []*domain.InsightRow
+	for key, rollup := range r.rollups {
+		owner := r.owners[key.walletID]
+		if owner[0] != userID || key.month.Before(from) || !key.month.Before(to) {
+			continue
+		}
+		group := domain.InsightRow{Currency: owner[1], Month: key.month, Dimension: key.dimension, Key: key.key}
+		row, ok := sums[group]
+		if !ok {
+			row = &group
+			sums[group] = row
+			rows = append(rows, row)
+		}
+		row.Inflow += rollup.Inflow
+		row.Outflow += rollup.Outflow
+		row.Count += rollup.Count
+	}
+
+	sorted := make([]domain.InsightRow, len(rows))
+	for i, row := range rows {
+		sorted[i] = *row
+	}
+	slices.SortFunc(sorted, func(a, b domain.InsightRow) int {
+		return cmp.Or(cmp.Compare(a.Currency, b.Currency), a.Month.Compare(b.Month), cmp.Compare(a.Dimension, b.Dimension), cmp.Compare(a.Key, b.Key))
+	})
+	return sorted, nil
+}
+
+// memMovements finds the movements categorized in the tests.
+type memMovements struct {
+	domain.MovementRepository
+	movements []domain.Movement
+}
+
+func (r memMovements) FindByID(ctx context.Context, id string) (*domain.Movement, error) {
+	for _, m := range r.movements {
+		if m.ID == id {
+			return &m, nil
+		}
+	}
+	return nil, domain.ErrMovementNotFound
+}
+
+// newAnalyticsFixture returns Alice's spending this month and last, from her
+// USD and EUR wallets, with the events of the movements still to count.
+func newAnalyticsFixture() (AnalyticsUsecase, *memAnalyticsRepo) {
+	thisMonth := domain.MonthOf(time.Now()).Add(time.Hour)
+	lastMonth := thisMonth.AddDate(0, -1, 0)
+	shop, bob := "w-shop", "w-bob"
+
+	repo := newMemAnalyticsRepo()
+	repo.owners["w-alice"] = [2]string{"alice", "USD"}
+	repo.owners["w-alice-eur"] = [2]string{"alice", "EUR"}
+	repo.owners[bob] = [2]string{"bob", "USD"}
+	repo.merchants[shop] = domain.Merchant{WalletID: shop, Name: "Corner Shop", Category: "groceries"}
+	repo.names = map[string]string{shop: "Corner Shop", bob: "B** M*****"}
+	repo.events = []domain.Movement{
+		{ID: "m-top-up", WalletID: "w-alice", Type: domain.MovementRecharge, Amount: 100, CreatedAt: thisMonth},
+		{ID: "m-shop", WalletID: "w-alice", Type: domain.MovementTransferOut, Amount: -30, CounterpartyWalletID: &shop, CreatedAt: thisMonth},
+		{ID: "m-to-bob", WalletID: "w-alice", Type: domain.MovementTransferOut, Amount: -20, CounterpartyWalletID: &bob, CreatedAt: thisMonth},
+		{ID: "m-from-bob", WalletID: "w-alice", Type: domain.MovementTransferIn, Amount: 5, CounterpartyWalletID: &bob, CreatedAt: thisMonth},
+		{ID: "m-eur", WalletID: "w-alice-eur", Type: domain.MovementRecharge, Amount: 50, CreatedAt: thisMonth},
+		{ID: "m-shop-before", WalletID: "w-alice", Type: domain.MovementTransferOut, Amount: -10, CounterpartyWalletID: &shop, CreatedAt: lastMonth},
+	}
+
+	movements := memMovements{movements: slices.Clone(repo.events)}
+	members := (&memMemberRepo{}).owner("w-alice", "alice").owner("w-alice-eur", "alice")
+	return NewAnalyticsUsecase(repo, nil, movements, members, &memAuditRepo{}, fakeTxnRepo{}, 2, discardLogger), repo
+}
+
+// Every event is counted once, in batches, under the merchant's category for
+// transfers with a merchant, and insights sum the rollups up by currency.
+func TestAnalyticsInsights(t *testing.T) {
+	analytics, repo := newAnalyticsFixture()
+	alice := domain.WithActor(context.Background(), domain.UserActor("alice"))
+
+	if n, err := analytics.ProcessEvents(context.Background()); n != 6 || err != nil {
+		t.Fatalf("ProcessEvents = %d, %v; want 6 movements", n, err)
+	}
+	if len(repo.events) != 0 {
+		t.Fatalf("%d events left, want none", len(repo.events))
+	}
+	if c := repo.categories["m-shop"]; c.Category != "groceries" || c.Source != domain.CategoryByMerchant {
+		t.Errorf("shop transfer filed under %s by %s, want groceries by merchant", c.Category, c.Source)
+	}
+
+	insights, err := analytics.Insights(alice, "alice", 2)
+	if err != nil {
+		t.Fatalf("Insights: %v", err)
+	}
+	if len(insights.Currencies) != 2 || insights.Currencies[0].Currency != "EUR" || insights.Currencies[1].Currency != "USD" {
+		t.Fatalf("insights = %+v, want EUR then USD", insights.Currencies)
+	}
+	usd := insights.Currencies[1]
+
+	wantMonths := []domain.MonthlyTotal{
+		{Month: insights.From, Out: 10, Net: -10, Count: 1},
+		{Month: insights.From.AddDate(0, 1, 0), In: 105, Out: 50, Net: 55, Count: 4},
+	}
+	if !slices.EqualFunc(usd.Months, wantMonths, func(a, b domain.MonthlyTotal) bool {
+		return a.Month.Equal(b.Month) && a.In == b.In && a.Out == b.Out && a.Net == b.Net && a.Count == b.Count
+	}) {
+		t.Errorf("months = %+v, want %+v", usd.Months, wantMonths)
+	}
+	wantCategories := []domain.CategoryTotal{
+		{Category: "groceries", Out: 40, Count: 2},
+		{Category: "transfer", In: 5, Out: 20, Count: 2},
+		{Category: "top_up", In: 100, Count: 1},
+	}
+	if !slices.Equal(usd.Categories, wantCategories) {
+		t.Errorf("categories = %+v, want %+v", usd.Categories, wantCategories)
+	}
+	wantCounterparties := []domain.CounterpartyTotal{
+		{WalletID: "w-shop", Name: "Corner Shop", Out: 40, Count: 2},
+		{WalletID: "w-bob", Name: "B** M*****", In: 5, Out: 20, Count: 2},
+	}
+	if !slices.Equal(usd.TopCounterparties, wantCounterparties) {
+		t.Errorf("top counterparties = %+v, want %+v", usd.TopCounterparties, wantCounterparties)
+	}
+
+	if _, err := analytics.Insights(domain.WithActor(context.Background(), domain.UserActor("bob")), "alice", 2); !errors.Is(err, domain.ErrOtherUser) {
+		t.Errorf("Insights by Bob = %v, want ErrOtherUser", err)
+	}
+	if _, err := analytics.Insights(alice, "alice", maxInsightMonths+1); !errors.Is(err, domain.ErrInvalidInsightsRange) {
+		t.Errorf("Insights over %d months = %v, want ErrInvalidInsightsRange", maxInsightMonths+1, err)
+	}
+}
+
+// Recategorizing a movement moves it between category rollups, and leaves
+// the other rollups alone.
+func TestAnalyticsCategorize(t *testing.T) {
+	analytics, repo := newAnalyticsFixture()
+	if _, err := analytics.ProcessEvents(context.Background()); err != nil {
+		t.Fatalf("ProcessEvents: %v", err)
+	}
+	month := domain.MonthOf(time.Now())
+	categoryOf := func(key string) domain.AnalyticsRollup {
+		return repo.rollups[rollupKey{"w-alice", month, domain.RollupCategory, key}]
+	}
+
+	bob := domain.WithActor(context.Background(), domain.UserActor("bob"))
+	if _, err := analytics.Categorize(bob, "m-to-bob", "rent"); !errors.Is(err, domain.ErrNotWalletMember) {
+		t.Errorf("Categorize by Bob = %v, want ErrNotWalletMember", err)
+	}
+
+	alice := domain.WithActor(context.Background(), domain.UserActor("alice"))
+	category, err := analytics.Categorize(alice, "m-to-bob", "Rent")
+	if err != nil {
+		t.Fatalf("Categorize: %v", err)
+	}
+	if category.Category != "rent" || category.Source != domain.CategoryByUser {
+		t.Errorf("category = %s by %s, want rent by user", category.Category, category.Source)
+	}
+	if got := categoryOf("transfer"); got.Outflow != 0 || got.Inflow != 5 || got.Count != 1 {
+		t.Errorf("transfer rollup = %+v, want only the 5 from Bob", got)
+	}
+	if got := categoryOf("rent"); got.Outflow != 20 || got.Count != 1 {
+		t.Errorf("rent rollup = %+v, want the 20 to Bob", got)
+	}
+	if got := repo.rollups[rollupKey{"w-alice", month, domain.RollupTotal, ""}]; got.Outflow != 50 || got.Count != 4 {
+		t.Errorf("total rollup = %+v, want it unchanged", got)
+	}
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// newAuditEntry builds an audit entry attributed to the actor of ctx.
+func newAuditEntry(ctx context.Context, action, entityType, entityID, reason string, details map[string]interface{}) *domain.AuditEntry {
+	entry := &domain.AuditEntry{
+		ID:         uuid.New().String(),
+		Actor:      domain.ActorFromContext(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Reason:     reason,
+	}
+	// details only ever holds plain values, so encoding can't fail.
+	if encoded, err := json.Marshal(details); err == nil && details != nil {
+		entry.Details = string(encoded)
+	}
+	return entry
+}
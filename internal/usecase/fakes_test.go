@@ -0,0 +1,676 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+	"wallet/internal/domain"
+)
+
+// The fakes below keep their data in memory. They are just enough of the
+// repositories for the use cases under test; none of them is transactional.
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// fakeTxnRepo runs fn in a transaction context, and commits unless it fails.
+// Nested calls join the outer transaction like the Postgres one.
+type fakeTxnRepo struct{}
+
+func (fakeTxnRepo) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	if domain.InTransaction(ctx) {
+		return fn(ctx)
+	}
+	txCtx, commit := domain.NewTransactionContext(ctx)
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+	commit(ctx)
+	return nil
+}
+
+// walletRead is a wallet as a FindByID of memWalletRepo returned it.
+type walletRead struct {
+	walletID      string
+	balance       float64
+	inTransaction bool
+}
+
+type memWalletRepo struct {
+	mu      sync.Mutex
+	wallets map[string]domain.Wallet
+	reads   []walletRead
+}
+
+func newMemWalletRepo(wallets ...domain.Wallet) *memWalletRepo {
+	r := &memWalletRepo{wallets: make(map[string]domain.Wallet)}
+	for _, w := range wallets {
+		r.wallets[w.ID] = w
+	}
+	return r
+}
+
+func (r *memWalletRepo) get(id string) domain.Wallet {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.wallets[id]
+}
+
+func (r *memWalletRepo) Save(ctx context.Context, wallet *domain.Wallet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.wallets[wallet.ID] = *wallet
+	return nil
+}
+
+func (r *memWalletRepo) FindByID(ctx context.Context, id string) (*domain.Wallet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wallet, ok := r.wallets[id]
+	if !ok {
+		return nil, domain.ErrWalletNotFound
+	}
+	r.reads = append(r.reads, walletRead{walletID: id, balance: wallet.Balance, inTransaction: domain.InTransaction(ctx)})
+	return &wallet, nil
+}
+
+func (r *memWalletRepo) FindByUserID(ctx context.Context, userID string) (*domain.Wallet, error) {
+	return r.FindByUserIDAndCurrency(ctx, userID, domain.DefaultCurrency)
+}
+
+func (r *memWalletRepo) FindByUserIDAndCurrency(ctx context.Context, userID, currency string) (*domain.Wallet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, wallet := range r.wallets {
+		if wallet.UserID == userID && wallet.Currency == currency {
+			return &wallet, nil
+		}
+	}
+	return nil, domain.ErrWalletNotFound
+}
+
+func (r *memWalletRepo) List(ctx context.Context, afterID string, limit int) ([]domain.Wallet, error) {
+	return nil, nil
+}
+
+func (r *memWalletRepo) Update(ctx context.Context, wallet *domain.Wallet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.wallets[wallet.ID]
+	if !ok {
+		return domain.ErrWalletNotFound
+	}
+	if stored.Version != wallet.Version {
+		return domain.ErrConcurrentModification
+	}
+	wallet.Version++
+	r.wallets[wallet.ID] = *wallet
+	return nil
+}
+
+type memUserRepo struct {
+	users map[string]domain.User
+}
+
+func newMemUserRepo(users ...domain.User) *memUserRepo {
+	r := &memUserRepo{users: make(map[string]domain.User)}
+	for _, u := range users {
+		r.users[u.ID] = u
+	}
+	return r
+}
+
+func (r *memUserRepo) Save(ctx context.Context, user *domain.User) error {
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *memUserRepo) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (r *memUserRepo) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *memUserRepo) List(ctx context.Context, afterID string, limit int) ([]domain.User, error) {
+	return nil, nil
+}
+
+func (r *memUserRepo) UpdateTier(ctx context.Context, id, tier string) error {
+	user, ok := r.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	user.Tier = tier
+	r.users[id] = user
+	return nil
+}
+
+type memMovementRepo struct {
+	mu        sync.Mutex
+	movements []domain.Movement
+}
+
+func (r *memMovementRepo) Save(ctx context.Context, movement *domain.Movement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	movement.CreatedAt = time.Now()
+	r.movements = append(r.movements, *movement)
+	return nil
+}
+
+func (r *memMovementRepo) FindByID(ctx context.Context, id string) (*domain.Movement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range r.movements {
+		if m.ID == id {
+			return &m, nil
+		}
+	}
+	return nil, domain.ErrMovementNotFound
+}
+
+func (r *memMovementRepo) ListByWallet(ctx context.Context, walletID string, from, to time.Time) ([]domain.Movement, error) {
+	return nil, nil
+}
+
+func (r *memMovementRepo) ListByWalletAfter(ctx context.Context, walletID string, from, to time.Time, after *domain.Movement, limit int) ([]domain.Movement, error) {
+	return nil, nil
+}
+
+func (r *memMovementRepo) BalanceAt(ctx context.Context, walletID string, at time.Time) (float64, error) {
+	return 0, nil
+}
+
+func (r *memMovementRepo) SpentBy(ctx context.Context, walletID, actor string, since time.Time) (float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	spent := 0.0
+	for _, m := range r.movements {
+		if m.WalletID == walletID && m.Actor == actor && m.Amount < 0 && !m.CreatedAt.Before(since) {
+			spent -= m.Amount
+		}
+	}
+	return spent, nil
+}
+
+// memFeeCreditRepo queues fee movements saved in movements.
+type memFeeCreditRepo struct {
+	movements *memMovementRepo
+	queued    []string
+}
+
+func (r *memFeeCreditRepo) Save(ctx context.Context, credit *domain.FeeCredit) error {
+	r.queued = append(r.queued, credit.MovementID)
+	return nil
+}
+
+func (r *memFeeCreditRepo) Claim(ctx context.Context, limit int) ([]domain.Movement, error) {
+	var fees []domain.Movement
+	for _, id := range r.queued[:min(limit, len(r.queued))] {
+		fee, err := r.movements.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		fees = append(fees, *fee)
+	}
+	return fees, nil
+}
+
+func (r *memFeeCreditRepo) Delete(ctx context.Context, movementIDs []string) error {
+	r.queued = slices.DeleteFunc(r.queued, func(id string) bool { return slices.Contains(movementIDs, id) })
+	return nil
+}
+
+type memMemberRepo struct {
+	members []domain.WalletMember
+}
+
+func (r *memMemberRepo) Save(ctx context.Context, member *domain.WalletMember) error {
+	if _, err := r.Find(ctx, member.WalletID, member.UserID); err == nil {
+		return domain.ErrAlreadyMember
+	}
+	r.members = append(r.members, *member)
+	return nil
+}
+
+func (r *memMemberRepo) Find(ctx context.Context, walletID, userID string) (*domain.WalletMember, error) {
+	for _, m := range r.members {
+		if m.WalletID == walletID && m.UserID == userID {
+			return &m, nil
+		}
+	}
+	return nil, domain.ErrMemberNotFound
+}
+
+func (r *memMemberRepo) ListByWallet(ctx context.Context, walletID string) ([]domain.WalletMember, error) {
+	var members []domain.WalletMember
+	for _, m := range r.members {
+		if m.WalletID == walletID {
+			members = append(members, m)
+		}
+	}
+	return members, nil
+}
+
+func (r *memMemberRepo) Activate(ctx context.Context, walletID, userID string) error {
+	for i, m := range r.members {
+		if m.WalletID == walletID && m.UserID == userID {
+			r.members[i].Status = domain.MemberActive
+			return nil
+		}
+	}
+	return domain.ErrMemberNotFound
+}
+
+func (r *memMemberRepo) Delete(ctx context.Context, walletID, userID string) error {
+	for i, m := range r.members {
+		if m.WalletID == walletID && m.UserID == userID {
+			r.members = append(r.members[:i], r.members[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrMemberNotFound
+}
+
+// owner makes userID the owner of walletID.
+func (r *memMemberRepo) owner(walletID, userID string) *memMemberRepo {
+	r.members = append(r.members, domain.WalletMember{
+		ID: walletID + ":" + userID, WalletID: walletID, UserID: userID,
+		Role: domain.RoleOwner, Status: domain.MemberActive,
+	})
+	return r
+}
+
+type memReviewRepo struct {
+	reviews map[string]domain.TransferReview
+}
+
+func newMemReviewRepo() *memReviewRepo {
+	return &memReviewRepo{reviews: make(map[string]domain.TransferReview)}
+}
+
+func (r *memReviewRepo) Save(ctx context.Context, review *domain.TransferReview) error {
+	r.reviews[review.ID] = *review
+	return nil
+}
+
+func (r *memReviewRepo) FindByID(ctx context.Context, id string) (*domain.TransferReview, error) {
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil, domain.ErrReviewNotFound
+	}
+	return &review, nil
+}
+
+func (r *memReviewRepo) FindByIDForUpdate(ctx context.Context, id string) (*domain.TransferReview, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *memReviewRepo) List(ctx context.Context, status domain.TransferReviewStatus) ([]domain.TransferReview, error) {
+	var reviews []domain.TransferReview
+	for _, review := range r.reviews {
+		if status == "" || review.Status == status {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+func (r *memReviewRepo) Resolve(ctx context.Context, review *domain.TransferReview) error {
+	if r.reviews[review.ID].Status != domain.ReviewPending {
+		return domain.ErrReviewNotPending
+	}
+	r.reviews[review.ID] = *review
+	return nil
+}
+
+func (r *memReviewRepo) TransferHistory(ctx context.Context, walletID, toWalletID string, recentSince, historySince time.Time) (*domain.TransferHistory, error) {
+	return &domain.TransferHistory{}, nil
+}
+
+// reviewEveryTransfer holds every transfer to a new recipient for review.
+type reviewEveryTransfer struct{}
+
+func (reviewEveryTransfer) RiskRules() *domain.RiskRules {
+	return &domain.RiskRules{ReviewScore: 50, BlockScore: 100, NewRecipient: &domain.NewRecipientRule{Score: 50}}
+}
+
+type memAuditRepo struct {
+	entries []domain.AuditEntry
+}
+
+func (r *memAuditRepo) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+// actions returns the audited actions, in order.
+func (r *memAuditRepo) actions() []string {
+	actions := make([]string, len(r.entries))
+	for i, e := range r.entries {
+		actions[i] = e.Action
+	}
+	return actions
+}
+
+type memScreeningRepo struct {
+	hits []domain.ScreeningHit
+}
+
+func (r *memScreeningRepo) SaveHits(ctx context.Context, hits []domain.ScreeningHit) error {
+	r.hits = append(r.hits, hits...)
+	return nil
+}
+
+func (r *memScreeningRepo) ListByUsers(ctx context.Context, userIDs []string) ([]domain.ScreeningHit, error) {
+	var hits []domain.ScreeningHit
+	for _, hit := range r.hits {
+		for _, id := range userIDs {
+			if hit.UserID == id {
+				hits = append(hits, hit)
+			}
+		}
+	}
+	return hits, nil
+}
+
+func (r *memScreeningRepo) FindByID(ctx context.Context, id string) (*domain.ScreeningHit, error) {
+	for _, hit := range r.hits {
+		if hit.ID == id {
+			return &hit, nil
+		}
+	}
+	return nil, domain.ErrHitNotFound
+}
+
+func (r *memScreeningRepo) FindByIDForUpdate(ctx context.Context, id string) (*domain.ScreeningHit, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *memScreeningRepo) List(ctx context.Context, status domain.ScreeningHitStatus) ([]domain.ScreeningHit, error) {
+	return r.hits, nil
+}
+
+func (r *memScreeningRepo) Resolve(ctx context.Context, hit *domain.ScreeningHit) error {
+	for i := range r.hits {
+		if r.hits[i].ID == hit.ID {
+			r.hits[i] = *hit
+		}
+	}
+	return nil
+}
+
+// walletFixture is a wallet usecase over in-memory repositories, with two
+// users owning a wallet each.
+type walletFixture struct {
+	wallets    *memWalletRepo
+	users      *memUserRepo
+	movements  *memMovementRepo
+	members    *memMemberRepo
+	reviews    *memReviewRepo
+	feeCredits *memFeeCreditRepo
+	audit      *memAuditRepo
+	screening  *memScreeningRepo
+	// fees is the fee schedule of the usecase; none by default.
+	fees *domain.FeeSchedule
+}
+
+func newWalletFixture(aliceBalance, bobBalance float64) *walletFixture {
+	movements := &memMovementRepo{}
+	return &walletFixture{
+		wallets: newMemWalletRepo(
+			domain.Wallet{ID: "w-alice", UserID: "alice", Currency: "USD", Balance: aliceBalance, Status: domain.WalletActive},
+			domain.Wallet{ID: "w-bob", UserID: "bob", Currency: "USD", Balance: bobBalance, Status: domain.WalletActive},
+		),
+		users: newMemUserRepo(
+			domain.User{ID: "alice", Username: "alice", Name: "Alice Liddell"},
+			domain.User{ID: "bob", Username: "bob", Name: "Bob Marley"},
+		),
+		movements:  movements,
+		members:    (&memMemberRepo{}).owner("w-alice", "alice").owner("w-bob", "bob"),
+		reviews:    newMemReviewRepo(),
+		feeCredits: &memFeeCreditRepo{movements: movements},
+		audit:      &memAuditRepo{},
+		screening:  &memScreeningRepo{},
+	}
+}
+
+// usecase builds the wallet usecase over walletRepo, which defaults to the
+// fixture's wallets, with risk scoring transfers when not nil.
+func (f *walletFixture) usecase(walletRepo domain.WalletRepository, risk domain.RiskRulesSource) WalletUsecase {
+	if walletRepo == nil {
+		walletRepo = f.wallets
+	}
+	screening := NewScreeningUsecase(f.screening, f.audit, fakeTxnRepo{}, nil, 0.85, 0.95, discardLogger)
+	return NewWalletUsecase(walletRepo, f.users, f.movements, f.members, f.reviews, f.feeCredits, f.audit, fakeTxnRepo{}, f.fees, risk, screening, discardLogger)
+}
+
+// nopNotifier drops every notification.
+type nopNotifier struct{}
+
+func (nopNotifier) Notify(ctx context.Context, notification domain.Notification) error { return nil }
+
+// recordingNotifier keeps every notification sent.
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []domain.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+// last returns the last notification sent.
+func (n *recordingNotifier) last() domain.Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.sent[len(n.sent)-1]
+}
+
+// memPaymentRequestRepo holds payment requests in memory.
+type memPaymentRequestRepo struct {
+	mu       sync.Mutex
+	requests map[string]domain.PaymentRequest
+}
+
+func newMemPaymentRequestRepo() *memPaymentRequestRepo {
+	return &memPaymentRequestRepo{requests: make(map[string]domain.PaymentRequest)}
+}
+
+func (r *memPaymentRequestRepo) Save(ctx context.Context, request *domain.PaymentRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests[request.ID] = *request
+	return nil
+}
+
+func (r *memPaymentRequestRepo) FindByID(ctx context.Context, id string) (*domain.PaymentRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, domain.ErrPaymentRequestNotFound
+	}
+	return &request, nil
+}
+
+func (r *memPaymentRequestRepo) FindByIDForUpdate(ctx context.Context, id string) (*domain.PaymentRequest, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *memPaymentRequestRepo) FindByReviewIDForUpdate(ctx context.Context, reviewID string) (*domain.PaymentRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, request := range r.requests {
+		if request.ReviewID != nil && *request.ReviewID == reviewID {
+			return &request, nil
+		}
+	}
+	return nil, domain.ErrPaymentRequestNotFound
+}
+
+func (r *memPaymentRequestRepo) ListOpen(ctx context.Context, walletID string, now time.Time) ([]domain.PaymentRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var open []domain.PaymentRequest
+	for _, request := range r.requests {
+		if request.Status == domain.PaymentRequestPending && (request.RequesterWalletID == walletID || request.PayerWalletID == walletID) {
+			open = append(open, request)
+		}
+	}
+	return open, nil
+}
+
+func (r *memPaymentRequestRepo) Resolve(ctx context.Context, request *domain.PaymentRequest, from domain.PaymentRequestStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.requests[request.ID].Status != from {
+		return domain.ErrPaymentRequestNotPending
+	}
+	r.requests[request.ID] = *request
+	return nil
+}
+
+func (r *memPaymentRequestRepo) ExpireDue(ctx context.Context, now time.Time) ([]domain.PaymentRequest, error) {
+	return nil, nil
+}
+
+// memAliasRepo holds aliases in memory.
+type memAliasRepo struct {
+	mu      sync.Mutex
+	aliases []domain.UserAlias
+}
+
+func (r *memAliasRepo) Save(ctx context.Context, alias *domain.UserAlias) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.aliases {
+		if a.UserID == alias.UserID && a.Kind == alias.Kind && a.Value == alias.Value {
+			return domain.ErrAliasTaken
+		}
+	}
+	r.aliases = append(r.aliases, *alias)
+	return nil
+}
+
+func (r *memAliasRepo) Update(ctx context.Context, alias *domain.UserAlias) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.aliases {
+		if alias.IsVerified() && a.ID != alias.ID && a.IsVerified() && a.Kind == alias.Kind && a.Value == alias.Value {
+			return domain.ErrAliasTaken
+		}
+	}
+	for i := range r.aliases {
+		if r.aliases[i].ID == alias.ID {
+			r.aliases[i] = *alias
+		}
+	}
+	return nil
+}
+
+func (r *memAliasRepo) ListByUser(ctx context.Context, userID string) ([]domain.UserAlias, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var aliases []domain.UserAlias
+	for _, a := range r.aliases {
+		if a.UserID == userID {
+			aliases = append(aliases, a)
+		}
+	}
+	return aliases, nil
+}
+
+func (r *memAliasRepo) FindByIDForUpdate(ctx context.Context, userID, id string) (*domain.UserAlias, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.aliases {
+		if a.ID == id && a.UserID == userID {
+			return &a, nil
+		}
+	}
+	return nil, domain.ErrAliasNotFound
+}
+
+func (r *memAliasRepo) FindByValue(ctx context.Context, kind domain.AliasKind, value string) (*domain.UserAlias, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.aliases {
+		if a.Kind == kind && a.Value == value && a.IsVerified() {
+			return &a, nil
+		}
+	}
+	return nil, domain.ErrAliasNotFound
+}
+
+func (r *memAliasRepo) Delete(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, a := range r.aliases {
+		if a.ID == id && a.UserID == userID {
+			r.aliases = slices.Delete(r.aliases, i, i+1)
+			return nil
+		}
+	}
+	return domain.ErrAliasNotFound
+}
+
+// memAdjustmentRepo holds adjustment requests in memory.
+type memAdjustmentRepo struct {
+	requests map[string]domain.AdjustmentRequest
+}
+
+func newMemAdjustmentRepo() *memAdjustmentRepo {
+	return &memAdjustmentRepo{requests: make(map[string]domain.AdjustmentRequest)}
+}
+
+func (r *memAdjustmentRepo) Save(ctx context.Context, request *domain.AdjustmentRequest) error {
+	r.requests[request.ID] = *request
+	return nil
+}
+
+func (r *memAdjustmentRepo) FindByID(ctx context.Context, id string) (*domain.AdjustmentRequest, error) {
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, domain.ErrAdjustmentNotFound
+	}
+	return &request, nil
+}
+
+func (r *memAdjustmentRepo) FindByIDForUpdate(ctx context.Context, id string) (*domain.AdjustmentRequest, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *memAdjustmentRepo) List(ctx context.Context, status domain.AdjustmentRequestStatus) ([]domain.AdjustmentRequest, error) {
+	var requests []domain.AdjustmentRequest
+	for _, request := range r.requests {
+		if status == "" || request.Status == status {
+			requests = append(requests, request)
+		}
+	}
+	return requests, nil
+}
+
+func (r *memAdjustmentRepo) Resolve(ctx context.Context, request *domain.AdjustmentRequest) error {
+	if r.requests[request.ID].Status != domain.AdjustmentPending {
+		return domain.ErrAdjustmentNotPending
+	}
+	r.requests[request.ID] = *request
+	return nil
+}
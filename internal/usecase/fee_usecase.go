@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// FeeUsecase credits the fees charged to payers to the house wallets. Fees are
+// queued as they are charged (see FeeCredit), so the house wallets are updated
+// once per batch instead of once per fee.
+type FeeUsecase interface {
+	// CreditPending credits the queued fees and returns how many it credited.
+	CreditPending(ctx context.Context) (int, error)
+}
+
+type feeUsecase struct {
+	feeRepo      domain.FeeCreditRepository
+	walletRepo   domain.WalletRepository
+	movementRepo domain.MovementRepository
+	txnRepo      domain.TxnRepository
+	batchSize    int
+	logger       *slog.Logger
+}
+
+// NewFeeUsecase credits fees batchSize at a time.
+func NewFeeUsecase(fr domain.FeeCreditRepository, wr domain.WalletRepository, mr domain.MovementRepository, tr domain.TxnRepository, batchSize int, logger *slog.Logger) FeeUsecase {
+	return &feeUsecase{
+		feeRepo:      fr,
+		walletRepo:   wr,
+		movementRepo: mr,
+		txnRepo:      tr,
+		batchSize:    batchSize,
+		logger:       logger,
+	}
+}
+
+// CreditPending handles the fees in batches, each in a transaction that claims
+// them, credits them and deletes them, so every fee is credited exactly once
+// however many instances run it.
+func (u *feeUsecase) CreditPending(ctx context.Context) (int, error) {
+	credited := 0
+	for ctx.Err() == nil {
+		var claimed int
+		err := withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+			fees, err := u.feeRepo.Claim(txCtx, u.batchSize)
+			if err != nil {
+				return err
+			}
+			claimed = len(fees)
+			return u.credit(txCtx, fees)
+		})
+		if err != nil {
+			return credited, err
+		}
+		credited += claimed
+		if claimed < u.batchSize {
+			break
+		}
+	}
+	if credited > 0 {
+		u.logger.InfoContext(ctx, "credited fees to house wallets", "fees", credited)
+	}
+	return credited, ctx.Err()
+}
+
+// credit adds fees, the payers' fee movements, to their house wallets with
+// one update per wallet, and records a fee_income movement for each fee.
+func (u *feeUsecase) credit(ctx context.Context, fees []domain.Movement) error {
+	if len(fees) == 0 {
+		return nil
+	}
+
+	byWallet := make(map[string][]domain.Movement)
+	for _, fee := range fees {
+		byWallet[*fee.CounterpartyWalletID] = append(byWallet[*fee.CounterpartyWalletID], fee)
+	}
+	// Concurrent batches update the house wallets in the same order.
+	walletIDs := make([]string, 0, len(byWallet))
+	for id := range byWallet {
+		walletIDs = append(walletIDs, id)
+	}
+	slices.Sort(walletIDs)
+
+	var movements []*domain.Movement
+	for _, id := range walletIDs {
+		wallet, err := u.walletRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, fee := range byWallet[id] {
+			wallet.Balance -= fee.Amount
+			payerID := fee.WalletID
+			movements = append(movements, &domain.Movement{
+				ID:                   uuid.New().String(),
+				WalletID:             wallet.ID,
+				Type:                 domain.MovementFeeIncome,
+				Amount:               -fee.Amount,
+				BalanceAfter:         wallet.Balance,
+				CounterpartyWalletID: &payerID,
+				Reference:            fee.Reference,
+				Actor:                fee.Actor,
+			})
+		}
+		if err := u.walletRepo.Update(ctx, wallet); err != nil {
+			return err
+		}
+	}
+
+	for _, movement := range movements {
+		if err := u.movementRepo.Save(ctx, movement); err != nil {
+			return err
+		}
+	}
+	ids := make([]string, len(fees))
+	for i, fee := range fees {
+		ids[i] = fee.ID
+	}
+	return u.feeRepo.Delete(ctx, ids)
+}
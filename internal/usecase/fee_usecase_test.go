@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"wallet/internal/domain"
+)
+
+// Fees are taken from the payer as they are charged, but credited to the house
+// wallet in batches: charging one never updates the house wallet row.
+func TestFeesAreCreditedToTheHouseWalletInBatches(t *testing.T) {
+	f := newWalletFixture(100, 0)
+	ctx := domain.WithActor(context.Background(), domain.UserActor("alice"))
+	f.wallets.Save(ctx, &domain.Wallet{ID: "w-house", UserID: "house", Currency: "USD", Status: domain.WalletActive})
+	f.fees = &domain.FeeSchedule{
+		Wallets: map[string]string{"USD": "w-house"},
+		Rules:   []domain.FeeRule{{Operation: domain.FeeTransfer, Fixed: 1}},
+	}
+	wallets := f.usecase(nil, nil)
+
+	for range 2 {
+		if err := wallets.Transfer(ctx, "w-alice", "w-bob", 10); err != nil {
+			t.Fatalf("Transfer: %v", err)
+		}
+	}
+	if got := f.wallets.get("w-alice").Balance; got != 78 {
+		t.Errorf("payer balance = %v, want 78 (100 - 2 × (10 + 1))", got)
+	}
+	if house := f.wallets.get("w-house"); house.Balance != 0 || house.Version != 0 {
+		t.Errorf("house wallet = balance %v, version %d; want it untouched by the transfers", house.Balance, house.Version)
+	}
+	if len(f.feeCredits.queued) != 2 {
+		t.Fatalf("%d fees queued, want 2", len(f.feeCredits.queued))
+	}
+
+	fees := NewFeeUsecase(f.feeCredits, f.wallets, f.movements, fakeTxnRepo{}, 500, discardLogger)
+	credited, err := fees.CreditPending(context.Background())
+	if err != nil || credited != 2 {
+		t.Fatalf("CreditPending = %d, %v; want 2 fees credited", credited, err)
+	}
+	if house := f.wallets.get("w-house"); house.Balance != 2 || house.Version != 1 {
+		t.Errorf("house wallet = balance %v, version %d; want 2, credited in a single update", house.Balance, house.Version)
+	}
+	if len(f.feeCredits.queued) != 0 {
+		t.Errorf("%d fees still queued", len(f.feeCredits.queued))
+	}
+
+	var balances []float64
+	for _, m := range f.movements.movements {
+		if m.Type != domain.MovementFeeIncome {
+			continue
+		}
+		if m.WalletID != "w-house" || m.Amount != 1 || m.CounterpartyWalletID == nil || *m.CounterpartyWalletID != "w-alice" {
+			t.Errorf("fee income movement %+v, want 1 from w-alice to w-house", m)
+		}
+		balances = append(balances, m.BalanceAfter)
+	}
+	if len(balances) != 2 || balances[0] != 1 || balances[1] != 2 {
+		t.Errorf("fee income balances after = %v, want [1 2]", balances)
+	}
+
+	if credited, err := fees.CreditPending(context.Background()); err != nil || credited != 0 {
+		t.Errorf("second CreditPending = %d, %v; want nothing left to credit", credited, err)
+	}
+}
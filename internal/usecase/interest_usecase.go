@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// interestBatchSize is how many wallets are read per query when accruing and posting.
+const interestBatchSize = 500
+
+// InterestReport is the outcome of an interest run.
+type InterestReport struct {
+	// From and Through are the days accrued; From is after Through when later
+	// days were already accrued.
+	From    time.Time `json:"from"`
+	Through time.Time `json:"through"`
+	Accrued int       `json:"accrued"` // Accruals stored
+	Posted  int       `json:"posted"`  // Wallets paid
+	Paid    float64   `json:"paid"`    // Sum of the interest paid, across currencies
+}
+
+// InterestUsecase accrues and pays interest on the wallets whose product has
+// an interest rate.
+type InterestUsecase interface {
+	// Run accrues every day from the last one accrued through through (only
+	// through on the first run), so days missed while no instance ran are
+	// caught up, and then pays the accruals of every month that has ended.
+	// The last day accrued is accrued again, as a run may have stopped
+	// partway through it; wallets already accrued for a day are skipped, so
+	// running it again never pays twice.
+	Run(ctx context.Context, through time.Time) (*InterestReport, error)
+	// Summary returns the product and pending interest of a wallet to its members.
+	Summary(ctx context.Context, walletID string) (*domain.InterestSummary, error)
+}
+
+type interestUsecase struct {
+	interestRepo domain.InterestRepository
+	walletRepo   domain.WalletRepository
+	memberRepo   domain.WalletMemberRepository
+	movementRepo domain.MovementRepository
+	txnRepo      domain.TxnRepository
+	dayCount     domain.DayCount
+	logger       *slog.Logger
+}
+
+func NewInterestUsecase(ir domain.InterestRepository, wr domain.WalletRepository, mbr domain.WalletMemberRepository, mr domain.MovementRepository, tr domain.TxnRepository, dayCount domain.DayCount, logger *slog.Logger) InterestUsecase {
+	return &interestUsecase{
+		interestRepo: ir,
+		walletRepo:   wr,
+		memberRepo:   mbr,
+		movementRepo: mr,
+		txnRepo:      tr,
+		dayCount:     dayCount,
+		logger:       logger,
+	}
+}
+
+func (u *interestUsecase) Run(ctx context.Context, through time.Time) (*InterestReport, error) {
+	through = startOfDay(through)
+	report := &InterestReport{From: through, Through: through}
+
+	last, err := u.interestRepo.LastAccruedDay(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		report.From = startOfDay(*last)
+	}
+
+	for day := report.From; !day.After(through); day = day.AddDate(0, 0, 1) {
+		accrued, err := u.accrue(ctx, day)
+		report.Accrued += accrued
+		if err != nil {
+			return report, fmt.Errorf("accrue %s: %w", day.Format(time.DateOnly), err)
+		}
+	}
+
+	// Accruals are paid once their month is over.
+	next := through.AddDate(0, 0, 1)
+	monthStart := time.Date(next.Year(), next.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if err := u.post(ctx, monthStart, report); err != nil {
+		return report, err
+	}
+
+	u.logger.InfoContext(ctx, "interest run finished",
+		"from", report.From.Format(time.DateOnly),
+		"through", through.Format(time.DateOnly),
+		"accrued", report.Accrued,
+		"posted", report.Posted,
+		"paid", report.Paid,
+	)
+	return report, nil
+}
+
+// accrue records the interest every wallet earned over day. Wallets already
+// accrued for day are skipped by the repository.
+func (u *interestUsecase) accrue(ctx context.Context, day time.Time) (int, error) {
+	stored := 0
+	afterID := ""
+	for {
+		candidates, err := u.interestRepo.ListCandidates(ctx, day, afterID, interestBatchSize)
+		if err != nil || len(candidates) == 0 {
+			return stored, err
+		}
+
+		accruals := make([]domain.InterestAccrual, 0, len(candidates))
+		for _, c := range candidates {
+			// Overdrawn and empty wallets earn nothing.
+			if c.Balance <= 0 {
+				continue
+			}
+			amount := math.Round(u.dayCount.DailyInterest(c.Balance, c.Rate, day)*1e8) / 1e8
+			accruals = append(accruals, domain.InterestAccrual{
+				ID:       uuid.New().String(),
+				WalletID: c.WalletID,
+				Day:      day,
+				Balance:  c.Balance,
+				Rate:     c.Rate,
+				Amount:   amount,
+			})
+		}
+		n, err := u.interestRepo.SaveAccruals(ctx, accruals)
+		stored += n
+		if err != nil {
+			return stored, err
+		}
+		afterID = candidates[len(candidates)-1].WalletID
+	}
+}
+
+// post pays every wallet its pending accruals for the days before before.
+func (u *interestUsecase) post(ctx context.Context, before time.Time, report *InterestReport) error {
+	afterID := ""
+	for {
+		walletIDs, err := u.interestRepo.ListWalletsToPost(ctx, before, afterID, interestBatchSize)
+		if err != nil || len(walletIDs) == 0 {
+			return err
+		}
+		for _, walletID := range walletIDs {
+			paid, err := u.postWallet(ctx, walletID, before)
+			if err != nil {
+				return fmt.Errorf("post interest of wallet %s: %w", walletID, err)
+			}
+			if paid != 0 {
+				report.Posted++
+				report.Paid += paid
+			}
+		}
+		afterID = walletIDs[len(walletIDs)-1]
+	}
+}
+
+// postWallet credits a wallet with its pending accruals, rounded half to even
+// to the cent, and marks them paid in the same transaction. The accruals stay
+// locked until the commit, so concurrent runs can't pay them again.
+func (u *interestUsecase) postWallet(ctx context.Context, walletID string, before time.Time) (float64, error) {
+	var paid float64
+	err := withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+		paid = 0
+		accruals, err := u.interestRepo.LockPending(txCtx, walletID, before)
+		if err != nil || len(accruals) == 0 {
+			return err
+		}
+
+		var sum float64
+		ids := make([]string, len(accruals))
+		for i, a := range accruals {
+			sum += a.Amount
+			ids[i] = a.ID
+		}
+		total := math.RoundToEven(sum*100) / 100
+
+		// Interest is paid to frozen wallets too: it was earned before or while frozen.
+		var movementID *string
+		if total != 0 {
+			wallet, err := u.walletRepo.FindByID(txCtx, walletID)
+			if err != nil {
+				return err
+			}
+			wallet.Balance += total
+			if err := u.walletRepo.Update(txCtx, wallet); err != nil {
+				return err
+			}
+			reference := fmt.Sprintf("interest %s to %s",
+				accruals[0].Day.Format(time.DateOnly), accruals[len(accruals)-1].Day.Format(time.DateOnly))
+			movement := newMovement(txCtx, wallet, domain.MovementInterest, total, nil, reference)
+			if err := u.movementRepo.Save(txCtx, movement); err != nil {
+				return err
+			}
+			movementID = &movement.ID
+		}
+		if err := u.interestRepo.MarkPosted(txCtx, ids, movementID, time.Now()); err != nil {
+			return err
+		}
+		paid = total
+		return nil
+	})
+	return paid, err
+}
+
+func (u *interestUsecase) Summary(ctx context.Context, walletID string) (*domain.InterestSummary, error) {
+	wallet, err := u.walletRepo.FindByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, walletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	product, err := u.interestRepo.FindProduct(ctx, wallet.Product)
+	if err != nil {
+		return nil, err
+	}
+	pending, last, err := u.interestRepo.Pending(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.InterestSummary{
+		WalletID:       wallet.ID,
+		Product:        product.Code,
+		InterestRate:   product.InterestRate,
+		Pending:        math.RoundToEven(pending*100) / 100,
+		AccruedThrough: last,
+	}, nil
+}
+
+// startOfDay truncates t to midnight UTC.
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+)
+
+// memInterestRepo holds accruals in memory; its candidates are set by the test.
+type memInterestRepo struct {
+	mu         sync.Mutex
+	candidates []domain.InterestCandidate
+	accruals   []domain.InterestAccrual
+	products   map[string]domain.WalletProduct
+}
+
+func (r *memInterestRepo) ListCandidates(ctx context.Context, day time.Time, afterID string, limit int) ([]domain.InterestCandidate, error) {
+	var page []domain.InterestCandidate
+	for _, c := range r.candidates {
+		if c.WalletID > afterID && len(page) < limit {
+			page = append(page, c)
+		}
+	}
+	return page, nil
+}
+
+func (r *memInterestRepo) SaveAccruals(ctx context.Context, accruals []domain.InterestAccrual) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := 0
+	for _, a := range accruals {
+		if r.accrued(a.WalletID, a.Day) {
+			continue
+		}
+		r.accruals = append(r.accruals, a)
+		stored++
+	}
+	return stored, nil
+}
+
+func (r *memInterestRepo) accrued(walletID string, day time.Time) bool {
+	for _, a := range r.accruals {
+		if a.WalletID == walletID && a.Day.Equal(day) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memInterestRepo) LastAccruedDay(ctx context.Context) (*time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var last *time.Time
+	for _, a := range r.accruals {
+		if last == nil || a.Day.After(*last) {
+			day := a.Day
+			last = &day
+		}
+	}
+	return last, nil
+}
+
+func (r *memInterestRepo) ListWalletsToPost(ctx context.Context, before time.Time, afterID string, limit int) ([]string, error) {
+	return nil, nil
+}
+
+func (r *memInterestRepo) LockPending(ctx context.Context, walletID string, before time.Time) ([]domain.InterestAccrual, error) {
+	return nil, nil
+}
+
+func (r *memInterestRepo) MarkPosted(ctx context.Context, ids []string, movementID *string, postedAt time.Time) error {
+	return nil
+}
+
+func (r *memInterestRepo) Pending(ctx context.Context, walletID string) (float64, *time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var pending float64
+	var last *time.Time
+	for _, a := range r.accruals {
+		if a.WalletID == walletID {
+			pending += a.Amount
+			if last == nil || a.Day.After(*last) {
+				day := a.Day
+				last = &day
+			}
+		}
+	}
+	return pending, last, nil
+}
+
+func (r *memInterestRepo) FindProduct(ctx context.Context, code string) (*domain.WalletProduct, error) {
+	product, ok := r.products[code]
+	if !ok {
+		return nil, domain.ErrUnknownProduct
+	}
+	return &product, nil
+}
+
+// A run that stopped partway through a day left some wallets unaccrued for
+// it; the next run must finish that day, not start on the one after.
+func TestInterestRunFinishesAPartlyAccruedDay(t *testing.T) {
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	interest := &memInterestRepo{
+		candidates: []domain.InterestCandidate{
+			{WalletID: "w-alice", Balance: 1000, Rate: 2},
+			{WalletID: "w-bob", Balance: 500, Rate: 2},
+		},
+		accruals: []domain.InterestAccrual{{ID: "a-1", WalletID: "w-alice", Day: day, Balance: 1000, Rate: 2, Amount: 0.05}},
+	}
+	u := NewInterestUsecase(interest, newMemWalletRepo(), &memMemberRepo{}, &memMovementRepo{}, fakeTxnRepo{}, domain.DayCountAct365, discardLogger)
+
+	report, err := u.Run(context.Background(), day)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.Accrued != 1 {
+		t.Errorf("%d accruals stored, want 1 for w-bob", report.Accrued)
+	}
+	if !interest.accrued("w-bob", day) {
+		t.Errorf("w-bob was not accrued for %s", day.Format(time.DateOnly))
+	}
+	if len(interest.accruals) != 2 {
+		t.Errorf("%d accruals, want 2: w-alice must not be accrued twice", len(interest.accruals))
+	}
+}
+
+// The interest summary shows the balance a wallet earns on, so it is for the
+// wallet's members and operators only.
+func TestInterestSummaryIsForMembers(t *testing.T) {
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	f := newWalletFixture(1000, 0)
+	alice := f.wallets.get("w-alice")
+	alice.Product = "savings"
+	f.wallets.Save(context.Background(), &alice)
+	interest := &memInterestRepo{
+		accruals: []domain.InterestAccrual{{ID: "a-1", WalletID: "w-alice", Day: day, Balance: 1000, Rate: 2, Amount: 0.05}},
+		products: map[string]domain.WalletProduct{"savings": {Code: "savings", InterestRate: 2}},
+	}
+	u := NewInterestUsecase(interest, f.wallets, f.members, f.movements, fakeTxnRepo{}, domain.DayCountAct365, discardLogger)
+
+	tests := []struct {
+		actor string
+		want  error
+	}{
+		{domain.UserActor("alice"), nil},
+		{domain.OperatorActor("carol"), nil},
+		{domain.UserActor("bob"), domain.ErrNotWalletMember},
+		{domain.AnonymousActor, domain.ErrNotWalletMember},
+	}
+	for _, tt := range tests {
+		summary, err := u.Summary(domain.WithActor(context.Background(), tt.actor), "w-alice")
+		if !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+			t.Errorf("Summary as %s = %v, want %v", tt.actor, err, tt.want)
+			continue
+		}
+		if err == nil && (summary.Product != "savings" || summary.Pending != 0.05) {
+			t.Errorf("Summary as %s = %+v, want savings with 0.05 pending", tt.actor, summary)
+		}
+		if err != nil && summary != nil {
+			t.Errorf("Summary as %s returned a summary with its error", tt.actor)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// maxPaymentRequestNote is the longest note a payment request may carry.
+const maxPaymentRequestNote = 255
+
+// PaymentRequestInput describes a payment request to create.
+type PaymentRequestInput struct {
+	RequesterWalletID string  `json:"requester_wallet_id"`
+	PayerWalletID     string  `json:"payer_wallet_id"`
+	Amount            float64 `json:"amount"`
+	Note              string  `json:"note,omitempty"`
+	// ExpiresAt defaults to the configured time to live from now.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// PaymentRequestUsecase lets a user request money from another.
+type PaymentRequestUsecase interface {
+	// Create sends a request from the requester wallet, which a user must be
+	// a member of.
+	Create(ctx context.Context, input PaymentRequestInput) (*domain.PaymentRequest, error)
+	// Get returns a request to the members of either wallet.
+	Get(ctx context.Context, id string) (*domain.PaymentRequest, error)
+	// Accept pays a pending request with a transfer from the payer to the
+	// requester. A request is paid at most once, however many accepts race.
+	// Only members of the payer wallet resolve a request, and the transfer
+	// takes the right to spend from it. A transfer the risk rules hold leaves
+	// the request held until its review is decided.
+	Accept(ctx context.Context, id string) (*domain.PaymentRequest, error)
+	Decline(ctx context.Context, id string) (*domain.PaymentRequest, error)
+	// SettleHeld ends the request held on a review the operator acting just
+	// decided, in the same transaction: paid if the review was approved and
+	// its transfer made, declined otherwise. Reviews holding no request are
+	// ignored.
+	SettleHeld(ctx context.Context, review *domain.TransferReview) error
+	// ListOpen returns the pending requests a wallet sent or received, to its
+	// members.
+	ListOpen(ctx context.Context, walletID string) ([]domain.PaymentRequest, error)
+	// ExpireDue expires the pending requests past their expiry and returns how many.
+	ExpireDue(ctx context.Context) (int, error)
+}
+
+type paymentRequestUsecase struct {
+	requestRepo   domain.PaymentRequestRepository
+	walletRepo    domain.WalletRepository
+	memberRepo    domain.WalletMemberRepository
+	walletUsecase WalletUsecase
+	txnRepo       domain.TxnRepository
+	notifier      domain.Notifier
+	ttl           time.Duration
+	logger        *slog.Logger
+}
+
+// NewPaymentRequestUsecase creates requests that expire after ttl unless they
+// set their own expiry.
+func NewPaymentRequestUsecase(rr domain.PaymentRequestRepository, wr domain.WalletRepository, mbr domain.WalletMemberRepository, wu WalletUsecase, tr domain.TxnRepository, notifier domain.Notifier, ttl time.Duration, logger *slog.Logger) PaymentRequestUsecase {
+	return &paymentRequestUsecase{
+		requestRepo:   rr,
+		walletRepo:    wr,
+		memberRepo:    mbr,
+		walletUsecase: wu,
+		txnRepo:       tr,
+		notifier:      notifier,
+		ttl:           ttl,
+		logger:        logger,
+	}
+}
+
+func (u *paymentRequestUsecase) Create(ctx context.Context, input PaymentRequestInput) (*domain.PaymentRequest, error) {
+	now := time.Now()
+	expiresAt := now.Add(u.ttl)
+	if input.ExpiresAt != nil {
+		expiresAt = *input.ExpiresAt
+	}
+	switch {
+	case input.Amount <= 0:
+		return nil, fmt.Errorf("%w: amount must be positive", domain.ErrInvalidPaymentRequest)
+	case input.RequesterWalletID == input.PayerWalletID:
+		return nil, fmt.Errorf("%w: cannot request money from the same wallet", domain.ErrInvalidPaymentRequest)
+	case len(input.Note) > maxPaymentRequestNote:
+		return nil, fmt.Errorf("%w: note is longer than %d characters", domain.ErrInvalidPaymentRequest, maxPaymentRequestNote)
+	case !expiresAt.After(now):
+		return nil, fmt.Errorf("%w: expires_at must be in the future", domain.ErrInvalidPaymentRequest)
+	}
+
+	requester, err := u.walletRepo.FindByID(ctx, input.RequesterWalletID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, requester.ID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	payer, err := u.walletRepo.FindByID(ctx, input.PayerWalletID)
+	if err != nil {
+		return nil, err
+	}
+	if requester.Currency != payer.Currency {
+		return nil, fmt.Errorf("%w: the wallets hold different currencies", domain.ErrInvalidPaymentRequest)
+	}
+
+	request := &domain.PaymentRequest{
+		ID:                uuid.New().String(),
+		RequesterWalletID: requester.ID,
+		PayerWalletID:     payer.ID,
+		Amount:            input.Amount,
+		Note:              input.Note,
+		Status:            domain.PaymentRequestPending,
+		Actor:             domain.ActorFromContext(ctx),
+		ExpiresAt:         expiresAt,
+	}
+	if err := u.requestRepo.Save(ctx, request); err != nil {
+		return nil, err
+	}
+	u.logger.InfoContext(ctx, "payment request created",
+		"payment_request_id", request.ID,
+		"requester_wallet", request.RequesterWalletID,
+		"payer_wallet", request.PayerWalletID,
+		"amount", request.Amount,
+	)
+
+	message := fmt.Sprintf("You have been asked to pay %.2f %s", request.Amount, payer.Currency)
+	if request.Note != "" {
+		message += ": " + request.Note
+	}
+	u.notify(ctx, domain.NotificationPaymentRequested, payer.UserID, message, request)
+	return request, nil
+}
+
+func (u *paymentRequestUsecase) Get(ctx context.Context, id string) (*domain.PaymentRequest, error) {
+	request, err := u.requestRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = authorizeMember(ctx, u.memberRepo, request.RequesterWalletID, isMember, domain.ErrNotWalletMember)
+	if errors.Is(err, domain.ErrNotWalletMember) {
+		_, err = authorizeMember(ctx, u.memberRepo, request.PayerWalletID, isMember, domain.ErrNotWalletMember)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (u *paymentRequestUsecase) Accept(ctx context.Context, id string) (*domain.PaymentRequest, error) {
+	return u.resolve(ctx, id, domain.PaymentRequestPaid, func(txCtx context.Context, request *domain.PaymentRequest) error {
+		// The transfer commits or rolls back with the new state of the request.
+		err := u.walletUsecase.Transfer(txCtx, request.PayerWalletID, request.RequesterWalletID, request.Amount)
+		var held *domain.TransferHeldError
+		if errors.As(err, &held) {
+			// The review is committed with the request, which waits for it.
+			request.Status = domain.PaymentRequestHeld
+			request.ReviewID = &held.ReviewID
+			return nil
+		}
+		return err
+	})
+}
+
+func (u *paymentRequestUsecase) Decline(ctx context.Context, id string) (*domain.PaymentRequest, error) {
+	return u.resolve(ctx, id, domain.PaymentRequestDeclined, nil)
+}
+
+// resolve moves a pending request to status, running apply first in the same
+// transaction, once the user acting is found to be a member of the payer
+// wallet. The request stays locked from the moment it is read, so
+// concurrent resolutions wait and then find it no longer pending. A request
+// found past its expiry is expired instead, and ErrPaymentRequestExpired
+// returned.
+func (u *paymentRequestUsecase) resolve(ctx context.Context, id string, status domain.PaymentRequestStatus, apply func(ctx context.Context, request *domain.PaymentRequest) error) (*domain.PaymentRequest, error) {
+	var request *domain.PaymentRequest
+	var expired bool
+	err := withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+		var err error
+		request, err = u.requestRepo.FindByIDForUpdate(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := authorizeMember(txCtx, u.memberRepo, request.PayerWalletID, isMember, domain.ErrNotWalletMember); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		expired = request.IsExpired(now)
+		target := status
+		if expired {
+			target = domain.PaymentRequestExpired
+		}
+		if err := request.Transition(target, domain.ActorFromContext(txCtx), now); err != nil {
+			return err
+		}
+		if apply != nil && !expired {
+			if err := apply(txCtx, request); err != nil {
+				return err
+			}
+		}
+		if err := u.requestRepo.Resolve(txCtx, request, domain.PaymentRequestPending); err != nil {
+			return err
+		}
+		if request.Status != domain.PaymentRequestHeld {
+			domain.AfterCommit(txCtx, func(ctx context.Context) {
+				u.notifyResolved(ctx, request)
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, domain.ErrPaymentRequestExpired
+	}
+
+	u.logger.InfoContext(ctx, "payment request resolved",
+		"payment_request_id", request.ID,
+		"status", request.Status,
+		"actor", request.ResolvedBy,
+	)
+	return request, nil
+}
+
+func (u *paymentRequestUsecase) SettleHeld(ctx context.Context, review *domain.TransferReview) error {
+	if _, ok := domain.UserFromContext(ctx); ok {
+		return domain.ErrReviewByUser
+	}
+	request, err := u.requestRepo.FindByReviewIDForUpdate(ctx, review.ID)
+	if errors.Is(err, domain.ErrPaymentRequestNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	status := domain.PaymentRequestDeclined
+	if review.Status == domain.ReviewApproved {
+		status = domain.PaymentRequestPaid
+	}
+	if err := request.Transition(status, domain.ActorFromContext(ctx), time.Now()); err != nil {
+		return err
+	}
+	if err := u.requestRepo.Resolve(ctx, request, domain.PaymentRequestHeld); err != nil {
+		return err
+	}
+	domain.AfterCommit(ctx, func(ctx context.Context) {
+		u.notifyResolved(ctx, request)
+	})
+
+	u.logger.InfoContext(ctx, "held payment request settled",
+		"payment_request_id", request.ID,
+		"review_id", review.ID,
+		"status", request.Status,
+	)
+	return nil
+}
+
+func (u *paymentRequestUsecase) ListOpen(ctx context.Context, walletID string) ([]domain.PaymentRequest, error) {
+	if _, err := u.walletRepo.FindByID(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, walletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	return u.requestRepo.ListOpen(ctx, walletID, time.Now())
+}
+
+func (u *paymentRequestUsecase) ExpireDue(ctx context.Context) (int, error) {
+	expired, err := u.requestRepo.ExpireDue(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	for i := range expired {
+		u.notifyResolved(ctx, &expired[i])
+	}
+	if len(expired) > 0 {
+		u.logger.InfoContext(ctx, "payment requests expired", "count", len(expired))
+	}
+	return len(expired), nil
+}
+
+// notifyResolved tells the requester how their request ended.
+func (u *paymentRequestUsecase) notifyResolved(ctx context.Context, request *domain.PaymentRequest) {
+	wallet, err := u.walletRepo.FindByID(ctx, request.RequesterWalletID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to notify payment request resolution", "payment_request_id", request.ID, "error", err)
+		return
+	}
+	message := fmt.Sprintf("Your request for %.2f %s was %s", request.Amount, wallet.Currency, request.Status)
+	u.notify(ctx, domain.NotificationPaymentRequestResolved, wallet.UserID, message, request)
+}
+
+func (u *paymentRequestUsecase) notify(ctx context.Context, kind, userID, message string, request *domain.PaymentRequest) {
+	err := u.notifier.Notify(ctx, domain.Notification{
+		Kind:    kind,
+		UserID:  userID,
+		Message: message,
+		Data: map[string]interface{}{
+			"payment_request_id":  request.ID,
+			"requester_wallet_id": request.RequesterWalletID,
+			"payer_wallet_id":     request.PayerWalletID,
+			"amount":              request.Amount,
+			"status":              request.Status,
+		},
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		u.logger.ErrorContext(ctx, "failed to send payment request notification", "payment_request_id", request.ID, "kind", kind, "error", err)
+	}
+}
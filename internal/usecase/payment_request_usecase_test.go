@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+)
+
+// Only members of the payer wallet resolve a request, and only members of
+// either wallet see it.
+func TestPaymentRequestsAreRestrictedToTheirWallets(t *testing.T) {
+	f := newWalletFixture(0, 100)
+	f.wallets.Save(context.Background(), &domain.Wallet{ID: "w-mallory", UserID: "mallory", Currency: "USD", Status: domain.WalletActive})
+	f.members.owner("w-mallory", "mallory")
+	requests := NewPaymentRequestUsecase(newMemPaymentRequestRepo(), f.wallets, f.members, f.usecase(nil, nil), fakeTxnRepo{}, nopNotifier{}, time.Hour, discardLogger)
+
+	alice := domain.WithActor(context.Background(), domain.UserActor("alice"))
+	bob := domain.WithActor(context.Background(), domain.UserActor("bob"))
+	mallory := domain.WithActor(context.Background(), domain.UserActor("mallory"))
+
+	if _, err := requests.Create(mallory, PaymentRequestInput{RequesterWalletID: "w-alice", PayerWalletID: "w-bob", Amount: 10}); !errors.Is(err, domain.ErrNotWalletMember) {
+		t.Errorf("Create from someone else's wallet: %v, want ErrNotWalletMember", err)
+	}
+	request, err := requests.Create(alice, PaymentRequestInput{RequesterWalletID: "w-alice", PayerWalletID: "w-bob", Amount: 10})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if _, err := requests.Get(mallory, request.ID); !errors.Is(err, domain.ErrNotWalletMember) {
+		t.Errorf("Get by an outsider: %v, want ErrNotWalletMember", err)
+	}
+	if _, err := requests.ListOpen(mallory, "w-bob"); !errors.Is(err, domain.ErrNotWalletMember) {
+		t.Errorf("ListOpen by an outsider: %v, want ErrNotWalletMember", err)
+	}
+	for _, ctx := range []context.Context{alice, bob} {
+		if _, err := requests.Get(ctx, request.ID); err != nil {
+			t.Errorf("Get by %s: %v", domain.ActorFromContext(ctx), err)
+		}
+	}
+
+	for _, ctx := range []context.Context{mallory, alice} {
+		if _, err := requests.Accept(ctx, request.ID); !errors.Is(err, domain.ErrNotWalletMember) {
+			t.Errorf("Accept by %s: %v, want ErrNotWalletMember", domain.ActorFromContext(ctx), err)
+		}
+		if _, err := requests.Decline(ctx, request.ID); !errors.Is(err, domain.ErrNotWalletMember) {
+			t.Errorf("Decline by %s: %v, want ErrNotWalletMember", domain.ActorFromContext(ctx), err)
+		}
+	}
+	if got := f.wallets.get("w-bob").Balance; got != 100 {
+		t.Fatalf("payer balance = %v after refused accepts, want 100", got)
+	}
+
+	paid, err := requests.Accept(bob, request.ID)
+	if err != nil || paid.Status != domain.PaymentRequestPaid {
+		t.Fatalf("Accept by the payer = %+v, %v; want it paid", paid, err)
+	}
+	if got := f.wallets.get("w-alice").Balance; got != 10 {
+		t.Errorf("requester balance = %v, want 10", got)
+	}
+}
+
+// A payment whose transfer is held for review waits for the review, which
+// pays or declines the request.
+func TestHeldPaymentRequestsSettleWithTheirReview(t *testing.T) {
+	f := newWalletFixture(0, 100)
+	wallets := f.usecase(nil, reviewEveryTransfer{})
+	requests := NewPaymentRequestUsecase(newMemPaymentRequestRepo(), f.wallets, f.members, wallets, fakeTxnRepo{}, nopNotifier{}, time.Hour, discardLogger)
+	reviews := NewTransferReviewUsecase(f.reviews, wallets, requests, f.audit, fakeTxnRepo{})
+
+	alice := domain.WithActor(context.Background(), domain.UserActor("alice"))
+	bob := domain.WithActor(context.Background(), domain.UserActor("bob"))
+	operator := domain.WithActor(context.Background(), domain.OperatorActor("carol"))
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		request, err := requests.Create(alice, PaymentRequestInput{RequesterWalletID: "w-alice", PayerWalletID: "w-bob", Amount: 10})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		held, err := requests.Accept(bob, request.ID)
+		if err != nil || held.Status != domain.PaymentRequestHeld || held.ReviewID == nil {
+			t.Fatalf("Accept = %+v, %v; want it held on a review", held, err)
+		}
+		ids = append(ids, request.ID)
+	}
+	if got := f.wallets.get("w-bob").Balance; got != 100 {
+		t.Fatalf("payer balance = %v with the transfers held, want 100", got)
+	}
+
+	approved, _ := requests.Get(bob, ids[0])
+	if _, err := reviews.Approve(operator, *approved.ReviewID, ""); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	rejected, _ := requests.Get(bob, ids[1])
+	if _, err := reviews.Reject(operator, *rejected.ReviewID, ""); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+
+	for id, want := range map[string]domain.PaymentRequestStatus{ids[0]: domain.PaymentRequestPaid, ids[1]: domain.PaymentRequestDeclined} {
+		request, _ := requests.Get(bob, id)
+		if request.Status != want || request.ResolvedBy != domain.UserActor("bob") {
+			t.Errorf("request = %s resolved by %s, want %s resolved by the payer", request.Status, request.ResolvedBy, want)
+		}
+	}
+	if got := f.wallets.get("w-alice").Balance; got != 10 {
+		t.Errorf("requester balance = %v, want 10", got)
+	}
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// PocketInput describes a pocket to create.
+type PocketInput struct {
+	Name         string     `json:"name"`
+	TargetAmount float64    `json:"target_amount,omitempty"`
+	TargetDate   *time.Time `json:"target_date,omitempty"`
+}
+
+// PocketUsecase sets money aside in pockets of a wallet. Moving money in and
+// out of a pocket doesn't change the wallet's balance, only how much of it
+// can be spent (see Wallet.AvailableBalance).
+type PocketUsecase interface {
+	Create(ctx context.Context, walletID string, input PocketInput) (*domain.Pocket, error)
+	Get(ctx context.Context, pocketID string) (*domain.PocketProgress, error)
+	// List returns the open pockets of a wallet.
+	List(ctx context.Context, walletID string) ([]domain.PocketProgress, error)
+	ListMovements(ctx context.Context, pocketID string) ([]domain.PocketMovement, error)
+	// Deposit moves amount from the wallet's main balance into the pocket.
+	// The overdraft can't be used for it.
+	Deposit(ctx context.Context, pocketID string, amount float64) (*domain.PocketProgress, error)
+	// Withdraw moves amount from the pocket back to the wallet's main balance.
+	Withdraw(ctx context.Context, pocketID string, amount float64) (*domain.PocketProgress, error)
+	// Close moves what is left in the pocket back to the wallet and closes it.
+	Close(ctx context.Context, pocketID string) error
+}
+
+type pocketUsecase struct {
+	pocketRepo domain.PocketRepository
+	walletRepo domain.WalletRepository
+	memberRepo domain.WalletMemberRepository
+	txnRepo    domain.TxnRepository
+	logger     *slog.Logger
+}
+
+func NewPocketUsecase(pr domain.PocketRepository, wr domain.WalletRepository, mbr domain.WalletMemberRepository, tr domain.TxnRepository, logger *slog.Logger) PocketUsecase {
+	return &pocketUsecase{
+		pocketRepo: pr,
+		walletRepo: wr,
+		memberRepo: mbr,
+		txnRepo:    tr,
+		logger:     logger,
+	}
+}
+
+func (u *pocketUsecase) Create(ctx context.Context, walletID string, input PocketInput) (*domain.Pocket, error) {
+	pocket := &domain.Pocket{
+		ID:           uuid.New().String(),
+		WalletID:     walletID,
+		Name:         input.Name,
+		TargetAmount: input.TargetAmount,
+		TargetDate:   input.TargetDate,
+		Status:       domain.PocketOpen,
+	}
+	if err := pocket.Validate(); err != nil {
+		return nil, err
+	}
+	if pocket.TargetDate != nil {
+		date := startOfDay(*pocket.TargetDate)
+		if !date.After(startOfDay(time.Now())) {
+			return nil, fmt.Errorf("%w: target date must be in the future", domain.ErrInvalidPocket)
+		}
+		pocket.TargetDate = &date
+	}
+
+	if _, err := u.walletRepo.FindByID(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, walletID, (*domain.WalletMember).CanSpend, domain.ErrSpendNotAllowed); err != nil {
+		return nil, err
+	}
+	if err := u.pocketRepo.Save(ctx, pocket); err != nil {
+		return nil, err
+	}
+	return pocket, nil
+}
+
+func (u *pocketUsecase) Get(ctx context.Context, pocketID string) (*domain.PocketProgress, error) {
+	pocket, err := u.pocketRepo.FindByID(ctx, pocketID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, pocket.WalletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	progress := pocket.Progress(time.Now().UTC())
+	return &progress, nil
+}
+
+func (u *pocketUsecase) List(ctx context.Context, walletID string) ([]domain.PocketProgress, error) {
+	if _, err := u.walletRepo.FindByID(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, walletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	pockets, err := u.pocketRepo.ListByWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	progress := make([]domain.PocketProgress, len(pockets))
+	for i := range pockets {
+		progress[i] = pockets[i].Progress(now)
+	}
+	return progress, nil
+}
+
+func (u *pocketUsecase) ListMovements(ctx context.Context, pocketID string) ([]domain.PocketMovement, error) {
+	pocket, err := u.pocketRepo.FindByID(ctx, pocketID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, pocket.WalletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	return u.pocketRepo.ListMovements(ctx, pocketID)
+}
+
+func (u *pocketUsecase) Deposit(ctx context.Context, pocketID string, amount float64) (*domain.PocketProgress, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", domain.ErrInvalidPocket)
+	}
+	return u.move(ctx, pocketID, func(wallet *domain.Wallet, pocket *domain.Pocket) (float64, error) {
+		if wallet.MainBalance() < amount {
+			return 0, domain.ErrInsufficientFunds
+		}
+		return amount, nil
+	})
+}
+
+func (u *pocketUsecase) Withdraw(ctx context.Context, pocketID string, amount float64) (*domain.PocketProgress, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", domain.ErrInvalidPocket)
+	}
+	return u.move(ctx, pocketID, func(wallet *domain.Wallet, pocket *domain.Pocket) (float64, error) {
+		if pocket.Balance < amount && !sameAmount(pocket.Balance, amount) {
+			return 0, domain.ErrInsufficientFunds
+		}
+		return -amount, nil
+	})
+}
+
+func (u *pocketUsecase) Close(ctx context.Context, pocketID string) error {
+	_, err := u.move(ctx, pocketID, func(wallet *domain.Wallet, pocket *domain.Pocket) (float64, error) {
+		pocket.Status = domain.PocketClosed
+		return -pocket.Balance, nil
+	})
+	return err
+}
+
+// move applies to a pocket and its wallet the amount (positive into the
+// pocket) that change returns, once both are loaded in a transaction.
+// Updating the wallet serializes concurrent moves on its pockets.
+func (u *pocketUsecase) move(ctx context.Context, pocketID string, change func(*domain.Wallet, *domain.Pocket) (float64, error)) (*domain.PocketProgress, error) {
+	var pocket *domain.Pocket
+	err := withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+		var err error
+		pocket, err = u.pocketRepo.FindByID(txCtx, pocketID)
+		if err != nil {
+			return err
+		}
+		if pocket.Status == domain.PocketClosed {
+			return domain.ErrPocketClosed
+		}
+		wallet, err := u.walletRepo.FindByID(txCtx, pocket.WalletID)
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			return fmt.Errorf("wallet %s of pocket %s not found", pocket.WalletID, pocketID)
+		}
+		if err != nil {
+			return err
+		}
+		if wallet.IsFrozen() {
+			return domain.ErrWalletFrozen
+		}
+		if _, err := authorizeMember(txCtx, u.memberRepo, wallet.ID, (*domain.WalletMember).CanSpend, domain.ErrSpendNotAllowed); err != nil {
+			return err
+		}
+
+		amount, err := change(wallet, pocket)
+		if err != nil {
+			return err
+		}
+		pocket.Balance += amount
+		wallet.Pocketed += amount
+		u.logger.InfoContext(txCtx, "moving pocket funds",
+			"pocket_id", pocketID,
+			"wallet_id", wallet.ID,
+			"amount", amount,
+			"status", pocket.Status,
+		)
+
+		if err := u.walletRepo.Update(txCtx, wallet); err != nil {
+			return err
+		}
+		if err := u.pocketRepo.Update(txCtx, pocket); err != nil {
+			return err
+		}
+		if amount == 0 {
+			return nil
+		}
+		return u.pocketRepo.SaveMovement(txCtx, &domain.PocketMovement{
+			ID:           uuid.New().String(),
+			PocketID:     pocketID,
+			WalletID:     wallet.ID,
+			Amount:       amount,
+			BalanceAfter: pocket.Balance,
+			Actor:        domain.ActorFromContext(txCtx),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	progress := pocket.Progress(time.Now().UTC())
+	return &progress, nil
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+)
+
+// memPocketRepo keeps pockets and their movements in memory.
+type memPocketRepo struct {
+	pockets   map[string]domain.Pocket
+	movements []domain.PocketMovement
+}
+
+func (r *memPocketRepo) Save(ctx context.Context, pocket *domain.Pocket) error {
+	for _, p := range r.pockets {
+		if p.WalletID == pocket.WalletID && p.Name == pocket.Name && p.Status == domain.PocketOpen {
+			return domain.ErrPocketNameTaken
+		}
+	}
+	r.pockets[pocket.ID] = *pocket
+	return nil
+}
+
+func (r *memPocketRepo) FindByID(ctx context.Context, id string) (*domain.Pocket, error) {
+	pocket, ok := r.pockets[id]
+	if !ok {
+		return nil, domain.ErrPocketNotFound
+	}
+	return &pocket, nil
+}
+
+func (r *memPocketRepo) ListByWallet(ctx context.Context, walletID string) ([]domain.Pocket, error) {
+	var pockets []domain.Pocket
+	for _, p := range r.pockets {
+		if p.WalletID == walletID && p.Status == domain.PocketOpen {
+			pockets = append(pockets, p)
+		}
+	}
+	return pockets, nil
+}
+
+func (r *memPocketRepo) Update(ctx context.Context, pocket *domain.Pocket) error {
+	r.pockets[pocket.ID] = *pocket
+	return nil
+}
+
+func (r *memPocketRepo) SaveMovement(ctx context.Context, movement *domain.PocketMovement) error {
+	r.movements = append(r.movements, *movement)
+	return nil
+}
+
+func (r *memPocketRepo) ListMovements(ctx context.Context, pocketID string) ([]domain.PocketMovement, error) {
+	var movements []domain.PocketMovement
+	for _, m := range r.movements {
+		if m.PocketID == pocketID {
+			movements = append(movements, m)
+		}
+	}
+	return movements, nil
+}
+
+// newPocketFixture returns Alice's wallet holding balance, with a pocket
+// saving towards 500.
+func newPocketFixture(t *testing.T, balance float64) (PocketUsecase, *memWalletRepo, *memPocketRepo, string) {
+	t.Helper()
+	wallets := newMemWalletRepo(domain.Wallet{ID: "w-alice", UserID: "alice", Currency: "USD", Balance: balance, Status: domain.WalletActive})
+	pockets := &memPocketRepo{pockets: make(map[string]domain.Pocket)}
+	members := (&memMemberRepo{}).owner("w-alice", "alice")
+	usecase := NewPocketUsecase(pockets, wallets, members, fakeTxnRepo{}, discardLogger)
+
+	ctx := domain.WithActor(context.Background(), domain.UserActor("alice"))
+	pocket, err := usecase.Create(ctx, "w-alice", PocketInput{Name: "Holidays", TargetAmount: 500})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return usecase, wallets, pockets, pocket.ID
+}
+
+// Money moved in and out of a pocket stays in the wallet's balance, and
+// closing the pocket sweeps what is left back to the main balance.
+func TestPocketMovesAndCloseSweep(t *testing.T) {
+	pockets, wallets, repo, pocketID := newPocketFixture(t, 300)
+	ctx := domain.WithActor(context.Background(), domain.UserActor("alice"))
+
+	progress, err := pockets.Deposit(ctx, pocketID, 200)
+	if err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	if progress.Balance != 200 || progress.Remaining != 300 || *progress.Percent != 40 {
+		t.Errorf("progress = %v saved, %v remaining, %v%%; want 200, 300 and 40%%", progress.Balance, progress.Remaining, *progress.Percent)
+	}
+	if _, err := pockets.Deposit(ctx, pocketID, 150); !errors.Is(err, domain.ErrInsufficientFunds) {
+		t.Errorf("Deposit of 150 with 100 outside pockets = %v, want ErrInsufficientFunds", err)
+	}
+	if _, err := pockets.Withdraw(ctx, pocketID, 50); err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+	if w := wallets.get("w-alice"); w.Balance != 300 || w.Pocketed != 150 {
+		t.Errorf("wallet balance = %v with %v pocketed, want 300 with 150", w.Balance, w.Pocketed)
+	}
+
+	if err := pockets.Close(ctx, pocketID); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if w := wallets.get("w-alice"); w.Balance != 300 || w.Pocketed != 0 {
+		t.Errorf("wallet balance = %v with %v pocketed after closing, want 300 with 0", w.Balance, w.Pocketed)
+	}
+	if p := repo.pockets[pocketID]; p.Status != domain.PocketClosed || p.Balance != 0 {
+		t.Errorf("pocket %s with %v, want closed and empty", p.Status, p.Balance)
+	}
+	if _, err := pockets.Deposit(ctx, pocketID, 10); !errors.Is(err, domain.ErrPocketClosed) {
+		t.Errorf("Deposit into a closed pocket = %v, want ErrPocketClosed", err)
+	}
+
+	var amounts, balances []float64
+	for _, m := range repo.movements {
+		amounts, balances = append(amounts, m.Amount), append(balances, m.BalanceAfter)
+	}
+	if want := []float64{200, -50, -150}; !slices.Equal(amounts, want) {
+		t.Errorf("movements = %v, want %v", amounts, want)
+	}
+	if want := []float64{200, 150, 0}; !slices.Equal(balances, want) {
+		t.Errorf("balances after the movements = %v, want %v", balances, want)
+	}
+}
+
+// Only those who can spend from the wallet move its pocketed money.
+func TestPocketMovesNeedASpender(t *testing.T) {
+	pockets, wallets, _, pocketID := newPocketFixture(t, 300)
+
+	for _, actor := range []string{domain.UserActor("bob"), domain.AnonymousActor} {
+		ctx := domain.WithActor(context.Background(), actor)
+		if _, err := pockets.Deposit(ctx, pocketID, 10); !errors.Is(err, domain.ErrSpendNotAllowed) {
+			t.Errorf("Deposit as %s = %v, want ErrSpendNotAllowed", actor, err)
+		}
+		if _, err := pockets.Get(ctx, pocketID); !errors.Is(err, domain.ErrNotWalletMember) {
+			t.Errorf("Get as %s = %v, want ErrNotWalletMember", actor, err)
+		}
+	}
+	if w := wallets.get("w-alice"); w.Pocketed != 0 {
+		t.Errorf("pocketed = %v after refused deposits, want 0", w.Pocketed)
+	}
+}
+
+// Progress tells how much is left to save and how fast.
+func TestPocketProgress(t *testing.T) {
+	now := time.Date(2026, 1, 1, 15, 0, 0, 0, time.UTC)
+	inDays := func(days int) *time.Time {
+		date := time.Date(2026, 1, 1+days, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+
+	tests := []struct {
+		name          string
+		pocket        domain.Pocket
+		wantPercent   float64
+		wantRemaining float64
+		wantReached   bool
+		wantMonthly   float64 // 0 when not reported
+	}{
+		{"halfway", domain.Pocket{Balance: 50, TargetAmount: 100}, 50, 50, false, 0},
+		{"over the target", domain.Pocket{Balance: 120, TargetAmount: 100}, 100, 0, true, 0},
+		{"two months left", domain.Pocket{Balance: 100, TargetAmount: 400, TargetDate: inDays(45)}, 25, 300, false, 150},
+		{"date passed", domain.Pocket{Balance: 100, TargetAmount: 400, TargetDate: inDays(-1)}, 25, 300, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.pocket.Progress(now)
+			if *got.Percent != tt.wantPercent || got.Remaining != tt.wantRemaining || got.Reached != tt.wantReached {
+				t.Errorf("progress = %v%%, %v remaining, reached %v; want %v%%, %v, %v", *got.Percent, got.Remaining, got.Reached, tt.wantPercent, tt.wantRemaining, tt.wantReached)
+			}
+			var monthly float64
+			if got.MonthlyNeeded != nil {
+				monthly = *got.MonthlyNeeded
+			}
+			if monthly != tt.wantMonthly {
+				t.Errorf("monthly needed = %v, want %v", monthly, tt.wantMonthly)
+			}
+		})
+	}
+
+	if got := (&domain.Pocket{Balance: 10}).Progress(now); got.Percent != nil || got.DaysLeft != nil {
+		t.Error("a pocket without a goal reports progress towards one")
+	}
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// RecipientUsecase finds the wallet a transfer goes to from what the sender
+// knows about the recipient.
+type RecipientUsecase interface {
+	// Resolve returns the wallet to, in the currency of the sender's wallet,
+	// belongs to. to is a wallet ID, a phone number (starting with +), an
+	// email address or a username (optionally starting with @).
+	Resolve(ctx context.Context, fromWalletID, to string) (*domain.Recipient, error)
+}
+
+type recipientUsecase struct {
+	userRepo   domain.UserRepository
+	walletRepo domain.WalletRepository
+	aliasRepo  domain.AliasRepository
+}
+
+func NewRecipientUsecase(ur domain.UserRepository, wr domain.WalletRepository, ar domain.AliasRepository) RecipientUsecase {
+	return &recipientUsecase{userRepo: ur, walletRepo: wr, aliasRepo: ar}
+}
+
+func (u *recipientUsecase) Resolve(ctx context.Context, fromWalletID, to string) (*domain.Recipient, error) {
+	to = strings.TrimSpace(to)
+	if to == "" {
+		return nil, fmt.Errorf("%w: no recipient given", domain.ErrRecipientNotFound)
+	}
+	sender, err := u.walletRepo.FindByID(ctx, fromWalletID)
+	if errors.Is(err, domain.ErrWalletNotFound) {
+		return nil, errors.New("sender wallet not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var wallet *domain.Wallet
+	var matchedBy string
+	if _, parseErr := uuid.Parse(to); parseErr == nil {
+		matchedBy = "wallet_id"
+		wallet, err = u.walletRepo.FindByID(ctx, to)
+		if err == nil && wallet.Currency != sender.Currency {
+			return nil, fmt.Errorf("%w: wallet holds %s, not %s", domain.ErrRecipientNotFound, wallet.Currency, sender.Currency)
+		}
+	} else {
+		var userID string
+		userID, matchedBy, err = u.findUser(ctx, to)
+		if err != nil {
+			return nil, err
+		}
+		wallet, err = u.walletRepo.FindByUserIDAndCurrency(ctx, userID, sender.Currency)
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			return nil, fmt.Errorf("%w: recipient has no %s wallet", domain.ErrRecipientNotFound, sender.Currency)
+		}
+	}
+	if errors.Is(err, domain.ErrWalletNotFound) {
+		return nil, domain.ErrRecipientNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.FindByID(ctx, wallet.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Recipient{
+		WalletID:   wallet.ID,
+		Currency:   wallet.Currency,
+		MaskedName: domain.MaskName(user.Name),
+		MatchedBy:  matchedBy,
+	}, nil
+}
+
+// findUser returns the ID of the user to identifies, and how it matched.
+func (u *recipientUsecase) findUser(ctx context.Context, to string) (string, string, error) {
+	var kind domain.AliasKind
+	switch {
+	case strings.HasPrefix(to, "+"):
+		kind = domain.AliasPhone
+	case strings.Contains(strings.TrimPrefix(to, "@"), "@"):
+		kind = domain.AliasEmail
+	default:
+		user, err := u.userRepo.FindByUsername(ctx, strings.TrimPrefix(to, "@"))
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return "", "", domain.ErrRecipientNotFound
+		}
+		if err != nil {
+			return "", "", err
+		}
+		return user.ID, "username", nil
+	}
+
+	value, err := domain.NormalizeAlias(kind, to)
+	if err != nil {
+		return "", "", err
+	}
+	alias, err := u.aliasRepo.FindByValue(ctx, kind, value)
+	if errors.Is(err, domain.ErrAliasNotFound) {
+		return "", "", domain.ErrRecipientNotFound
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return alias.UserID, string(kind), nil
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ReconciliationReport is the outcome of a reconciliation run.
+type ReconciliationReport struct {
+	Run           *domain.ReconciliationRun          `json:"run"`
+	Discrepancies []domain.ReconciliationDiscrepancy `json:"discrepancies"`
+}
+
+// ReconciliationUsecase checks wallet balances against the ledger.
+type ReconciliationUsecase interface {
+	// Run checks every wallet, and that transfers neither created nor destroyed
+	// money. scheduledFor is the day a scheduled run covers; it is nil for
+	// manual runs. Discrepancies are stored with the run.
+	Run(ctx context.Context, trigger domain.ReconciliationTrigger, scheduledFor *time.Time) (*ReconciliationReport, error)
+}
+
+type reconciliationUsecase struct {
+	reconciliationRepo domain.ReconciliationRepository
+	batchSize          int
+	logger             *slog.Logger
+}
+
+// NewReconciliationUsecase creates a ReconciliationUsecase that reads batchSize
+// wallets per query, so no single query holds a long snapshot of the table.
+func NewReconciliationUsecase(rr domain.ReconciliationRepository, batchSize int, logger *slog.Logger) ReconciliationUsecase {
+	return &reconciliationUsecase{
+		reconciliationRepo: rr,
+		batchSize:          batchSize,
+		logger:             logger,
+	}
+}
+
+func (u *reconciliationUsecase) Run(ctx context.Context, trigger domain.ReconciliationTrigger, scheduledFor *time.Time) (*ReconciliationReport, error) {
+	run := &domain.ReconciliationRun{
+		ID:           uuid.New().String(),
+		Trigger:      trigger,
+		ScheduledFor: scheduledFor,
+		Status:       domain.ReconciliationRunning,
+		Actor:        domain.ActorFromContext(ctx),
+		StartedAt:    time.Now(),
+	}
+	if err := u.reconciliationRepo.StartRun(ctx, run); err != nil {
+		return nil, err
+	}
+	u.logger.InfoContext(ctx, "reconciliation started", "run_id", run.ID, "trigger", trigger)
+
+	report := &ReconciliationReport{Run: run}
+	runErr := u.check(ctx, report)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Discrepancies = len(report.Discrepancies)
+	run.Status = domain.ReconciliationCompleted
+	if runErr != nil {
+		run.Status = domain.ReconciliationFailed
+		run.Error = runErr.Error()
+	}
+	// Record the outcome even if the caller gave up on the run.
+	if err := u.reconciliationRepo.FinishRun(context.WithoutCancel(ctx), run); err != nil {
+		return report, errors.Join(runErr, err)
+	}
+
+	u.logger.InfoContext(ctx, "reconciliation finished",
+		"run_id", run.ID,
+		"status", run.Status,
+		"wallets_checked", run.WalletsChecked,
+		"discrepancies", run.Discrepancies,
+		"duration", finishedAt.Sub(run.StartedAt),
+	)
+	return report, runErr
+}
+
+func (u *reconciliationUsecase) check(ctx context.Context, report *ReconciliationReport) error {
+	run := report.Run
+
+	afterID := ""
+	for {
+		balances, err := u.reconciliationRepo.LedgerBalances(ctx, afterID, u.batchSize)
+		if err != nil {
+			return err
+		}
+
+		var found []domain.ReconciliationDiscrepancy
+		for _, b := range balances {
+			if !sameAmount(b.Balance, b.LedgerBalance) {
+				walletID := b.WalletID
+				found = append(found, newDiscrepancy(run, domain.DiscrepancyWalletBalance, &walletID, b.Currency, b.LedgerBalance, b.Balance))
+			}
+		}
+		if err := u.save(ctx, report, found); err != nil {
+			return err
+		}
+
+		run.WalletsChecked += len(balances)
+		if len(balances) < u.batchSize {
+			break
+		}
+		afterID = balances[len(balances)-1].WalletID
+	}
+
+	totals, err := u.reconciliationRepo.CurrencyTotals(ctx)
+	if err != nil {
+		return err
+	}
+	var found []domain.ReconciliationDiscrepancy
+	for _, t := range totals {
+		if !sameAmount(t.Balance, t.Inflows) {
+			found = append(found, newDiscrepancy(run, domain.DiscrepancyCurrencyTotal, nil, t.Currency, t.Inflows, t.Balance))
+		}
+	}
+	return u.save(ctx, report, found)
+}
+
+func (u *reconciliationUsecase) save(ctx context.Context, report *ReconciliationReport, found []domain.ReconciliationDiscrepancy) error {
+	if err := u.reconciliationRepo.SaveDiscrepancies(ctx, found); err != nil {
+		return err
+	}
+	for _, d := range found {
+		u.logger.WarnContext(ctx, "reconciliation discrepancy",
+			"run_id", d.RunID,
+			"kind", d.Kind,
+			"wallet_id", d.WalletID,
+			"currency", d.Currency,
+			"expected", d.Expected,
+			"actual", d.Actual,
+		)
+	}
+	report.Discrepancies = append(report.Discrepancies, found...)
+	return nil
+}
+
+func newDiscrepancy(run *domain.ReconciliationRun, kind domain.DiscrepancyKind, walletID *string, currency string, expected, actual float64) domain.ReconciliationDiscrepancy {
+	return domain.ReconciliationDiscrepancy{
+		ID:       uuid.New().String(),
+		RunID:    run.ID,
+		Kind:     kind,
+		WalletID: walletID,
+		Currency: currency,
+		Expected: expected,
+		Actual:   actual,
+	}
+}
+
+// sameAmount compares amounts to the cent, ignoring floating point noise.
+func sameAmount(a, b float64) bool {
+	return math.Round((a-b)*100) == 0
+}
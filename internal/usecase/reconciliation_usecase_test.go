@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"wallet/internal/domain"
+)
+
+// memReconciliationRepo serves fixed balances and keeps what the run stores.
+type memReconciliationRepo struct {
+	balances []domain.WalletLedgerBalance // Ordered by wallet ID
+	totals   []domain.CurrencyTotal
+	// failAfter makes LedgerBalances fail when asked for the page after it.
+	failAfter string
+
+	pages         []string
+	finished      *domain.ReconciliationRun
+	discrepancies []domain.ReconciliationDiscrepancy
+}
+
+func (r *memReconciliationRepo) StartRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	return nil
+}
+
+func (r *memReconciliationRepo) FinishRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	finished := *run
+	r.finished = &finished
+	return nil
+}
+
+func (r *memReconciliationRepo) SaveDiscrepancies(ctx context.Context, discrepancies []domain.ReconciliationDiscrepancy) error {
+	r.discrepancies = append(r.discrepancies, discrepancies...)
+	return nil
+}
+
+func (r *memReconciliationRepo) LedgerBalances(ctx context.Context, afterWalletID string, limit int) ([]domain.WalletLedgerBalance, error) {
+	r.pages = append(r.pages, afterWalletID)
+	if r.failAfter != "" && afterWalletID == r.failAfter {
+		return nil, errors.New("connection reset")
+	}
+	var page []domain.WalletLedgerBalance
+	for _, b := range r.balances {
+		if b.WalletID > afterWalletID && len(page) < limit {
+			page = append(page, b)
+		}
+	}
+	return page, nil
+}
+
+func (r *memReconciliationRepo) CurrencyTotals(ctx context.Context) ([]domain.CurrencyTotal, error) {
+	return r.totals, nil
+}
+
+func TestReconciliationFindsMismatches(t *testing.T) {
+	repo := &memReconciliationRepo{
+		balances: []domain.WalletLedgerBalance{
+			{WalletID: "w1", Currency: "USD", Balance: 100, LedgerBalance: 100},
+			// Floating point noise below a cent is no discrepancy.
+			{WalletID: "w2", Currency: "USD", Balance: 0.1 + 0.2, LedgerBalance: 0.3},
+			{WalletID: "w3", Currency: "USD", Balance: 80, LedgerBalance: 75.5},
+			{WalletID: "w4", Currency: "EUR", Balance: 10, LedgerBalance: 10},
+			{WalletID: "w5", Currency: "EUR", Balance: 0, LedgerBalance: 20},
+		},
+		totals: []domain.CurrencyTotal{
+			{Currency: "USD", Balance: 180.3, Inflows: 175.8},
+			{Currency: "EUR", Balance: 10, Inflows: 10},
+		},
+	}
+	reconciliation := NewReconciliationUsecase(repo, 2, discardLogger)
+
+	report, err := reconciliation.Run(context.Background(), domain.ReconciliationManual, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if want := []string{"", "w2", "w4"}; !slices.Equal(repo.pages, want) {
+		t.Errorf("pages read after %q, want %q", repo.pages, want)
+	}
+	type found struct {
+		kind     domain.DiscrepancyKind
+		walletID string
+		currency string
+		diff     float64
+	}
+	var got []found
+	for _, d := range report.Discrepancies {
+		f := found{kind: d.Kind, currency: d.Currency, diff: d.Difference()}
+		if d.WalletID != nil {
+			f.walletID = *d.WalletID
+		}
+		got = append(got, f)
+	}
+	want := []found{
+		{domain.DiscrepancyWalletBalance, "w3", "USD", 4.5},
+		{domain.DiscrepancyWalletBalance, "w5", "EUR", -20},
+		{domain.DiscrepancyCurrencyTotal, "", "USD", 4.5},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("discrepancies = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].kind != want[i].kind || got[i].walletID != want[i].walletID || got[i].currency != want[i].currency || !sameAmount(got[i].diff, want[i].diff) {
+			t.Errorf("discrepancy %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if len(repo.discrepancies) != len(want) {
+		t.Errorf("stored %d discrepancies, want %d", len(repo.discrepancies), len(want))
+	}
+
+	run := repo.finished
+	if run == nil {
+		t.Fatal("the run was not finished")
+	}
+	if run.Status != domain.ReconciliationCompleted || run.WalletsChecked != 5 || run.Discrepancies != 3 {
+		t.Errorf("finished run = %s with %d wallets and %d discrepancies, want completed with 5 and 3", run.Status, run.WalletsChecked, run.Discrepancies)
+	}
+}
+
+// A run that breaks off half-way is recorded as failed, keeping what it found.
+func TestReconciliationRecordsAFailedRun(t *testing.T) {
+	repo := &memReconciliationRepo{
+		balances: []domain.WalletLedgerBalance{
+			{WalletID: "w1", Currency: "USD", Balance: 5, LedgerBalance: 0},
+			{WalletID: "w2", Currency: "USD", Balance: 0, LedgerBalance: 0},
+			{WalletID: "w3", Currency: "USD", Balance: 0, LedgerBalance: 0},
+		},
+		failAfter: "w2",
+	}
+	reconciliation := NewReconciliationUsecase(repo, 2, discardLogger)
+
+	report, err := reconciliation.Run(context.Background(), domain.ReconciliationManual, nil)
+	if err == nil {
+		t.Fatal("Run succeeded, want the read error")
+	}
+	run := repo.finished
+	if run == nil || run.Status != domain.ReconciliationFailed || run.Error == "" {
+		t.Fatalf("finished run = %+v, want it failed with the error", run)
+	}
+	if run.WalletsChecked != 2 || len(report.Discrepancies) != 1 {
+		t.Errorf("run checked %d wallets and found %d discrepancies, want 2 and 1", run.WalletsChecked, len(report.Discrepancies))
+	}
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/robfig/cron/v3"
+)
+
+// nextRun returns the first run of schedule strictly after after, and false
+// when there is none left.
+func nextRun(schedule *domain.ScheduledTransfer, after time.Time) (time.Time, bool, error) {
+	start := schedule.StartAt.UTC()
+	after = after.UTC()
+
+	var next time.Time
+	switch schedule.Recurrence {
+	case domain.RecurrenceOnce:
+		next = start
+	case domain.RecurrenceDaily:
+		next = everyNDays(start, after, 1)
+	case domain.RecurrenceWeekly:
+		next = everyNDays(start, after, 7)
+	case domain.RecurrenceMonthly:
+		next = monthly(start, after, schedule.DayOfMonth)
+	case domain.RecurrenceCron:
+		spec, err := cron.ParseStandard(schedule.CronExpr)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: invalid cron expression: %v", domain.ErrInvalidSchedule, err)
+		}
+		from := after
+		if start.After(after) {
+			// Next is strictly after its argument; start itself may match.
+			from = start.Add(-time.Nanosecond)
+		}
+		next = spec.Next(from)
+	default:
+		return time.Time{}, false, fmt.Errorf("%w: unknown recurrence %q", domain.ErrInvalidSchedule, schedule.Recurrence)
+	}
+
+	if next.IsZero() || !next.After(after) {
+		return time.Time{}, false, nil
+	}
+	if schedule.EndAt != nil && next.After(*schedule.EndAt) {
+		return time.Time{}, false, nil
+	}
+	return next, true, nil
+}
+
+// everyNDays returns the first of start, start + n days, start + 2n days...
+// that is after after.
+func everyNDays(start, after time.Time, n int) time.Time {
+	if start.After(after) {
+		return start
+	}
+	periods := int(after.Sub(start)/(time.Duration(n)*24*time.Hour)) + 1
+	next := start.AddDate(0, 0, periods*n)
+	for !next.After(after) {
+		next = next.AddDate(0, 0, n)
+	}
+	return next
+}
+
+// monthly returns the first day-th of a month, at the time of day of start,
+// that is not before start and is after after. Months without that day use
+// their last day.
+func monthly(start, after time.Time, day int) time.Time {
+	from := start
+	if after.After(from) {
+		from = after
+	}
+	for i := 0; ; i++ {
+		next := monthDay(from.Year(), from.Month()+time.Month(i), day, start)
+		if !next.Before(start) && next.After(after) {
+			return next
+		}
+	}
+}
+
+func monthDay(year int, month time.Month, day int, clock time.Time) time.Time {
+	first := time.Date(year, month, 1, clock.Hour(), clock.Minute(), clock.Second(), 0, time.UTC)
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+)
+
+func TestNextRun(t *testing.T) {
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	// 2026-01-05 is a Monday.
+	start := at(time.January, 5, 9)
+	end := at(time.January, 20, 0)
+	buenosAires := time.FixedZone("ART", -3*60*60)
+
+	tests := []struct {
+		name     string
+		schedule domain.ScheduledTransfer
+		after    time.Time
+		want     time.Time // Zero when no run is left
+	}{
+		{"once, not yet run", domain.ScheduledTransfer{Recurrence: domain.RecurrenceOnce, StartAt: start}, start.Add(-time.Hour), start},
+		{"once, already run", domain.ScheduledTransfer{Recurrence: domain.RecurrenceOnce, StartAt: start}, start, time.Time{}},
+
+		{"daily, first run", domain.ScheduledTransfer{Recurrence: domain.RecurrenceDaily, StartAt: start}, start.Add(-time.Hour), start},
+		{"daily, right after a run", domain.ScheduledTransfer{Recurrence: domain.RecurrenceDaily, StartAt: start}, start, at(time.January, 6, 9)},
+		{"daily, days later", domain.ScheduledTransfer{Recurrence: domain.RecurrenceDaily, StartAt: start}, at(time.January, 9, 12), at(time.January, 10, 9)},
+		{"daily, start in another zone", domain.ScheduledTransfer{Recurrence: domain.RecurrenceDaily, StartAt: start.In(buenosAires)}, start.In(buenosAires), at(time.January, 6, 9)},
+		{"daily, past the end", domain.ScheduledTransfer{Recurrence: domain.RecurrenceDaily, StartAt: start, EndAt: &end}, at(time.January, 19, 9), time.Time{}},
+
+		{"weekly", domain.ScheduledTransfer{Recurrence: domain.RecurrenceWeekly, StartAt: start}, at(time.January, 6, 0), at(time.January, 12, 9)},
+		{"weekly, on the day after the time", domain.ScheduledTransfer{Recurrence: domain.RecurrenceWeekly, StartAt: start}, at(time.January, 12, 10), at(time.January, 19, 9)},
+
+		{"monthly, day not before the start", domain.ScheduledTransfer{Recurrence: domain.RecurrenceMonthly, DayOfMonth: 1, StartAt: start}, start.Add(-time.Hour), at(time.February, 1, 9)},
+		{"monthly, later this month", domain.ScheduledTransfer{Recurrence: domain.RecurrenceMonthly, DayOfMonth: 15, StartAt: start}, start, at(time.January, 15, 9)},
+		{"monthly, last day of a short month", domain.ScheduledTransfer{Recurrence: domain.RecurrenceMonthly, DayOfMonth: 31, StartAt: start}, at(time.January, 31, 9), at(time.February, 28, 9)},
+		{"monthly, back to the day after a short month", domain.ScheduledTransfer{Recurrence: domain.RecurrenceMonthly, DayOfMonth: 31, StartAt: start}, at(time.February, 28, 9), at(time.March, 31, 9)},
+
+		{"cron, start matches", domain.ScheduledTransfer{Recurrence: domain.RecurrenceCron, CronExpr: "0 9 * * 1", StartAt: start}, start.AddDate(0, 0, -1), start},
+		{"cron, next match", domain.ScheduledTransfer{Recurrence: domain.RecurrenceCron, CronExpr: "0 9 * * 1", StartAt: start}, start, at(time.January, 12, 9)},
+		{"cron, past the end", domain.ScheduledTransfer{Recurrence: domain.RecurrenceCron, CronExpr: "0 9 * * 1", StartAt: start, EndAt: &end}, at(time.January, 19, 9), time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := nextRun(&tt.schedule, tt.after)
+			if err != nil {
+				t.Fatalf("nextRun: %v", err)
+			}
+			if ok != !tt.want.IsZero() || !got.Equal(tt.want) {
+				t.Errorf("nextRun(after %s) = %s, %v; want %s", tt.after, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextRunRejectsInvalidSchedules(t *testing.T) {
+	for _, schedule := range []domain.ScheduledTransfer{
+		{Recurrence: domain.RecurrenceCron, CronExpr: "every monday"},
+		{Recurrence: domain.RecurrenceCron, CronExpr: "0 25 * * *"},
+		{Recurrence: "fortnightly"},
+	} {
+		if _, _, err := nextRun(&schedule, time.Now()); !errors.Is(err, domain.ErrInvalidSchedule) {
+			t.Errorf("nextRun(%s %q) = %v, want ErrInvalidSchedule", schedule.Recurrence, schedule.CronExpr, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+	"wallet/internal/domain"
+)
+
+// Transactions that lose a race are retried from scratch a few times, waiting
+// a bit longer (with jitter) before each new attempt.
+const (
+	maxTxAttempts  = 4
+	baseTxBackoff  = 10 * time.Millisecond
+	maxTxBackoffMs = 200
+)
+
+// isRetryable reports whether err is a concurrency conflict that a fresh
+// attempt of the whole transaction may not hit again.
+func isRetryable(err error) bool {
+	return errors.Is(err, domain.ErrConcurrentModification) || errors.Is(err, domain.ErrTransactionConflict)
+}
+
+// withTxRetry runs fn in a transaction and retries it on concurrency
+// conflicts. Inside an enclosing transaction fn runs once: only the outermost
+// transaction can be retried, since the conflict aborted all of it.
+func withTxRetry(ctx context.Context, txnRepo domain.TxnRepository, fn func(ctx context.Context) error) error {
+	if domain.InTransaction(ctx) {
+		return txnRepo.WithTransaction(ctx, fn)
+	}
+
+	var err error
+	for attempt := 0; attempt < maxTxAttempts; attempt++ {
+		if attempt > 0 {
+			if waitErr := sleepCtx(ctx, backoff(attempt)); waitErr != nil {
+				return err
+			}
+		}
+		err = txnRepo.WithTransaction(ctx, fn)
+		if !isRetryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func backoff(attempt int) time.Duration {
+	d := baseTxBackoff << (attempt - 1)
+	if limit := maxTxBackoffMs * time.Millisecond; d > limit {
+		d = limit
+	}
+	// Full jitter keeps conflicting requests from retrying in lockstep.
+	return time.Duration(rand.Int64N(int64(d)) + 1)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
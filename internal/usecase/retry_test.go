@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"wallet/internal/domain"
+)
+
+func TestWithTxRetry(t *testing.T) {
+	errOther := errors.New("disk full")
+	conflict := fmt.Errorf("%w: could not serialize access", domain.ErrTransactionConflict)
+
+	tests := []struct {
+		name         string
+		nested       bool
+		errs         []error // returned by the attempts in turn; nil once exhausted
+		want         error
+		wantAttempts int
+	}{
+		{"succeeds at once", false, nil, nil, 1},
+		{"retries a version conflict", false, []error{domain.ErrConcurrentModification}, nil, 2},
+		{"retries a serialization failure", false, []error{conflict, conflict}, nil, 3},
+		{"gives up after the last attempt", false, []error{domain.ErrConcurrentModification, conflict, domain.ErrConcurrentModification, domain.ErrConcurrentModification}, domain.ErrConcurrentModification, maxTxAttempts},
+		{"does not retry other errors", false, []error{errOther}, errOther, 1},
+		{"runs once inside a transaction", true, []error{domain.ErrConcurrentModification}, domain.ErrConcurrentModification, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.nested {
+				ctx, _ = domain.NewTransactionContext(ctx)
+			}
+			attempts := 0
+			err := withTxRetry(ctx, fakeTxnRepo{}, func(context.Context) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+				t.Errorf("withTxRetry() = %v, want %v", err, tt.want)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+// racingWalletRepo lets another writer update a wallet between the first read
+// of it and the write that follows.
+type racingWalletRepo struct {
+	*memWalletRepo
+	race func()
+}
+
+func (r *racingWalletRepo) FindByID(ctx context.Context, id string) (*domain.Wallet, error) {
+	wallet, err := r.memWalletRepo.FindByID(ctx, id)
+	if r.race != nil {
+		r.race()
+		r.race = nil
+	}
+	return wallet, err
+}
+
+// A recharge losing the race to another write starts over from the new
+// balance instead of overwriting it.
+func TestRechargeRetriesAVersionConflict(t *testing.T) {
+	f := newWalletFixture(100, 0)
+	ctx := domain.WithActor(context.Background(), domain.UserActor("alice"))
+	repo := &racingWalletRepo{memWalletRepo: f.wallets, race: func() {
+		concurrent := f.wallets.get("w-alice")
+		concurrent.Balance -= 30
+		if err := f.wallets.Update(ctx, &concurrent); err != nil {
+			t.Fatalf("concurrent update: %v", err)
+		}
+	}}
+
+	if err := f.usecase(repo, nil).Recharge(ctx, "w-alice", 50); err != nil {
+		t.Fatalf("Recharge: %v", err)
+	}
+	stored := f.wallets.get("w-alice")
+	if stored.Balance != 120 {
+		t.Errorf("balance = %v, want 120 (100 - 30 + 50)", stored.Balance)
+	}
+	if stored.Version != 2 {
+		t.Errorf("version = %d, want 2 after both writes", stored.Version)
+	}
+}
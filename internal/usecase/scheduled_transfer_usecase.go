@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleRequest describes a scheduled transfer to create. Times are UTC.
+type ScheduleRequest struct {
+	FromWalletID string            `json:"from_wallet_id"`
+	ToWalletID   string            `json:"to_wallet_id"`
+	Amount       float64           `json:"amount"`
+	Recurrence   domain.Recurrence `json:"recurrence"`
+	StartAt      time.Time         `json:"start_at"`
+	EndAt        *time.Time        `json:"end_at,omitempty"`
+	DayOfMonth   int               `json:"day_of_month,omitempty"`
+	Cron         string            `json:"cron,omitempty"`
+}
+
+// ScheduleUpdate changes a scheduled transfer; nil fields are left as they are.
+type ScheduleUpdate struct {
+	Amount *float64   `json:"amount,omitempty"`
+	EndAt  *time.Time `json:"end_at,omitempty"`
+	// Status pauses (paused) or resumes (active) the schedule. Runs missed
+	// while paused are skipped.
+	Status *domain.ScheduleStatus `json:"status,omitempty"`
+}
+
+// ScheduledTransferUsecase manages scheduled and recurring transfers. Users
+// must be allowed to spend from the sending wallet to create, update or cancel
+// a schedule, and be members of it to read one.
+type ScheduledTransferUsecase interface {
+	Create(ctx context.Context, req ScheduleRequest) (*domain.ScheduledTransfer, error)
+	Get(ctx context.Context, id string) (*domain.ScheduledTransfer, error)
+	ListByWallet(ctx context.Context, walletID string) ([]domain.ScheduledTransfer, error)
+	Update(ctx context.Context, id string, update ScheduleUpdate) (*domain.ScheduledTransfer, error)
+	Cancel(ctx context.Context, id string) error
+	ListExecutions(ctx context.Context, id string, limit int) ([]domain.ScheduledTransferExecution, error)
+
+	// RunDue runs every transfer that is due and returns how many ran.
+	RunDue(ctx context.Context) (int, error)
+}
+
+type scheduledTransferUsecase struct {
+	scheduleRepo  domain.ScheduledTransferRepository
+	walletRepo    domain.WalletRepository
+	memberRepo    domain.WalletMemberRepository
+	movementRepo  domain.MovementRepository
+	walletUsecase WalletUsecase
+	txnRepo       domain.TxnRepository
+	notifier      domain.Notifier
+	logger        *slog.Logger
+}
+
+func NewScheduledTransferUsecase(sr domain.ScheduledTransferRepository, wr domain.WalletRepository, mbr domain.WalletMemberRepository, mr domain.MovementRepository, wu WalletUsecase, tr domain.TxnRepository, notifier domain.Notifier, logger *slog.Logger) ScheduledTransferUsecase {
+	return &scheduledTransferUsecase{
+		scheduleRepo:  sr,
+		walletRepo:    wr,
+		memberRepo:    mbr,
+		movementRepo:  mr,
+		walletUsecase: wu,
+		txnRepo:       tr,
+		notifier:      notifier,
+		logger:        logger,
+	}
+}
+
+func (u *scheduledTransferUsecase) Create(ctx context.Context, req ScheduleRequest) (*domain.ScheduledTransfer, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", domain.ErrInvalidSchedule)
+	}
+	if req.FromWalletID == req.ToWalletID {
+		return nil, fmt.Errorf("%w: cannot transfer to the same wallet", domain.ErrInvalidSchedule)
+	}
+	if req.Recurrence == domain.RecurrenceMonthly && (req.DayOfMonth < 1 || req.DayOfMonth > 31) {
+		return nil, fmt.Errorf("%w: day_of_month must be between 1 and 31", domain.ErrInvalidSchedule)
+	}
+	if req.Recurrence != domain.RecurrenceMonthly {
+		req.DayOfMonth = 0
+	}
+	if req.Recurrence != domain.RecurrenceCron {
+		req.Cron = ""
+	}
+	for _, id := range []string{req.FromWalletID, req.ToWalletID} {
+		if _, err := u.walletRepo.FindByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	// A spender can't schedule more than their daily limit lets them send.
+	if err := checkSpender(ctx, u.memberRepo, u.movementRepo, req.FromWalletID, req.Amount); err != nil {
+		return nil, err
+	}
+
+	schedule := &domain.ScheduledTransfer{
+		ID:           uuid.New().String(),
+		FromWalletID: req.FromWalletID,
+		ToWalletID:   req.ToWalletID,
+		Amount:       req.Amount,
+		Recurrence:   req.Recurrence,
+		DayOfMonth:   req.DayOfMonth,
+		CronExpr:     req.Cron,
+		StartAt:      req.StartAt.UTC(),
+		EndAt:        req.EndAt,
+		Status:       domain.ScheduleActive,
+		Actor:        domain.ActorFromContext(ctx),
+	}
+	next, ok, err := nextRun(schedule, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: the schedule has no run in the future", domain.ErrInvalidSchedule)
+	}
+	schedule.NextRunAt = &next
+
+	if err := u.scheduleRepo.Save(ctx, schedule); err != nil {
+		return nil, err
+	}
+	u.logger.InfoContext(ctx, "scheduled transfer created",
+		"schedule_id", schedule.ID,
+		"recurrence", schedule.Recurrence,
+		"next_run_at", next,
+	)
+	return schedule, nil
+}
+
+func (u *scheduledTransferUsecase) Get(ctx context.Context, id string) (*domain.ScheduledTransfer, error) {
+	schedule, err := u.scheduleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, schedule.FromWalletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (u *scheduledTransferUsecase) ListByWallet(ctx context.Context, walletID string) ([]domain.ScheduledTransfer, error) {
+	if _, err := u.walletRepo.FindByID(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, walletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	return u.scheduleRepo.ListByWallet(ctx, walletID)
+}
+
+// Update locks the schedule, so it never overlaps with a run of it.
+func (u *scheduledTransferUsecase) Update(ctx context.Context, id string, update ScheduleUpdate) (*domain.ScheduledTransfer, error) {
+	var schedule *domain.ScheduledTransfer
+	err := u.txnRepo.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		schedule, err = u.scheduleRepo.FindByIDForUpdate(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := authorizeMember(txCtx, u.memberRepo, schedule.FromWalletID, (*domain.WalletMember).CanSpend, domain.ErrSpendNotAllowed); err != nil {
+			return err
+		}
+		if schedule.IsOver() {
+			return domain.ErrScheduleOver
+		}
+
+		if update.Amount != nil {
+			if *update.Amount <= 0 {
+				return fmt.Errorf("%w: amount must be positive", domain.ErrInvalidSchedule)
+			}
+			if err := checkSpender(txCtx, u.memberRepo, u.movementRepo, schedule.FromWalletID, *update.Amount); err != nil {
+				return err
+			}
+			schedule.Amount = *update.Amount
+		}
+		if update.EndAt != nil {
+			schedule.EndAt = update.EndAt
+		}
+		if update.Status != nil {
+			if *update.Status != domain.ScheduleActive && *update.Status != domain.SchedulePaused {
+				return fmt.Errorf("%w: status can only be set to %s or %s", domain.ErrInvalidSchedule, domain.ScheduleActive, domain.SchedulePaused)
+			}
+			schedule.Status = *update.Status
+		}
+
+		if schedule.Status == domain.ScheduleActive {
+			// Runs missed while paused are skipped.
+			next, ok, err := nextRun(schedule, time.Now())
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("%w: the schedule would have no run in the future", domain.ErrInvalidSchedule)
+			}
+			schedule.NextRunAt = &next
+		}
+		return u.scheduleRepo.Update(txCtx, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (u *scheduledTransferUsecase) Cancel(ctx context.Context, id string) error {
+	return u.txnRepo.WithTransaction(ctx, func(txCtx context.Context) error {
+		schedule, err := u.scheduleRepo.FindByIDForUpdate(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := authorizeMember(txCtx, u.memberRepo, schedule.FromWalletID, (*domain.WalletMember).CanSpend, domain.ErrSpendNotAllowed); err != nil {
+			return err
+		}
+		if schedule.IsOver() {
+			return domain.ErrScheduleOver
+		}
+		schedule.Status = domain.ScheduleCancelled
+		schedule.NextRunAt = nil
+		return u.scheduleRepo.Update(txCtx, schedule)
+	})
+}
+
+func (u *scheduledTransferUsecase) ListExecutions(ctx context.Context, id string, limit int) ([]domain.ScheduledTransferExecution, error) {
+	if _, err := u.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.scheduleRepo.ListExecutions(ctx, id, limit)
+}
+
+func (u *scheduledTransferUsecase) RunDue(ctx context.Context) (int, error) {
+	executed := 0
+	for ctx.Err() == nil {
+		ran, err := u.runNext(ctx)
+		if err != nil || !ran {
+			return executed, err
+		}
+		executed++
+	}
+	return executed, ctx.Err()
+}
+
+// runNext runs the transfer that has been due the longest. The schedule stays
+// locked from the claim to the commit of its execution record and next run
+// time, so no other instance can run the same occurrence.
+func (u *scheduledTransferUsecase) runNext(ctx context.Context) (bool, error) {
+	var ran bool
+	err := u.txnRepo.WithTransaction(ctx, func(txCtx context.Context) error {
+		now := time.Now()
+		schedule, err := u.scheduleRepo.ClaimDue(txCtx, now)
+		if err != nil || schedule == nil {
+			return err
+		}
+		ran = true
+		occurrence := *schedule.NextRunAt
+
+		// The transfer runs in a savepoint: if it fails, the failure is still
+		// recorded. One held for review commits its review with the execution.
+		transferErr := u.walletUsecase.Transfer(domain.WithActor(txCtx, schedule.Actor),
+			schedule.FromWalletID, schedule.ToWalletID, schedule.Amount)
+		if isRetryable(transferErr) || ctx.Err() != nil {
+			// Try the same occurrence again on the next poll.
+			return transferErr
+		}
+
+		execution := &domain.ScheduledTransferExecution{
+			ID:           uuid.New().String(),
+			ScheduleID:   schedule.ID,
+			ScheduledFor: occurrence,
+			Status:       domain.ExecutionSucceeded,
+			Amount:       schedule.Amount,
+			ExecutedAt:   now,
+		}
+		var held *domain.TransferHeldError
+		switch {
+		case errors.As(transferErr, &held):
+			execution.Status = domain.ExecutionHeld
+			execution.ReviewID = &held.ReviewID
+		case transferErr != nil:
+			execution.Status = domain.ExecutionFailed
+			execution.Error = transferErr.Error()
+		}
+		if err := u.scheduleRepo.SaveExecution(txCtx, execution); err != nil {
+			return err
+		}
+
+		// Occurrences missed while no instance was running are skipped.
+		schedule.LastRunAt = &occurrence
+		schedule.NextRunAt = nil
+		after := occurrence
+		if now.After(after) {
+			after = now
+		}
+		next, ok, err := nextRun(schedule, after)
+		if err != nil {
+			return err
+		}
+		if ok {
+			schedule.NextRunAt = &next
+		} else {
+			schedule.Status = domain.ScheduleCompleted
+		}
+		if err := u.scheduleRepo.Update(txCtx, schedule); err != nil {
+			return err
+		}
+
+		u.logger.InfoContext(txCtx, "scheduled transfer executed",
+			"schedule_id", schedule.ID,
+			"scheduled_for", occurrence,
+			"status", execution.Status,
+			"error", execution.Error,
+		)
+		if execution.Status == domain.ExecutionFailed {
+			domain.AfterCommit(txCtx, func(ctx context.Context) {
+				u.notifyFailure(ctx, schedule, execution)
+			})
+		}
+		return nil
+	})
+	return ran, err
+}
+
+func (u *scheduledTransferUsecase) notifyFailure(ctx context.Context, schedule *domain.ScheduledTransfer, execution *domain.ScheduledTransferExecution) {
+	wallet, err := u.walletRepo.FindByID(ctx, schedule.FromWalletID)
+	if err == nil {
+		err = u.notifier.Notify(ctx, domain.Notification{
+			Kind:    domain.NotificationScheduledTransferFailed,
+			UserID:  wallet.UserID,
+			Message: fmt.Sprintf("Your scheduled transfer of %.2f %s could not be made: %s", schedule.Amount, wallet.Currency, execution.Error),
+			Data: map[string]interface{}{
+				"schedule_id":   schedule.ID,
+				"execution_id":  execution.ID,
+				"scheduled_for": execution.ScheduledFor,
+				"to_wallet_id":  schedule.ToWalletID,
+			},
+		})
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		u.logger.ErrorContext(ctx, "failed to notify scheduled transfer failure", "schedule_id", schedule.ID, "error", err)
+	}
+}
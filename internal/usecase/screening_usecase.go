@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ScreeningUsecase screens users against the sanctions list and lets
+// operators decide on the hits. Users can't see or decide on hits.
+type ScreeningUsecase interface {
+	// Screen matches the names of users against the sanctions list, records
+	// the hits not seen before and returns the status of each user by ID.
+	// Confirmed hits block a user even once the entry leaves the list.
+	Screen(ctx context.Context, source domain.ScreeningSource, users ...*domain.User) (map[string]domain.ScreeningStatus, error)
+
+	// List returns the hits in status, oldest first; all of them when status
+	// is empty.
+	List(ctx context.Context, status domain.ScreeningHitStatus) ([]domain.ScreeningHit, error)
+	Get(ctx context.Context, id string) (*domain.ScreeningHit, error)
+	// Confirm marks a pending hit as a true match, which blocks the user.
+	Confirm(ctx context.Context, id, note string) (*domain.ScreeningHit, error)
+	// Clear marks a pending hit as a false positive. The user is no longer
+	// held back by it, and the same entry won't be raised for them again.
+	Clear(ctx context.Context, id, note string) (*domain.ScreeningHit, error)
+}
+
+type screeningUsecase struct {
+	screeningRepo   domain.ScreeningRepository
+	auditRepo       domain.AuditRepository
+	txnRepo         domain.TxnRepository
+	list            domain.SanctionsListSource
+	reviewThreshold float64
+	blockThreshold  float64
+	logger          *slog.Logger
+}
+
+// NewScreeningUsecase flags the names at least reviewThreshold similar to a
+// list entry, and blocks them from blockThreshold on. A nil list screens no
+// one, but the hits already confirmed still block.
+func NewScreeningUsecase(sr domain.ScreeningRepository, ar domain.AuditRepository, tr domain.TxnRepository, list domain.SanctionsListSource, reviewThreshold, blockThreshold float64, logger *slog.Logger) ScreeningUsecase {
+	return &screeningUsecase{
+		screeningRepo:   sr,
+		auditRepo:       ar,
+		txnRepo:         tr,
+		list:            list,
+		reviewThreshold: reviewThreshold,
+		blockThreshold:  blockThreshold,
+		logger:          logger,
+	}
+}
+
+func (u *screeningUsecase) Screen(ctx context.Context, source domain.ScreeningSource, users ...*domain.User) (map[string]domain.ScreeningStatus, error) {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	hits, err := u.screeningRepo.ListByUsers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var list *domain.SanctionsList
+	if u.list != nil {
+		list = u.list.SanctionsList()
+	}
+	if list != nil {
+		var found []domain.ScreeningHit
+		for _, user := range users {
+			for _, match := range list.Match(user.Name, u.reviewThreshold) {
+				if hasHit(hits, user.ID, match.Entry.ID) || hasHit(found, user.ID, match.Entry.ID) {
+					continue
+				}
+				found = append(found, u.newHit(ctx, source, user, match))
+			}
+		}
+		if err := u.screeningRepo.SaveHits(ctx, found); err != nil {
+			return nil, err
+		}
+		hits = append(hits, found...)
+	}
+
+	statuses := make(map[string]domain.ScreeningStatus, len(users))
+	for _, user := range users {
+		var own []domain.ScreeningHit
+		for _, hit := range hits {
+			if hit.UserID == user.ID {
+				own = append(own, hit)
+			}
+		}
+		statuses[user.ID] = domain.ScreeningStatusOf(own)
+	}
+	return statuses, nil
+}
+
+func (u *screeningUsecase) newHit(ctx context.Context, source domain.ScreeningSource, user *domain.User, match domain.SanctionsMatch) domain.ScreeningHit {
+	hit := domain.ScreeningHit{
+		ID:           uuid.New().String(),
+		UserID:       user.ID,
+		ScreenedName: user.Name,
+		EntryID:      match.Entry.ID,
+		EntryName:    match.Entry.Name,
+		Programs:     strings.Join(match.Entry.Programs, ", "),
+		Score:        math.Round(match.Score*10000) / 10000,
+		Source:       source,
+		Status:       domain.HitPending,
+	}
+	if match.Score >= u.blockThreshold {
+		hit.Status = domain.HitConfirmed
+	}
+	u.logger.WarnContext(ctx, "sanctions screening hit",
+		"user_id", user.ID,
+		"entry_id", hit.EntryID,
+		"score", hit.Score,
+		"status", hit.Status,
+		"source", source,
+	)
+	return hit
+}
+
+func hasHit(hits []domain.ScreeningHit, userID, entryID string) bool {
+	for _, hit := range hits {
+		if hit.UserID == userID && hit.EntryID == entryID {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *screeningUsecase) List(ctx context.Context, status domain.ScreeningHitStatus) ([]domain.ScreeningHit, error) {
+	if _, ok := domain.UserFromContext(ctx); ok {
+		return nil, domain.ErrScreeningByUser
+	}
+	return u.screeningRepo.List(ctx, status)
+}
+
+func (u *screeningUsecase) Get(ctx context.Context, id string) (*domain.ScreeningHit, error) {
+	if _, ok := domain.UserFromContext(ctx); ok {
+		return nil, domain.ErrScreeningByUser
+	}
+	return u.screeningRepo.FindByID(ctx, id)
+}
+
+func (u *screeningUsecase) Confirm(ctx context.Context, id, note string) (*domain.ScreeningHit, error) {
+	return u.resolve(ctx, id, domain.HitConfirmed, domain.AuditHitConfirmed, note)
+}
+
+func (u *screeningUsecase) Clear(ctx context.Context, id, note string) (*domain.ScreeningHit, error) {
+	return u.resolve(ctx, id, domain.HitCleared, domain.AuditHitCleared, note)
+}
+
+func (u *screeningUsecase) resolve(ctx context.Context, id string, status domain.ScreeningHitStatus, action, note string) (*domain.ScreeningHit, error) {
+	if _, ok := domain.UserFromContext(ctx); ok {
+		return nil, domain.ErrScreeningByUser
+	}
+	var hit *domain.ScreeningHit
+	err := u.txnRepo.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if hit, err = u.screeningRepo.FindByIDForUpdate(txCtx, id); err != nil {
+			return err
+		}
+		if err := hit.Resolve(status, domain.ActorFromContext(txCtx), note, time.Now()); err != nil {
+			return err
+		}
+		if err := u.screeningRepo.Resolve(txCtx, hit); err != nil {
+			return err
+		}
+		return u.auditRepo.Record(txCtx, newAuditEntry(txCtx, action, domain.AuditEntityHit, hit.ID, note, map[string]interface{}{
+			"user_id":  hit.UserID,
+			"entry_id": hit.EntryID,
+			"score":    hit.Score,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hit, nil
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// statementPageSize is how many movements are read per query while rendering.
+const statementPageSize = 500
+
+// StatementUsecase produces wallet statements.
+type StatementUsecase interface {
+	// Open checks the wallet and the range [from, to) and computes the opening balance.
+	Open(ctx context.Context, walletID string, from, to time.Time) (*domain.Statement, error)
+	// Render writes the movements of statement to r, reading them in pages.
+	Render(ctx context.Context, statement *domain.Statement, r domain.StatementRenderer) error
+}
+
+type statementUsecase struct {
+	walletRepo   domain.WalletRepository
+	movementRepo domain.MovementRepository
+	memberRepo   domain.WalletMemberRepository
+}
+
+func NewStatementUsecase(wr domain.WalletRepository, mr domain.MovementRepository, mbr domain.WalletMemberRepository) StatementUsecase {
+	return &statementUsecase{walletRepo: wr, movementRepo: mr, memberRepo: mbr}
+}
+
+func (u *statementUsecase) Open(ctx context.Context, walletID string, from, to time.Time) (*domain.Statement, error) {
+	if !to.After(from) {
+		return nil, domain.ErrInvalidStatementRange
+	}
+	wallet, err := u.walletRepo.FindByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, walletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	opening, err := u.movementRepo.BalanceAt(ctx, walletID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Statement{
+		ID:             uuid.New().String(),
+		WalletID:       wallet.ID,
+		Currency:       wallet.Currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		GeneratedAt:    time.Now().UTC(),
+	}, nil
+}
+
+func (u *statementUsecase) Render(ctx context.Context, statement *domain.Statement, r domain.StatementRenderer) error {
+	if err := r.Begin(statement); err != nil {
+		return err
+	}
+
+	balance := statement.OpeningBalance
+	var last *domain.Movement
+	for {
+		movements, err := u.movementRepo.ListByWalletAfter(ctx, statement.WalletID, statement.From, statement.To, last, statementPageSize)
+		if err != nil {
+			return err
+		}
+		for i := range movements {
+			if err := r.Movement(&movements[i]); err != nil {
+				return err
+			}
+			balance = movements[i].BalanceAfter
+		}
+		if len(movements) < statementPageSize {
+			return r.End(balance)
+		}
+		last = &movements[len(movements)-1]
+	}
+}
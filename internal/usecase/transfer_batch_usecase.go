@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// TransferRequest is one transfer submitted in a batch, from the wallet of
+// the batch.
+type TransferRequest struct {
+	ToWalletID string  `json:"to_wallet_id"`
+	Amount     float64 `json:"amount"`
+}
+
+// TransferBatchUsecase runs many transfers as one background job.
+type TransferBatchUsecase interface {
+	// Submit validates every transfer from fromWalletID and stores the batch
+	// for processing. A user must be allowed to spend from the wallet. It
+	// returns a *domain.TransferBatchValidationError listing every invalid
+	// transfer, in which case nothing is stored.
+	Submit(ctx context.Context, mode domain.TransferBatchMode, fromWalletID string, transfers []TransferRequest) (*domain.TransferBatch, error)
+	// Get returns a batch and the outcome of each of its transfers, to the
+	// members of the wallet it sends from.
+	Get(ctx context.Context, id string) (*domain.TransferBatch, []domain.TransferBatchItem, error)
+	// ProcessNext processes the next batch waiting for a worker and reports
+	// whether there was one.
+	ProcessNext(ctx context.Context) (bool, error)
+}
+
+type transferBatchUsecase struct {
+	batchRepo     domain.TransferBatchRepository
+	walletRepo    domain.WalletRepository
+	memberRepo    domain.WalletMemberRepository
+	walletUsecase WalletUsecase
+	txnRepo       domain.TxnRepository
+	maxItems      int
+	lease         time.Duration
+	logger        *slog.Logger
+}
+
+// NewTransferBatchUsecase creates a TransferBatchUsecase. Batches hold at most
+// maxItems transfers; a batch whose worker reported no progress for lease is
+// handed to another worker.
+func NewTransferBatchUsecase(br domain.TransferBatchRepository, wr domain.WalletRepository, mbr domain.WalletMemberRepository, wu WalletUsecase, tr domain.TxnRepository, maxItems int, lease time.Duration, logger *slog.Logger) TransferBatchUsecase {
+	return &transferBatchUsecase{
+		batchRepo:     br,
+		walletRepo:    wr,
+		memberRepo:    mbr,
+		walletUsecase: wu,
+		txnRepo:       tr,
+		maxItems:      maxItems,
+		lease:         lease,
+		logger:        logger,
+	}
+}
+
+func (u *transferBatchUsecase) Submit(ctx context.Context, mode domain.TransferBatchMode, fromWalletID string, transfers []TransferRequest) (*domain.TransferBatch, error) {
+	if mode != domain.TransferBatchAtomic && mode != domain.TransferBatchBestEffort {
+		return nil, fmt.Errorf("%w: unknown mode %q, use %s or %s", domain.ErrInvalidTransferBatch, mode, domain.TransferBatchAtomic, domain.TransferBatchBestEffort)
+	}
+	if uuid.Validate(fromWalletID) != nil {
+		return nil, fmt.Errorf("%w: invalid sender wallet ID %q", domain.ErrInvalidTransferBatch, fromWalletID)
+	}
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("%w: no transfers", domain.ErrInvalidTransferBatch)
+	}
+	if len(transfers) > u.maxItems {
+		return nil, fmt.Errorf("%w: %d transfers, at most %d are allowed", domain.ErrInvalidTransferBatch, len(transfers), u.maxItems)
+	}
+	if _, err := u.walletRepo.FindByID(ctx, fromWalletID); err != nil {
+		return nil, err
+	}
+	// Spending limits are checked as each transfer runs, like the balance.
+	if _, err := authorizeMember(ctx, u.memberRepo, fromWalletID, (*domain.WalletMember).CanSpend, domain.ErrSpendNotAllowed); err != nil {
+		return nil, err
+	}
+	if err := u.validate(ctx, fromWalletID, transfers); err != nil {
+		return nil, err
+	}
+
+	batch := &domain.TransferBatch{
+		ID:           uuid.New().String(),
+		FromWalletID: fromWalletID,
+		Mode:         mode,
+		Status:       domain.TransferBatchPending,
+		Actor:        domain.ActorFromContext(ctx),
+		Total:        len(transfers),
+	}
+	items := make([]domain.TransferBatchItem, len(transfers))
+	for i, t := range transfers {
+		items[i] = domain.TransferBatchItem{
+			ID:           uuid.New().String(),
+			BatchID:      batch.ID,
+			Position:     i + 1,
+			FromWalletID: fromWalletID,
+			ToWalletID:   t.ToWalletID,
+			Amount:       t.Amount,
+			Status:       domain.TransferItemPending,
+		}
+	}
+	if err := u.batchRepo.Save(ctx, batch, items); err != nil {
+		return nil, err
+	}
+
+	u.logger.InfoContext(ctx, "transfer batch submitted", "batch_id", batch.ID, "mode", mode, "transfers", len(items))
+	return batch, nil
+}
+
+// validate checks what can be known before running the transfers. Balances
+// and wallet statuses are checked when each transfer runs, as they may change
+// in between.
+func (u *transferBatchUsecase) validate(ctx context.Context, fromWalletID string, transfers []TransferRequest) error {
+	var invalid []domain.TransferBatchItemError
+	reject := func(i int, format string, args ...interface{}) {
+		invalid = append(invalid, domain.TransferBatchItemError{Position: i + 1, Error: fmt.Sprintf(format, args...)})
+	}
+
+	exists := make(map[string]error)
+	walletExists := func(id string) error {
+		if err, ok := exists[id]; ok {
+			return err
+		}
+		_, err := u.walletRepo.FindByID(ctx, id)
+		exists[id] = err
+		return err
+	}
+
+	for i, t := range transfers {
+		switch {
+		case uuid.Validate(t.ToWalletID) != nil:
+			reject(i, "invalid receiver wallet ID %q", t.ToWalletID)
+			continue
+		case t.Amount <= 0:
+			reject(i, "transfer amount must be positive")
+			continue
+		case t.ToWalletID == fromWalletID:
+			reject(i, "cannot transfer to the same wallet")
+			continue
+		}
+
+		err := walletExists(t.ToWalletID)
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			reject(i, "wallet %s not found", t.ToWalletID)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if len(invalid) > 0 {
+		return &domain.TransferBatchValidationError{Items: invalid}
+	}
+	return nil
+}
+
+func (u *transferBatchUsecase) Get(ctx context.Context, id string) (*domain.TransferBatch, []domain.TransferBatchItem, error) {
+	batch, err := u.batchRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, batch.FromWalletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, nil, err
+	}
+	items, err := u.batchRepo.ListItems(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return batch, items, nil
+}
+
+func (u *transferBatchUsecase) ProcessNext(ctx context.Context) (bool, error) {
+	batch, err := u.batchRepo.Claim(ctx, time.Now().Add(-u.lease))
+	if err != nil || batch == nil {
+		return false, err
+	}
+
+	// The transfers are made on behalf of whoever submitted the batch.
+	ctx = domain.WithActor(ctx, batch.Actor)
+	u.logger.InfoContext(ctx, "processing transfer batch", "batch_id", batch.ID, "mode", batch.Mode)
+
+	items, err := u.batchRepo.ListItems(ctx, batch.ID)
+	if err != nil {
+		return true, err
+	}
+	if batch.Mode == domain.TransferBatchAtomic {
+		err = u.processAtomic(ctx, items)
+	} else {
+		err = u.processBestEffort(ctx, batch, items)
+	}
+	if errors.Is(err, domain.ErrTransferBatchItemProcessed) {
+		// Our claim went stale and another worker took the batch over.
+		u.logger.WarnContext(ctx, "transfer batch taken over by another worker", "batch_id", batch.ID)
+		return true, nil
+	}
+	if err != nil {
+		// Left in processing: another worker resumes it once the claim is stale.
+		return true, err
+	}
+
+	return true, u.finish(ctx, batch, items)
+}
+
+// processAtomic runs every transfer in one transaction. If one fails, it is
+// marked failed and the others skipped. A transfer held for review fails the
+// same way: approving it would make that transfer alone, so its review is
+// rolled back with the batch.
+func (u *transferBatchUsecase) processAtomic(ctx context.Context, items []domain.TransferBatchItem) error {
+	var failed int
+	var transferErr error
+	err := withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+		failed, transferErr = 0, nil
+		for i := range items {
+			item := items[i]
+			if err := u.walletUsecase.Transfer(txCtx, item.FromWalletID, item.ToWalletID, item.Amount); err != nil {
+				failed, transferErr = i, err
+				return err
+			}
+			item.Status = domain.TransferItemSucceeded
+			if err := u.batchRepo.UpdateItem(txCtx, &item); err != nil {
+				transferErr = nil
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		for i := range items {
+			items[i].Status = domain.TransferItemSucceeded
+		}
+		return nil
+	}
+	if transferErr == nil || ctx.Err() != nil {
+		return err
+	}
+
+	if errors.Is(transferErr, domain.ErrTransferHeld) {
+		transferErr = domain.ErrHeldInAtomicBatch
+	}
+	return u.txnRepo.WithTransaction(ctx, func(txCtx context.Context) error {
+		for i := range items {
+			items[i].Status = domain.TransferItemSkipped
+			if i == failed {
+				items[i].Status = domain.TransferItemFailed
+				items[i].Error = transferErr.Error()
+			}
+			if err := u.batchRepo.UpdateItem(txCtx, &items[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// processBestEffort runs each pending transfer in its own transaction, with
+// the update of its item, so a batch resumed by another worker never repeats
+// a transfer. A transfer held for review commits its review with the item.
+func (u *transferBatchUsecase) processBestEffort(ctx context.Context, batch *domain.TransferBatch, items []domain.TransferBatchItem) error {
+	for i := range items {
+		item := &items[i]
+		if item.Status != domain.TransferItemPending {
+			continue
+		}
+
+		var held *domain.TransferHeldError
+		err := withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+			held = nil
+			outcome := *item
+			outcome.Status = domain.TransferItemSucceeded
+			if err := u.walletUsecase.Transfer(txCtx, item.FromWalletID, item.ToWalletID, item.Amount); errors.As(err, &held) {
+				outcome.Status = domain.TransferItemHeld
+				outcome.ReviewID = &held.ReviewID
+			} else if err != nil {
+				return err
+			}
+			return u.batchRepo.UpdateItem(txCtx, &outcome)
+		})
+		switch {
+		case err == nil && held != nil:
+			item.Status = domain.TransferItemHeld
+			item.ReviewID = &held.ReviewID
+		case err == nil:
+			item.Status = domain.TransferItemSucceeded
+		case errors.Is(err, domain.ErrTransferBatchItemProcessed), ctx.Err() != nil:
+			return err
+		default:
+			item.Status = domain.TransferItemFailed
+			item.Error = err.Error()
+			if err := u.batchRepo.UpdateItem(ctx, item); err != nil {
+				return err
+			}
+		}
+
+		if err := u.batchRepo.Touch(ctx, batch.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *transferBatchUsecase) finish(ctx context.Context, batch *domain.TransferBatch, items []domain.TransferBatchItem) error {
+	batch.Succeeded, batch.Failed, batch.Held = 0, 0, 0
+	for _, item := range items {
+		switch item.Status {
+		case domain.TransferItemSucceeded:
+			batch.Succeeded++
+		case domain.TransferItemFailed:
+			batch.Failed++
+		case domain.TransferItemHeld:
+			batch.Held++
+		}
+	}
+
+	switch {
+	case batch.Succeeded == batch.Total:
+		batch.Status = domain.TransferBatchCompleted
+	case batch.Succeeded == 0 && batch.Held == 0:
+		batch.Status = domain.TransferBatchFailed
+	default:
+		batch.Status = domain.TransferBatchPartiallyCompleted
+	}
+	now := time.Now()
+	batch.FinishedAt = &now
+
+	u.logger.InfoContext(ctx, "transfer batch finished",
+		"batch_id", batch.ID,
+		"status", batch.Status,
+		"succeeded", batch.Succeeded,
+		"failed", batch.Failed,
+		"held", batch.Held,
+	)
+	return u.batchRepo.Finish(ctx, batch)
+}
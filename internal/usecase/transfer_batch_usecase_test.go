@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+)
+
+// Batches only take wallet IDs that are UUIDs.
+const (
+	aliceWallet = "00000000-0000-4000-8000-00000000a11c"
+	bobWallet   = "00000000-0000-4000-8000-000000000b0b"
+	carolWallet = "00000000-0000-4000-8000-0000000ca201"
+)
+
+// memBatchRepo keeps batches in memory and hands out the pending ones.
+type memBatchRepo struct {
+	batches map[string]domain.TransferBatch
+	items   map[string]domain.TransferBatchItem
+}
+
+func newMemBatchRepo() *memBatchRepo {
+	return &memBatchRepo{batches: make(map[string]domain.TransferBatch), items: make(map[string]domain.TransferBatchItem)}
+}
+
+func (r *memBatchRepo) Save(ctx context.Context, batch *domain.TransferBatch, items []domain.TransferBatchItem) error {
+	r.batches[batch.ID] = *batch
+	for _, item := range items {
+		r.items[item.ID] = item
+	}
+	return nil
+}
+
+func (r *memBatchRepo) FindByID(ctx context.Context, id string) (*domain.TransferBatch, error) {
+	batch, ok := r.batches[id]
+	if !ok {
+		return nil, domain.ErrTransferBatchNotFound
+	}
+	return &batch, nil
+}
+
+func (r *memBatchRepo) ListItems(ctx context.Context, batchID string) ([]domain.TransferBatchItem, error) {
+	var items []domain.TransferBatchItem
+	for _, item := range r.items {
+		if item.BatchID == batchID {
+			items = append(items, item)
+		}
+	}
+	slices.SortFunc(items, func(a, b domain.TransferBatchItem) int { return a.Position - b.Position })
+	return items, nil
+}
+
+func (r *memBatchRepo) Claim(ctx context.Context, staleBefore time.Time) (*domain.TransferBatch, error) {
+	for id, batch := range r.batches {
+		if batch.Status == domain.TransferBatchPending {
+			batch.Status = domain.TransferBatchProcessing
+			r.batches[id] = batch
+			return &batch, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memBatchRepo) Touch(ctx context.Context, batchID string) error { return nil }
+
+func (r *memBatchRepo) UpdateItem(ctx context.Context, item *domain.TransferBatchItem) error {
+	if r.items[item.ID].Status != domain.TransferItemPending {
+		return domain.ErrTransferBatchItemProcessed
+	}
+	r.items[item.ID] = *item
+	return nil
+}
+
+func (r *memBatchRepo) Finish(ctx context.Context, batch *domain.TransferBatch) error {
+	r.batches[batch.ID] = *batch
+	return nil
+}
+
+// statuses returns the status of every item of a batch, in order.
+func (r *memBatchRepo) statuses(batchID string) []domain.TransferBatchItemStatus {
+	items, _ := r.ListItems(context.Background(), batchID)
+	statuses := make([]domain.TransferBatchItemStatus, len(items))
+	for i, item := range items {
+		statuses[i] = item.Status
+	}
+	return statuses
+}
+
+// rollbackTxnRepo is fakeTxnRepo with rollbacks: a failed transaction leaves
+// the wallets, batch items and reviews as they were when it began.
+type rollbackTxnRepo struct {
+	wallets *memWalletRepo
+	batches *memBatchRepo
+	reviews *memReviewRepo
+}
+
+func (r rollbackTxnRepo) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	if domain.InTransaction(ctx) {
+		return fn(ctx)
+	}
+	wallets, items, reviews := maps.Clone(r.wallets.wallets), maps.Clone(r.batches.items), maps.Clone(r.reviews.reviews)
+	if err := (fakeTxnRepo{}).WithTransaction(ctx, fn); err != nil {
+		r.wallets.wallets, r.batches.items, r.reviews.reviews = wallets, items, reviews
+		return err
+	}
+	return nil
+}
+
+// batchFixture runs batches from Alice's wallet to Bob's and Carol's, with
+// risk scoring the transfers when not nil.
+type batchFixture struct {
+	*walletFixture
+	batches *memBatchRepo
+	usecase TransferBatchUsecase
+}
+
+func newBatchFixture(aliceBalance float64, risk domain.RiskRulesSource) *batchFixture {
+	f := newWalletFixture(0, 0)
+	for _, w := range []domain.Wallet{
+		{ID: aliceWallet, UserID: "alice", Currency: "USD", Balance: aliceBalance, Status: domain.WalletActive},
+		{ID: bobWallet, UserID: "bob", Currency: "USD", Status: domain.WalletActive},
+		{ID: carolWallet, UserID: "carol", Currency: "USD", Status: domain.WalletActive},
+	} {
+		f.wallets.wallets[w.ID] = w
+	}
+	f.users.users["carol"] = domain.User{ID: "carol", Username: "carol", Name: "Carol Danvers"}
+	f.members.owner(aliceWallet, "alice").owner(bobWallet, "bob").owner(carolWallet, "carol")
+
+	batches := newMemBatchRepo()
+	txn := rollbackTxnRepo{wallets: f.wallets, batches: batches, reviews: f.reviews}
+	return &batchFixture{
+		walletFixture: f,
+		batches:       batches,
+		usecase:       NewTransferBatchUsecase(batches, f.wallets, f.members, f.usecase(nil, risk), txn, 10, time.Minute, discardLogger),
+	}
+}
+
+// run submits a batch as Alice and processes it.
+func (f *batchFixture) run(t *testing.T, mode domain.TransferBatchMode, transfers ...TransferRequest) *domain.TransferBatch {
+	t.Helper()
+	ctx := domain.WithActor(context.Background(), domain.UserActor("alice"))
+	batch, err := f.usecase.Submit(ctx, mode, aliceWallet, transfers)
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if ok, err := f.usecase.ProcessNext(context.Background()); !ok || err != nil {
+		t.Fatalf("ProcessNext = %v, %v; want the batch processed", ok, err)
+	}
+	processed, _ := f.batches.FindByID(ctx, batch.ID)
+	return processed
+}
+
+func TestSubmitRejectsInvalidBatches(t *testing.T) {
+	f := newBatchFixture(100, nil)
+	alice := domain.WithActor(context.Background(), domain.UserActor("alice"))
+
+	_, err := f.usecase.Submit(alice, domain.TransferBatchBestEffort, aliceWallet, []TransferRequest{
+		{ToWalletID: bobWallet, Amount: 10},
+		{ToWalletID: "w-bob", Amount: 10},
+		{ToWalletID: carolWallet, Amount: 0},
+		{ToWalletID: aliceWallet, Amount: 10},
+		{ToWalletID: "00000000-0000-4000-8000-00000000dead", Amount: 10},
+	})
+	var invalid *domain.TransferBatchValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Submit = %v, want a TransferBatchValidationError", err)
+	}
+	var positions []int
+	for _, item := range invalid.Items {
+		positions = append(positions, item.Position)
+	}
+	if want := []int{2, 3, 4, 5}; !slices.Equal(positions, want) {
+		t.Errorf("invalid transfers = %v, want %v", positions, want)
+	}
+	if len(f.batches.batches) != 0 || len(f.batches.items) != 0 {
+		t.Error("an invalid batch was stored")
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		mode      domain.TransferBatchMode
+		transfers []TransferRequest
+		want      error
+	}{
+		{"unknown mode", alice, "eventually", []TransferRequest{{ToWalletID: bobWallet, Amount: 1}}, domain.ErrInvalidTransferBatch},
+		{"no transfers", alice, domain.TransferBatchAtomic, nil, domain.ErrInvalidTransferBatch},
+		{"too many transfers", alice, domain.TransferBatchAtomic, make([]TransferRequest, 11), domain.ErrInvalidTransferBatch},
+		{"someone else's wallet", domain.WithActor(context.Background(), domain.UserActor("bob")), domain.TransferBatchAtomic, []TransferRequest{{ToWalletID: bobWallet, Amount: 1}}, domain.ErrSpendNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.usecase.Submit(tt.ctx, tt.mode, aliceWallet, tt.transfers); !errors.Is(err, tt.want) {
+				t.Errorf("Submit = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessBatches(t *testing.T) {
+	// The second transfer can't be made: Alice has 70 left when it runs.
+	transfers := []TransferRequest{
+		{ToWalletID: bobWallet, Amount: 30},
+		{ToWalletID: carolWallet, Amount: 80},
+		{ToWalletID: bobWallet, Amount: 10},
+	}
+
+	tests := []struct {
+		name         string
+		mode         domain.TransferBatchMode
+		balance      float64
+		wantStatus   domain.TransferBatchStatus
+		wantItems    []domain.TransferBatchItemStatus
+		wantBalances [3]float64 // Alice, Bob, Carol
+	}{
+		{
+			"atomic, all made", domain.TransferBatchAtomic, 200, domain.TransferBatchCompleted,
+			[]domain.TransferBatchItemStatus{domain.TransferItemSucceeded, domain.TransferItemSucceeded, domain.TransferItemSucceeded},
+			[3]float64{80, 40, 80},
+		},
+		{
+			"atomic, one fails", domain.TransferBatchAtomic, 100, domain.TransferBatchFailed,
+			[]domain.TransferBatchItemStatus{domain.TransferItemSkipped, domain.TransferItemFailed, domain.TransferItemSkipped},
+			[3]float64{100, 0, 0},
+		},
+		{
+			"best effort, one fails", domain.TransferBatchBestEffort, 100, domain.TransferBatchPartiallyCompleted,
+			[]domain.TransferBatchItemStatus{domain.TransferItemSucceeded, domain.TransferItemFailed, domain.TransferItemSucceeded},
+			[3]float64{60, 40, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newBatchFixture(tt.balance, nil)
+			batch := f.run(t, tt.mode, transfers...)
+
+			if batch.Status != tt.wantStatus {
+				t.Errorf("batch status = %s, want %s", batch.Status, tt.wantStatus)
+			}
+			if got := f.batches.statuses(batch.ID); !slices.Equal(got, tt.wantItems) {
+				t.Errorf("item statuses = %v, want %v", got, tt.wantItems)
+			}
+			got := [3]float64{f.wallets.get(aliceWallet).Balance, f.wallets.get(bobWallet).Balance, f.wallets.get(carolWallet).Balance}
+			if got != tt.wantBalances {
+				t.Errorf("balances of Alice, Bob and Carol = %v, want %v", got, tt.wantBalances)
+			}
+		})
+	}
+}
+
+// An atomic batch can't wait for a review that would approve one of its
+// transfers alone, so a held transfer fails it and opens no review.
+func TestAtomicBatchFailsOnAHeldTransfer(t *testing.T) {
+	f := newBatchFixture(100, reviewEveryTransfer{})
+	batch := f.run(t, domain.TransferBatchAtomic,
+		TransferRequest{ToWalletID: bobWallet, Amount: 30},
+		TransferRequest{ToWalletID: carolWallet, Amount: 20},
+	)
+
+	if batch.Status != domain.TransferBatchFailed || batch.Held != 0 {
+		t.Errorf("batch status = %s with %d held, want failed with none", batch.Status, batch.Held)
+	}
+	items, _ := f.batches.ListItems(context.Background(), batch.ID)
+	want := []domain.TransferBatchItemStatus{domain.TransferItemFailed, domain.TransferItemSkipped}
+	if got := f.batches.statuses(batch.ID); !slices.Equal(got, want) {
+		t.Errorf("item statuses = %v, want %v", got, want)
+	}
+	if items[0].ReviewID != nil || items[0].Error != domain.ErrHeldInAtomicBatch.Error() {
+		t.Errorf("failed item has review %v and error %q, want no review and %q", items[0].ReviewID, items[0].Error, domain.ErrHeldInAtomicBatch)
+	}
+	if len(f.reviews.reviews) != 0 {
+		t.Errorf("%d reviews opened, want none", len(f.reviews.reviews))
+	}
+	if got := f.wallets.get(aliceWallet).Balance; got != 100 {
+		t.Errorf("Alice's balance = %v, want 100", got)
+	}
+}
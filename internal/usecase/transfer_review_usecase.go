@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"wallet/internal/domain"
+)
+
+// TransferReviewUsecase lets operators decide on the transfers the risk rules
+// held. Users can't review transfers, their own included.
+type TransferReviewUsecase interface {
+	// List returns the reviews in status, oldest first; all of them when
+	// status is empty.
+	List(ctx context.Context, status domain.TransferReviewStatus) ([]domain.TransferReview, error)
+	Get(ctx context.Context, id string) (*domain.TransferReview, error)
+	// Approve makes the held transfer on behalf of whoever asked for it, with
+	// the checks of any other transfer but the risk rules. If it can't be
+	// made the review ends failed, with the reason in its note. A payment
+	// request held on the review is paid or declined with it.
+	Approve(ctx context.Context, id, note string) (*domain.TransferReview, error)
+	Reject(ctx context.Context, id, note string) (*domain.TransferReview, error)
+}
+
+type transferReviewUsecase struct {
+	reviewRepo    domain.TransferReviewRepository
+	walletUsecase WalletUsecase
+	requests      PaymentRequestUsecase
+	auditRepo     domain.AuditRepository
+	txnRepo       domain.TxnRepository
+}
+
+func NewTransferReviewUsecase(rr domain.TransferReviewRepository, wu WalletUsecase, pru PaymentRequestUsecase, ar domain.AuditRepository, tr domain.TxnRepository) TransferReviewUsecase {
+	return &transferReviewUsecase{
+		reviewRepo:    rr,
+		walletUsecase: wu,
+		requests:      pru,
+		auditRepo:     ar,
+		txnRepo:       tr,
+	}
+}
+
+func (u *transferReviewUsecase) List(ctx context.Context, status domain.TransferReviewStatus) ([]domain.TransferReview, error) {
+	if _, ok := domain.UserFromContext(ctx); ok {
+		return nil, domain.ErrReviewByUser
+	}
+	return u.reviewRepo.List(ctx, status)
+}
+
+func (u *transferReviewUsecase) Get(ctx context.Context, id string) (*domain.TransferReview, error) {
+	if _, ok := domain.UserFromContext(ctx); ok {
+		return nil, domain.ErrReviewByUser
+	}
+	return u.reviewRepo.FindByID(ctx, id)
+}
+
+func (u *transferReviewUsecase) Approve(ctx context.Context, id, note string) (*domain.TransferReview, error) {
+	return u.resolve(ctx, id, domain.ReviewApproved, note, func(txCtx context.Context, review *domain.TransferReview) error {
+		transferCtx := withRiskApproved(domain.WithActor(txCtx, review.Actor))
+		return u.walletUsecase.Transfer(transferCtx, review.FromWalletID, review.ToWalletID, review.Amount)
+	})
+}
+
+func (u *transferReviewUsecase) Reject(ctx context.Context, id, note string) (*domain.TransferReview, error) {
+	return u.resolve(ctx, id, domain.ReviewRejected, note, nil)
+}
+
+// resolve moves a pending review to status, running apply first in the same
+// transaction with the review locked. When apply fails for a reason other
+// than a conflict, the review ends failed instead.
+func (u *transferReviewUsecase) resolve(ctx context.Context, id string, status domain.TransferReviewStatus, note string, apply func(ctx context.Context, review *domain.TransferReview) error) (*domain.TransferReview, error) {
+	if _, ok := domain.UserFromContext(ctx); ok {
+		return nil, domain.ErrReviewByUser
+	}
+
+	var review *domain.TransferReview
+	err := withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+		var err error
+		review, err = u.reviewRepo.FindByIDForUpdate(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		target, targetNote := status, note
+		if apply != nil && review.Status == domain.ReviewPending {
+			// The transfer runs in a savepoint: if it fails, the failure is still recorded.
+			applyErr := apply(txCtx, review)
+			if isRetryable(applyErr) || ctx.Err() != nil {
+				return applyErr
+			}
+			if applyErr != nil {
+				target, targetNote = domain.ReviewFailed, applyErr.Error()
+			}
+		}
+		if err := review.Resolve(target, domain.ActorFromContext(txCtx), targetNote, time.Now()); err != nil {
+			return err
+		}
+		if err := u.reviewRepo.Resolve(txCtx, review); err != nil {
+			return err
+		}
+		if err := u.requests.SettleHeld(txCtx, review); err != nil {
+			return err
+		}
+
+		action := domain.AuditReviewApproved
+		if status == domain.ReviewRejected {
+			action = domain.AuditReviewRejected
+		}
+		return u.auditRepo.Record(txCtx, newAuditEntry(txCtx, action, domain.AuditEntityReview, review.ID, note, map[string]interface{}{
+			"from_wallet_id": review.FromWalletID,
+			"to_wallet_id":   review.ToWalletID,
+			"amount":         review.Amount,
+			"status":         review.Status,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+type riskApprovedKey struct{}
+
+// withRiskApproved marks the transfer made with ctx as approved by a review,
+// so the risk rules don't score it again.
+func withRiskApproved(ctx context.Context) context.Context {
+	return context.WithValue(ctx, riskApprovedKey{}, true)
+}
+
+func riskApproved(ctx context.Context) bool {
+	approved, _ := ctx.Value(riskApprovedKey{}).(bool)
+	return approved
+}
@@ -2,7 +2,11 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
+	"fmt"
+	"math/big"
+	"time"
 	"wallet/internal/domain"
 
 	"github.com/google/uuid"
@@ -10,22 +14,50 @@ import (
 
 // UserUsecase defines the contract for user-related business logic.
 type UserUsecase interface {
+	// Create screens the new user's name against the sanctions list. A close
+	// match still creates the user, blocked, and returns ErrSanctioned.
 	Create(ctx context.Context, username, name, dni string) (*domain.User, error)
+	// SetTier moves a user to another tier, which selects the fees they pay.
+	SetTier(ctx context.Context, userID, tier, reason string) (*domain.User, error)
+
+	// AddAlias registers a phone number or email address others can send the
+	// user money to, once the user verifies it with the code sent to it.
+	// Registering a pending alias again sends a new code. Only the user
+	// registers and verifies their aliases.
+	AddAlias(ctx context.Context, userID string, kind domain.AliasKind, value string) (*domain.UserAlias, error)
+	// VerifyAlias checks the code sent to an alias and makes it find the
+	// user. It returns ErrInvalidAliasCode for a wrong or expired code.
+	VerifyAlias(ctx context.Context, userID, aliasID, code string) (*domain.UserAlias, error)
+	ListAliases(ctx context.Context, userID string) ([]domain.UserAlias, error)
+	RemoveAlias(ctx context.Context, userID, aliasID string) error
 }
 
 // userUsecase implements the UserUsecase interface.
 type userUsecase struct {
 	userRepo   domain.UserRepository
 	walletRepo domain.WalletRepository
+	memberRepo domain.WalletMemberRepository
+	aliasRepo  domain.AliasRepository
+	auditRepo  domain.AuditRepository
 	txnRepo    domain.TxnRepository
+	screening  ScreeningUsecase
+	notifier   domain.Notifier
+	aliasTTL   time.Duration
 }
 
-// NewUserUsecase creates a new userUsecase instance.
-func NewUserUsecase(ur domain.UserRepository, wr domain.WalletRepository, tr domain.TxnRepository) UserUsecase {
+// NewUserUsecase creates a new userUsecase instance. Alias verification codes
+// are sent through notifier and expire after aliasTTL.
+func NewUserUsecase(ur domain.UserRepository, wr domain.WalletRepository, mbr domain.WalletMemberRepository, alr domain.AliasRepository, ar domain.AuditRepository, tr domain.TxnRepository, su ScreeningUsecase, notifier domain.Notifier, aliasTTL time.Duration) UserUsecase {
 	return &userUsecase{
 		userRepo:   ur,
 		walletRepo: wr,
+		memberRepo: mbr,
+		aliasRepo:  alr,
+		auditRepo:  ar,
 		txnRepo:    tr,
+		screening:  su,
+		notifier:   notifier,
+		aliasTTL:   aliasTTL,
 	}
 }
 
@@ -35,7 +67,7 @@ func (u *userUsecase) Create(ctx context.Context, username string, name string,
 	existingUser, err := u.userRepo.FindByUsername(ctx, username)
 	if err == nil && existingUser != nil {
 		// If err is nil, a user was found, which is an error for us.
-		return nil, errors.New("username already exists")
+		return nil, domain.ErrUsernameTaken
 	}
 
 	// Create user with generated UUID
@@ -44,9 +76,11 @@ func (u *userUsecase) Create(ctx context.Context, username string, name string,
 		Username: username,
 		Name:     name,
 		DNI:      dni,
+		Tier:     domain.DefaultUserTier,
 	}
 
 	// Execute user and wallet creation within a single transaction.
+	var status domain.ScreeningStatus
 	err = u.txnRepo.WithTransaction(ctx, func(ctx context.Context) error {
 		// 1. Save the user first
 		if err := u.userRepo.Save(ctx, user); err != nil {
@@ -61,12 +95,229 @@ func (u *userUsecase) Create(ctx context.Context, username string, name string,
 			return err
 		}
 
+		// 3. Make the user the owner of the wallet
+		if err := u.memberRepo.Save(ctx, &domain.WalletMember{
+			ID:        uuid.New().String(),
+			WalletID:  wallet.ID,
+			UserID:    user.ID,
+			Role:      domain.RoleOwner,
+			Status:    domain.MemberActive,
+			InvitedBy: domain.ActorFromContext(ctx),
+		}); err != nil {
+			return err
+		}
+
+		// 4. Record who created the user
+		if err := u.auditRepo.Record(ctx, newAuditEntry(ctx, domain.AuditUserCreated, domain.AuditEntityUser, user.ID, "", map[string]interface{}{
+			"username":  user.Username,
+			"wallet_id": wallet.ID,
+		})); err != nil {
+			return err
+		}
+
+		// 5. Screen the user; the hits are kept for review whatever they say
+		statuses, err := u.screening.Screen(ctx, domain.ScreenedOnCreation, user)
+		if err != nil {
+			return err
+		}
+		status = statuses[user.ID]
 		return nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
+	if status == domain.ScreeningBlocked {
+		return nil, domain.ErrSanctioned
+	}
+
+	return user, nil
+}
+
+// SetTier implements UserUsecase.
+func (u *userUsecase) SetTier(ctx context.Context, userID, tier, reason string) (*domain.User, error) {
+	if tier == "" {
+		return nil, errors.New("tier is required")
+	}
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
 
+	var user *domain.User
+	err := u.txnRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = u.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		previous := user.Tier
+		if err := u.userRepo.UpdateTier(ctx, userID, tier); err != nil {
+			return err
+		}
+		user.Tier = tier
+		return u.auditRepo.Record(ctx, newAuditEntry(ctx, domain.AuditUserTierSet, domain.AuditEntityUser, userID, reason, map[string]interface{}{
+			"from": previous,
+			"to":   tier,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
 	return user, nil
 }
+
+// AddAlias implements UserUsecase.
+func (u *userUsecase) AddAlias(ctx context.Context, userID string, kind domain.AliasKind, value string) (*domain.UserAlias, error) {
+	if actor, ok := domain.UserFromContext(ctx); !ok || actor != userID {
+		return nil, domain.ErrOtherUser
+	}
+	normalized, err := domain.NormalizeAlias(kind, value)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if _, err := u.aliasRepo.FindByValue(ctx, kind, normalized); err == nil {
+		return nil, domain.ErrAliasTaken
+	} else if !errors.Is(err, domain.ErrAliasNotFound) {
+		return nil, err
+	}
+
+	code, err := newAliasCode()
+	if err != nil {
+		return nil, err
+	}
+	var alias *domain.UserAlias
+	err = u.txnRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		aliases, err := u.aliasRepo.ListByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for i := range aliases {
+			if aliases[i].Kind == kind && aliases[i].Value == normalized {
+				alias = &aliases[i]
+			}
+		}
+
+		// A pending alias gets a new code; the previous one stops working.
+		if alias != nil {
+			if alias.IsVerified() {
+				return domain.ErrAliasTaken
+			}
+			alias.SetCode(code, time.Now().Add(u.aliasTTL))
+			return u.aliasRepo.Update(ctx, alias)
+		}
+
+		alias = &domain.UserAlias{
+			ID:     uuid.New().String(),
+			UserID: userID,
+			Kind:   kind,
+			Value:  normalized,
+		}
+		alias.SetCode(code, time.Now().Add(u.aliasTTL))
+		if err := u.aliasRepo.Save(ctx, alias); err != nil {
+			return err
+		}
+		return u.auditRepo.Record(ctx, newAuditEntry(ctx, domain.AuditUserAliasAdded, domain.AuditEntityUser, userID, "", map[string]interface{}{
+			"alias_id": alias.ID,
+			"kind":     alias.Kind,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.notifier.Notify(ctx, domain.Notification{
+		Kind:    domain.NotificationAliasCode,
+		UserID:  userID,
+		Message: fmt.Sprintf("Your verification code is %s", code),
+		Data: map[string]interface{}{
+			"alias_id": alias.ID,
+			"kind":     alias.Kind,
+			"value":    alias.Value,
+			"code":     code,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("send alias verification code: %w", err)
+	}
+	return alias, nil
+}
+
+// VerifyAlias implements UserUsecase.
+func (u *userUsecase) VerifyAlias(ctx context.Context, userID, aliasID, code string) (*domain.UserAlias, error) {
+	if actor, ok := domain.UserFromContext(ctx); !ok || actor != userID {
+		return nil, domain.ErrOtherUser
+	}
+
+	var alias *domain.UserAlias
+	var valid bool
+	err := u.txnRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		alias, err = u.aliasRepo.FindByIDForUpdate(ctx, userID, aliasID)
+		if err != nil {
+			return err
+		}
+		if alias.IsVerified() {
+			valid = true
+			return nil
+		}
+
+		// Wrong codes are counted even though the verification fails.
+		valid = alias.CheckCode(code, time.Now())
+		if !valid {
+			return u.aliasRepo.Update(ctx, alias)
+		}
+		alias.Verify(time.Now())
+		if err := u.aliasRepo.Update(ctx, alias); err != nil {
+			return err
+		}
+		return u.auditRepo.Record(ctx, newAuditEntry(ctx, domain.AuditUserAliasVerified, domain.AuditEntityUser, userID, "", map[string]interface{}{
+			"alias_id": alias.ID,
+			"kind":     alias.Kind,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, domain.ErrInvalidAliasCode
+	}
+	return alias, nil
+}
+
+// newAliasCode returns a random 6-digit verification code.
+func newAliasCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// ListAliases implements UserUsecase.
+func (u *userUsecase) ListAliases(ctx context.Context, userID string) ([]domain.UserAlias, error) {
+	if err := authorizeUser(ctx, userID, domain.ErrOtherUser); err != nil {
+		return nil, err
+	}
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return u.aliasRepo.ListByUser(ctx, userID)
+}
+
+// RemoveAlias implements UserUsecase.
+func (u *userUsecase) RemoveAlias(ctx context.Context, userID, aliasID string) error {
+	if err := authorizeUser(ctx, userID, domain.ErrOtherUser); err != nil {
+		return err
+	}
+	return u.txnRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := u.aliasRepo.Delete(ctx, userID, aliasID); err != nil {
+			return err
+		}
+		return u.auditRepo.Record(ctx, newAuditEntry(ctx, domain.AuditUserAliasGone, domain.AuditEntityUser, userID, "", map[string]interface{}{
+			"alias_id": aliasID,
+		}))
+	})
+}
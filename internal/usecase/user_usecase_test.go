@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+)
+
+// An alias finds its user only once they enter the code sent to it, and only
+// the user themselves registers and verifies their aliases.
+func TestAliasesFindTheirUserOnceVerified(t *testing.T) {
+	f := newWalletFixture(0, 0)
+	aliases := &memAliasRepo{}
+	notifier := &recordingNotifier{}
+	screening := NewScreeningUsecase(f.screening, f.audit, fakeTxnRepo{}, nil, 0.85, 0.95, discardLogger)
+	users := NewUserUsecase(f.users, f.wallets, f.members, aliases, f.audit, fakeTxnRepo{}, screening, notifier, time.Minute)
+
+	alice := domain.WithActor(context.Background(), domain.UserActor("alice"))
+	bob := domain.WithActor(context.Background(), domain.UserActor("bob"))
+
+	if _, err := users.AddAlias(bob, "alice", domain.AliasEmail, "alice@example.com"); !errors.Is(err, domain.ErrOtherUser) {
+		t.Errorf("AddAlias for someone else: %v, want ErrOtherUser", err)
+	}
+	if _, err := users.AddAlias(context.Background(), "alice", domain.AliasEmail, "alice@example.com"); !errors.Is(err, domain.ErrOtherUser) {
+		t.Errorf("anonymous AddAlias: %v, want ErrOtherUser", err)
+	}
+
+	alias, err := users.AddAlias(alice, "alice", domain.AliasEmail, "Alice@example.com")
+	if err != nil {
+		t.Fatalf("AddAlias: %v", err)
+	}
+	sent := notifier.last()
+	if sent.Kind != domain.NotificationAliasCode || sent.Data["value"] != "alice@example.com" {
+		t.Fatalf("notification sent = %+v, want the code for alice@example.com", sent)
+	}
+	code := sent.Data["code"].(string)
+	if _, err := aliases.FindByValue(alice, domain.AliasEmail, "alice@example.com"); !errors.Is(err, domain.ErrAliasNotFound) {
+		t.Errorf("unverified alias lookup: %v, want ErrAliasNotFound", err)
+	}
+
+	// Registering a value doesn't reserve it.
+	bobs, err := users.AddAlias(bob, "bob", domain.AliasEmail, "alice@example.com")
+	if err != nil {
+		t.Fatalf("AddAlias of a pending value by another user: %v", err)
+	}
+
+	if _, err := users.VerifyAlias(bob, "alice", alias.ID, code); !errors.Is(err, domain.ErrOtherUser) {
+		t.Errorf("VerifyAlias for someone else: %v, want ErrOtherUser", err)
+	}
+	if _, err := users.VerifyAlias(alice, "alice", alias.ID, "not the code"); !errors.Is(err, domain.ErrInvalidAliasCode) {
+		t.Errorf("VerifyAlias with a wrong code: %v, want ErrInvalidAliasCode", err)
+	}
+	verified, err := users.VerifyAlias(alice, "alice", alias.ID, code)
+	if err != nil || !verified.IsVerified() {
+		t.Fatalf("VerifyAlias = %+v, %v; want it verified", verified, err)
+	}
+	found, err := aliases.FindByValue(alice, domain.AliasEmail, "alice@example.com")
+	if err != nil || found.UserID != "alice" {
+		t.Errorf("verified alias lookup = %+v, %v; want alice", found, err)
+	}
+
+	// Bob got a code too, but the value is Alice's now.
+	if _, err := users.VerifyAlias(bob, "bob", bobs.ID, notifier.sent[1].Data["code"].(string)); !errors.Is(err, domain.ErrAliasTaken) {
+		t.Errorf("VerifyAlias of a value verified by another user: %v, want ErrAliasTaken", err)
+	}
+}
+
+// A code stops working after too many wrong attempts; registering the alias
+// again sends a new one.
+func TestAliasCodeLocksAfterWrongAttempts(t *testing.T) {
+	f := newWalletFixture(0, 0)
+	notifier := &recordingNotifier{}
+	screening := NewScreeningUsecase(f.screening, f.audit, fakeTxnRepo{}, nil, 0.85, 0.95, discardLogger)
+	users := NewUserUsecase(f.users, f.wallets, f.members, &memAliasRepo{}, f.audit, fakeTxnRepo{}, screening, notifier, time.Minute)
+	alice := domain.WithActor(context.Background(), domain.UserActor("alice"))
+
+	alias, err := users.AddAlias(alice, "alice", domain.AliasPhone, "+14155552671")
+	if err != nil {
+		t.Fatalf("AddAlias: %v", err)
+	}
+	code := notifier.last().Data["code"].(string)
+	for range domain.AliasCodeAttempts {
+		if _, err := users.VerifyAlias(alice, "alice", alias.ID, "wrong"); !errors.Is(err, domain.ErrInvalidAliasCode) {
+			t.Fatalf("VerifyAlias with a wrong code: %v, want ErrInvalidAliasCode", err)
+		}
+	}
+	if _, err := users.VerifyAlias(alice, "alice", alias.ID, code); !errors.Is(err, domain.ErrInvalidAliasCode) {
+		t.Errorf("VerifyAlias after %d wrong codes: %v, want ErrInvalidAliasCode", domain.AliasCodeAttempts, err)
+	}
+
+	again, err := users.AddAlias(alice, "alice", domain.AliasPhone, "+1 415 555 2671")
+	if err != nil || again.ID != alias.ID {
+		t.Fatalf("AddAlias again = %+v, %v; want the same alias", again, err)
+	}
+	if _, err := users.VerifyAlias(alice, "alice", alias.ID, notifier.last().Data["code"].(string)); err != nil {
+		t.Errorf("VerifyAlias with the new code: %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"wallet/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// WalletMemberUsecase manages who shares a wallet. Only owners can invite and
+// remove members, and an invitation can only be accepted by the invitee;
+// operators are not restricted (see authorizeMember).
+type WalletMemberUsecase interface {
+	// Invite asks a user to join a wallet. The membership applies once they accept.
+	Invite(ctx context.Context, walletID, userID string, role domain.MemberRole, dailyLimit float64) (*domain.WalletMember, error)
+	Accept(ctx context.Context, walletID, userID string) (*domain.WalletMember, error)
+	// Remove takes a member out of a wallet, or withdraws their invitation.
+	// Members can remove themselves.
+	Remove(ctx context.Context, walletID, userID string) error
+	List(ctx context.Context, walletID string) ([]domain.WalletMember, error)
+}
+
+type walletMemberUsecase struct {
+	memberRepo domain.WalletMemberRepository
+	walletRepo domain.WalletRepository
+	auditRepo  domain.AuditRepository
+	txnRepo    domain.TxnRepository
+}
+
+func NewWalletMemberUsecase(mr domain.WalletMemberRepository, wr domain.WalletRepository, ar domain.AuditRepository, tr domain.TxnRepository) WalletMemberUsecase {
+	return &walletMemberUsecase{
+		memberRepo: mr,
+		walletRepo: wr,
+		auditRepo:  ar,
+		txnRepo:    tr,
+	}
+}
+
+func (u *walletMemberUsecase) Invite(ctx context.Context, walletID, userID string, role domain.MemberRole, dailyLimit float64) (*domain.WalletMember, error) {
+	member := &domain.WalletMember{
+		ID:         uuid.New().String(),
+		WalletID:   walletID,
+		UserID:     userID,
+		Role:       role,
+		DailyLimit: dailyLimit,
+		Status:     domain.MemberInvited,
+		InvitedBy:  domain.ActorFromContext(ctx),
+	}
+	if err := member.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := u.txnRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := u.walletRepo.FindByID(ctx, walletID); err != nil {
+			return err
+		}
+		if _, err := authorizeMember(ctx, u.memberRepo, walletID, isOwner, domain.ErrNotWalletMember); err != nil {
+			return err
+		}
+		if err := u.memberRepo.Save(ctx, member); err != nil {
+			return err
+		}
+		return u.audit(ctx, domain.AuditMemberInvited, member)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (u *walletMemberUsecase) Accept(ctx context.Context, walletID, userID string) (*domain.WalletMember, error) {
+	if err := authorizeUser(ctx, userID, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+
+	var member *domain.WalletMember
+	err := u.txnRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := u.memberRepo.Activate(ctx, walletID, userID); err != nil {
+			return err
+		}
+		var err error
+		if member, err = u.memberRepo.Find(ctx, walletID, userID); err != nil {
+			return err
+		}
+		return u.audit(ctx, domain.AuditMemberJoined, member)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (u *walletMemberUsecase) Remove(ctx context.Context, walletID, userID string) error {
+	return u.txnRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := u.walletRepo.FindByID(ctx, walletID)
+		if err != nil {
+			return err
+		}
+		if wallet.UserID == userID {
+			return domain.ErrCannotRemoveCreator
+		}
+		if actor, ok := domain.UserFromContext(ctx); !ok || actor != userID {
+			if _, err := authorizeMember(ctx, u.memberRepo, walletID, isOwner, domain.ErrNotWalletMember); err != nil {
+				return err
+			}
+		}
+
+		member, err := u.memberRepo.Find(ctx, walletID, userID)
+		if err != nil {
+			return err
+		}
+		if err := u.memberRepo.Delete(ctx, walletID, userID); err != nil {
+			return err
+		}
+		return u.audit(ctx, domain.AuditMemberRemoved, member)
+	})
+}
+
+func (u *walletMemberUsecase) List(ctx context.Context, walletID string) ([]domain.WalletMember, error) {
+	if _, err := u.walletRepo.FindByID(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, walletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	return u.memberRepo.ListByWallet(ctx, walletID)
+}
+
+func (u *walletMemberUsecase) audit(ctx context.Context, action string, member *domain.WalletMember) error {
+	return u.auditRepo.Record(ctx, newAuditEntry(ctx, action, domain.AuditEntityWallet, member.WalletID, "", map[string]interface{}{
+		"user_id":     member.UserID,
+		"role":        member.Role,
+		"daily_limit": member.DailyLimit,
+	}))
+}
+
+// authorizeMember checks that the user acting in ctx is a member of walletID
+// that allow accepts, and returns their membership. It returns denied when
+// they are not, and when nobody identified themselves. Operators and
+// background jobs are not restricted, and get a nil membership.
+func authorizeMember(ctx context.Context, repo domain.WalletMemberRepository, walletID string, allow func(*domain.WalletMember) bool, denied error) (*domain.WalletMember, error) {
+	userID, ok := domain.UserFromContext(ctx)
+	if !ok {
+		if domain.IsInternal(ctx) {
+			return nil, nil
+		}
+		return nil, denied
+	}
+	member, err := repo.Find(ctx, walletID, userID)
+	if errors.Is(err, domain.ErrMemberNotFound) {
+		return nil, denied
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !allow(member) {
+		return nil, denied
+	}
+	return member, nil
+}
+
+// authorizeUser checks that the actor in ctx is the user userID, an operator
+// or a background job, and returns denied otherwise.
+func authorizeUser(ctx context.Context, userID string, denied error) error {
+	if actor, ok := domain.UserFromContext(ctx); (ok && actor == userID) || domain.IsInternal(ctx) {
+		return nil
+	}
+	return denied
+}
+
+func isMember(m *domain.WalletMember) bool { return m.IsActive() }
+
+func isOwner(m *domain.WalletMember) bool { return m.IsActive() && m.Role == domain.RoleOwner }
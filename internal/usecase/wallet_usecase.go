@@ -3,27 +3,102 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 	"wallet/internal/domain"
+
+	"github.com/google/uuid"
 )
 
 type WalletUsecase interface {
+	GetWallet(ctx context.Context, walletID string) (*domain.Wallet, error)
+	ListMovements(ctx context.Context, walletID string, from, to time.Time) ([]domain.Movement, error)
 	Recharge(ctx context.Context, walletID string, amount float64) error
+	// Transfer moves amount between two wallets once the risk rules allow it.
+	// A transfer they hold for review returns a *domain.TransferHeldError and
+	// is only made if an operator approves it (see TransferReviewUsecase).
+	// The owners of both wallets are screened against the sanctions list: a
+	// possible match is held for review too, a confirmed one returns
+	// ErrSanctioned.
 	Transfer(ctx context.Context, fromWalletID, toWalletID string, amount float64) error
+	// PreviewFee returns the fee op would cost on a wallet, without moving any money.
+	PreviewFee(ctx context.Context, walletID string, op domain.FeeOperation, amount float64) (*domain.FeeQuote, error)
+
+	// Adjust credits (positive amount) or debits (negative amount) a wallet
+	// outside of the regular flows, e.g. to correct an operator error. It only
+	// applies adjustments approved through AdjustmentUsecase, and returns
+	// ErrAdjustmentNotApproved otherwise.
+	Adjust(ctx context.Context, walletID string, amount float64, reason string) (*domain.Movement, error)
+	Freeze(ctx context.Context, walletID, reason string) error
+	Unfreeze(ctx context.Context, walletID, reason string) error
+	// SetProduct changes the product of a wallet, and so the interest it earns
+	// from the next accrual on.
+	SetProduct(ctx context.Context, walletID, product, reason string) error
 }
 
 type walletUsecase struct {
-	walletRepo domain.WalletRepository
-	txnRepo    domain.TxnRepository
-	logger     *slog.Logger
+	walletRepo   domain.WalletRepository
+	userRepo     domain.UserRepository
+	movementRepo domain.MovementRepository
+	memberRepo   domain.WalletMemberRepository
+	reviewRepo   domain.TransferReviewRepository
+	feeRepo      domain.FeeCreditRepository
+	auditRepo    domain.AuditRepository
+	txnRepo      domain.TxnRepository
+	fees         *domain.FeeSchedule
+	risk         domain.RiskRulesSource
+	screening    ScreeningUsecase
+	logger       *slog.Logger
 }
 
-func NewWalletUsecase(wr domain.WalletRepository, tr domain.TxnRepository, logger *slog.Logger) WalletUsecase {
+// NewWalletUsecase charges the fees of the fee schedule; a nil schedule charges
+// none. Fees are queued in fr and credited to the house wallets later, by
+// FeeUsecase. Transfers are scored with the rules risk provides; a nil source scores
+// none. Users acting on a wallet must be members of it (see WalletMember).
+func NewWalletUsecase(wr domain.WalletRepository, ur domain.UserRepository, mr domain.MovementRepository, mbr domain.WalletMemberRepository, rr domain.TransferReviewRepository, fr domain.FeeCreditRepository, ar domain.AuditRepository, tr domain.TxnRepository, fees *domain.FeeSchedule, risk domain.RiskRulesSource, su ScreeningUsecase, logger *slog.Logger) WalletUsecase {
+	if fees == nil {
+		fees = &domain.FeeSchedule{}
+	}
 	return &walletUsecase{
-		walletRepo: wr,
-		txnRepo:    tr,
-		logger:     logger,
+		walletRepo:   wr,
+		userRepo:     ur,
+		movementRepo: mr,
+		memberRepo:   mbr,
+		reviewRepo:   rr,
+		feeRepo:      fr,
+		auditRepo:    ar,
+		txnRepo:      tr,
+		fees:         fees,
+		risk:         risk,
+		screening:    su,
+		logger:       logger,
+	}
+}
+
+// GetWallet returns a wallet for display. The balance may come from the cache,
+// so it must never be used to decide a money movement.
+func (u *walletUsecase) GetWallet(ctx context.Context, walletID string) (*domain.Wallet, error) {
+	wallet, err := u.walletRepo.FindByID(ctx, walletID)
+	if err != nil {
+		return nil, err
 	}
+	if _, err := authorizeMember(ctx, u.memberRepo, walletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// ListMovements returns the ledger of a wallet between from (inclusive) and to (exclusive).
+func (u *walletUsecase) ListMovements(ctx context.Context, walletID string, from, to time.Time) ([]domain.Movement, error) {
+	if _, err := u.walletRepo.FindByID(ctx, walletID); err != nil {
+		return nil, err
+	}
+	if _, err := authorizeMember(ctx, u.memberRepo, walletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	return u.movementRepo.ListByWallet(ctx, walletID, from, to)
 }
 
 func (u *walletUsecase) Recharge(ctx context.Context, walletID string, amount float64) error {
@@ -31,16 +106,33 @@ func (u *walletUsecase) Recharge(ctx context.Context, walletID string, amount fl
 		return errors.New("recharge amount must be positive")
 	}
 
-	return u.txnRepo.WithTransaction(ctx, func(txCtx context.Context) error {
+	return withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
 		wallet, err := u.walletRepo.FindByID(txCtx, walletID)
 		if err != nil {
 			return err
 		}
+		if wallet.IsFrozen() {
+			return domain.ErrWalletFrozen
+		}
+
+		// The fee is taken out of the recharged amount.
+		fee, feeWalletID, err := u.feeFor(txCtx, domain.FeeRecharge, wallet, amount)
+		if err != nil {
+			return err
+		}
+		if fee > amount {
+			return domain.ErrFeeExceedsAmount
+		}
 
 		wallet.Balance += amount
-		u.logger.InfoContext(txCtx, "recharging wallet", "wallet_id", walletID, "amount", amount)
+		movements := []*domain.Movement{newMovement(txCtx, wallet, domain.MovementRecharge, amount, nil, "")}
+		movements = append(movements, chargeFee(txCtx, domain.FeeRecharge, wallet, feeWalletID, fee)...)
+		u.logger.InfoContext(txCtx, "recharging wallet", "wallet_id", walletID, "amount", amount, "fee", fee)
 
-		return u.walletRepo.Update(txCtx, wallet)
+		if err := u.walletRepo.Update(txCtx, wallet); err != nil {
+			return err
+		}
+		return u.saveMovements(txCtx, movements)
 	})
 }
 
@@ -52,28 +144,112 @@ func (u *walletUsecase) Transfer(ctx context.Context, fromWalletID, toWalletID s
 		return errors.New("cannot transfer to the same wallet")
 	}
 
-	return u.txnRepo.WithTransaction(ctx, func(txCtx context.Context) error {
+	// A transfer made within another operation (a batch, a schedule, a payment
+	// request) saves its review in that operation's transaction: the caller
+	// records what it did as held and commits.
+	var held *domain.TransferReview
+	var sanctioned bool
+	err := withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+		held, sanctioned = nil, false
 		fromWallet, err := u.walletRepo.FindByID(txCtx, fromWalletID)
-		if err != nil {
+		if errors.Is(err, domain.ErrWalletNotFound) {
 			return errors.New("sender wallet not found")
 		}
-
-		if fromWallet.Balance < amount {
-			return errors.New("insufficient funds")
+		if err != nil {
+			return err
 		}
 
 		toWallet, err := u.walletRepo.FindByID(txCtx, toWalletID)
-		if err != nil {
+		if errors.Is(err, domain.ErrWalletNotFound) {
 			return errors.New("receiver wallet not found")
 		}
+		if err != nil {
+			return err
+		}
+		if fromWallet.Currency != toWallet.Currency {
+			return domain.ErrCurrencyMismatch
+		}
+
+		// The sender pays the fee on top of the amount.
+		fee, feeWalletID, err := u.feeFor(txCtx, domain.FeeTransfer, fromWallet, amount, toWallet)
+		if err != nil {
+			return err
+		}
+		if err := checkSpender(txCtx, u.memberRepo, u.movementRepo, fromWalletID, amount+fee); err != nil {
+			return err
+		}
+		if fromWallet.AvailableBalance() < amount+fee {
+			return domain.ErrInsufficientFunds
+		}
+
+		if fromWallet.IsFrozen() || toWallet.IsFrozen() {
+			return domain.ErrWalletFrozen
+		}
+
+		// The new hits are committed either way, so the refusal comes after.
+		flags, blocked, err := u.screenParties(txCtx, fromWallet, toWallet)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			sanctioned = true
+			return nil
+		}
+
+		assessment, err := u.assessRisk(txCtx, fromWalletID, toWalletID, amount)
+		if err != nil {
+			return err
+		}
+		if len(flags) > 0 && !riskApproved(txCtx) {
+			// A possible match waits for the review however low the score.
+			if assessment == nil {
+				assessment = &domain.RiskAssessment{Decision: domain.RiskAllow}
+			}
+			if assessment.Decision == domain.RiskAllow {
+				assessment.Decision = domain.RiskReview
+			}
+			assessment.Reasons = append(assessment.Reasons, flags...)
+		}
+		if assessment != nil && assessment.Decision != domain.RiskAllow {
+			reasons := strings.Join(assessment.Reasons, "; ")
+			u.logger.WarnContext(txCtx, "transfer flagged by risk rules",
+				"from_wallet", fromWalletID,
+				"to_wallet", toWalletID,
+				"amount", amount,
+				"score", assessment.Score,
+				"decision", assessment.Decision,
+				"reasons", reasons,
+			)
+			if assessment.Decision == domain.RiskBlock {
+				return fmt.Errorf("%w: %s", domain.ErrTransferBlocked, reasons)
+			}
+			// Only the review is saved: no money moves until it's approved.
+			held = &domain.TransferReview{
+				ID:           uuid.New().String(),
+				FromWalletID: fromWalletID,
+				ToWalletID:   toWalletID,
+				Amount:       amount,
+				Score:        assessment.Score,
+				Reasons:      reasons,
+				Status:       domain.ReviewPending,
+				Actor:        domain.ActorFromContext(txCtx),
+			}
+			return u.reviewRepo.Save(txCtx, held)
+		}
 
 		fromWallet.Balance -= amount
 		toWallet.Balance += amount
+		movements := []*domain.Movement{
+			newMovement(txCtx, fromWallet, domain.MovementTransferOut, -amount, &toWalletID, ""),
+			newMovement(txCtx, toWallet, domain.MovementTransferIn, amount, &fromWalletID, ""),
+		}
+		movements = append(movements, chargeFee(txCtx, domain.FeeTransfer, fromWallet, feeWalletID, fee)...)
 
 		u.logger.InfoContext(txCtx, "transferring funds",
 			"from_wallet", fromWalletID,
 			"to_wallet", toWalletID,
 			"amount", amount,
+			"fee", fee,
 		)
 
 		if err := u.walletRepo.Update(txCtx, fromWallet); err != nil {
@@ -82,7 +258,359 @@ func (u *walletUsecase) Transfer(ctx context.Context, fromWalletID, toWalletID s
 		if err := u.walletRepo.Update(txCtx, toWallet); err != nil {
 			return err
 		}
+		return u.saveMovements(txCtx, movements)
+	})
+	if err == nil && sanctioned {
+		return domain.ErrSanctioned
+	}
+	if err == nil && held != nil {
+		return &domain.TransferHeldError{ReviewID: held.ID, Score: held.Score, Review: held}
+	}
+	return err
+}
 
-		return nil
+func (u *walletUsecase) PreviewFee(ctx context.Context, walletID string, op domain.FeeOperation, amount float64) (*domain.FeeQuote, error) {
+	if op != domain.FeeTransfer && op != domain.FeeRecharge {
+		return nil, fmt.Errorf("%w: unknown operation %q", domain.ErrInvalidFeeRequest, op)
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", domain.ErrInvalidFeeRequest)
+	}
+
+	wallet, err := u.walletRepo.FindByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	// The quote tells the owner's tier, so only members may ask for it.
+	if _, err := authorizeMember(ctx, u.memberRepo, walletID, isMember, domain.ErrNotWalletMember); err != nil {
+		return nil, err
+	}
+	fee, err := u.quoteFee(ctx, op, wallet, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &domain.FeeQuote{
+		Operation: op,
+		WalletID:  wallet.ID,
+		Currency:  wallet.Currency,
+		Amount:    amount,
+		Fee:       fee,
+		Total:     amount + fee,
+	}
+	if op == domain.FeeRecharge {
+		if fee > amount {
+			return nil, domain.ErrFeeExceedsAmount
+		}
+		quote.Total = amount - fee
+	}
+	return quote, nil
+}
+
+func (u *walletUsecase) Adjust(ctx context.Context, walletID string, amount float64, reason string) (*domain.Movement, error) {
+	if amount == 0 {
+		return nil, errors.New("adjustment amount cannot be zero")
+	}
+	if reason == "" {
+		return nil, errors.New("adjustment reason is required")
+	}
+	if !adjustmentApproved(ctx) {
+		return nil, domain.ErrAdjustmentNotApproved
+	}
+
+	var movement *domain.Movement
+	err := withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+		wallet, err := u.walletRepo.FindByID(txCtx, walletID)
+		if err != nil {
+			return err
+		}
+		// Adjustments apply to frozen wallets too: correcting them is often why they were frozen.
+		if amount < 0 && wallet.AvailableBalance() < -amount {
+			return domain.ErrInsufficientFunds
+		}
+
+		wallet.Balance += amount
+		u.logger.InfoContext(txCtx, "adjusting wallet",
+			"wallet_id", walletID,
+			"amount", amount,
+			"reason", reason,
+			"actor", domain.ActorFromContext(txCtx),
+		)
+
+		if err := u.walletRepo.Update(txCtx, wallet); err != nil {
+			return err
+		}
+
+		movement = newMovement(txCtx, wallet, domain.MovementAdjustment, amount, nil, reason)
+		if err := u.movementRepo.Save(txCtx, movement); err != nil {
+			return err
+		}
+		return u.audit(txCtx, domain.AuditWalletAdjusted, walletID, reason, map[string]interface{}{
+			"amount":        amount,
+			"balance_after": wallet.Balance,
+			"movement_id":   movement.ID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+func (u *walletUsecase) Freeze(ctx context.Context, walletID, reason string) error {
+	return u.setStatus(ctx, walletID, domain.WalletFrozen, domain.AuditWalletFrozen, reason)
+}
+
+func (u *walletUsecase) Unfreeze(ctx context.Context, walletID, reason string) error {
+	return u.setStatus(ctx, walletID, domain.WalletActive, domain.AuditWalletUnfrozen, reason)
+}
+
+func (u *walletUsecase) SetProduct(ctx context.Context, walletID, product, reason string) error {
+	if product == "" {
+		return errors.New("product is required")
+	}
+	if reason == "" {
+		return errors.New("reason is required")
+	}
+
+	return withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+		wallet, err := u.walletRepo.FindByID(txCtx, walletID)
+		if err != nil {
+			return err
+		}
+		if wallet.Product == product {
+			return nil
+		}
+
+		previous := wallet.Product
+		wallet.Product = product
+		u.logger.InfoContext(txCtx, "changing wallet product",
+			"wallet_id", walletID,
+			"from", previous,
+			"to", product,
+			"actor", domain.ActorFromContext(txCtx),
+		)
+
+		if err := u.walletRepo.Update(txCtx, wallet); err != nil {
+			return err
+		}
+		return u.audit(txCtx, domain.AuditWalletProduct, walletID, reason, map[string]interface{}{
+			"from": previous,
+			"to":   product,
+		})
 	})
 }
+
+func (u *walletUsecase) setStatus(ctx context.Context, walletID string, status domain.WalletStatus, action, reason string) error {
+	if reason == "" {
+		return errors.New("reason is required")
+	}
+
+	return withTxRetry(ctx, u.txnRepo, func(txCtx context.Context) error {
+		wallet, err := u.walletRepo.FindByID(txCtx, walletID)
+		if err != nil {
+			return err
+		}
+		if wallet.Status == status {
+			return nil
+		}
+
+		previous := wallet.Status
+		wallet.Status = status
+		u.logger.InfoContext(txCtx, "changing wallet status",
+			"wallet_id", walletID,
+			"from", previous,
+			"to", status,
+			"actor", domain.ActorFromContext(txCtx),
+		)
+
+		if err := u.walletRepo.Update(txCtx, wallet); err != nil {
+			return err
+		}
+		return u.audit(txCtx, action, walletID, reason, map[string]interface{}{
+			"from": previous,
+			"to":   status,
+		})
+	})
+}
+
+// checkSpender checks that the user acting in ctx, if any, may take amount
+// out of walletID: owners can spend freely, spenders within their daily limit.
+// The limit holds under concurrent transfers because they all update the
+// wallet, so only one of them commits and the others retry and see it.
+func checkSpender(ctx context.Context, memberRepo domain.WalletMemberRepository, movementRepo domain.MovementRepository, walletID string, amount float64) error {
+	member, err := authorizeMember(ctx, memberRepo, walletID, (*domain.WalletMember).CanSpend, domain.ErrSpendNotAllowed)
+	if err != nil || member == nil || member.Role != domain.RoleSpender {
+		return err
+	}
+
+	spent, err := movementRepo.SpentBy(ctx, walletID, domain.ActorFromContext(ctx), startOfDay(time.Now()))
+	if err != nil {
+		return err
+	}
+	if total := spent + amount; total > member.DailyLimit && !sameAmount(total, member.DailyLimit) {
+		return fmt.Errorf("%w: %.2f of %.2f left today", domain.ErrSpendLimitExceeded, max(member.DailyLimit-spent, 0), member.DailyLimit)
+	}
+	return nil
+}
+
+// assessRisk scores a transfer with the rules in force, or returns nil when
+// there are none or the transfer was approved by a review.
+func (u *walletUsecase) assessRisk(ctx context.Context, fromWalletID, toWalletID string, amount float64) (*domain.RiskAssessment, error) {
+	if u.risk == nil || riskApproved(ctx) {
+		return nil, nil
+	}
+	rules := u.risk.RiskRules()
+	if rules == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	recentSince, historySince := now, now
+	if rules.Velocity != nil {
+		recentSince = now.Add(-time.Duration(rules.Velocity.WindowMinutes) * time.Minute)
+	}
+	if rules.AmountSpike != nil {
+		historySince = now.AddDate(0, 0, -rules.AmountSpike.LookbackDays)
+	}
+	history, err := u.reviewRepo.TransferHistory(ctx, fromWalletID, toWalletID, recentSince, historySince)
+	if err != nil {
+		return nil, err
+	}
+	assessment := rules.Assess(amount, now, *history)
+	return &assessment, nil
+}
+
+// screenParties screens the owners of both wallets. It returns the reasons
+// to review the transfer for the owners with a possible match, and whether
+// either of them is blocked.
+func (u *walletUsecase) screenParties(ctx context.Context, fromWallet, toWallet *domain.Wallet) ([]string, bool, error) {
+	sender, err := u.userRepo.FindByID(ctx, fromWallet.UserID)
+	if err != nil {
+		return nil, false, err
+	}
+	recipient, err := u.userRepo.FindByID(ctx, toWallet.UserID)
+	if err != nil {
+		return nil, false, err
+	}
+	statuses, err := u.screening.Screen(ctx, domain.ScreenedOnTransfer, sender, recipient)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var flags []string
+	for _, party := range []struct {
+		user *domain.User
+		role string
+	}{{sender, "sender"}, {recipient, "recipient"}} {
+		switch statuses[party.user.ID] {
+		case domain.ScreeningBlocked:
+			return nil, true, nil
+		case domain.ScreeningFlagged:
+			flags = append(flags, "possible sanctions match for the "+party.role)
+		}
+	}
+	return flags, false, nil
+}
+
+// quoteFee returns the fee wallet pays for op on amount, according to the
+// rule for its currency and its owner's tier. The house fee wallet pays none.
+func (u *walletUsecase) quoteFee(ctx context.Context, op domain.FeeOperation, wallet *domain.Wallet, amount float64) (float64, error) {
+	if !u.fees.Charges(op) {
+		return 0, nil
+	}
+	feeWalletID, ok := u.fees.Wallets[wallet.Currency]
+	if wallet.ID == feeWalletID {
+		return 0, nil
+	}
+
+	user, err := u.userRepo.FindByID(ctx, wallet.UserID)
+	if err != nil {
+		return 0, err
+	}
+	rule := u.fees.Rule(op, wallet.Currency, user.Tier)
+	if rule == nil {
+		return 0, nil
+	}
+	fee := rule.Fee(amount)
+	if fee > 0 && !ok {
+		return 0, fmt.Errorf("no fee wallet configured for %s", wallet.Currency)
+	}
+	return fee, nil
+}
+
+// feeFor returns the fee wallet pays for op on amount and the ID of the house
+// wallet it is credited to, or no ID when there is no fee. The house wallet is
+// only read to check it, never locked; when it is one of loaded it isn't read
+// again.
+func (u *walletUsecase) feeFor(ctx context.Context, op domain.FeeOperation, wallet *domain.Wallet, amount float64, loaded ...*domain.Wallet) (float64, string, error) {
+	fee, err := u.quoteFee(ctx, op, wallet, amount)
+	if err != nil || fee == 0 {
+		return 0, "", err
+	}
+
+	feeWalletID := u.fees.Wallets[wallet.Currency]
+	var feeWallet *domain.Wallet
+	for _, w := range loaded {
+		if w.ID == feeWalletID {
+			feeWallet = w
+		}
+	}
+	if feeWallet == nil {
+		feeWallet, err = u.walletRepo.FindByID(ctx, feeWalletID)
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			return 0, "", fmt.Errorf("fee wallet %s for %s does not exist", feeWalletID, wallet.Currency)
+		}
+		if err != nil {
+			return 0, "", err
+		}
+	}
+	if feeWallet.Currency != wallet.Currency {
+		return 0, "", fmt.Errorf("fee wallet %s holds %s, not %s", feeWalletID, feeWallet.Currency, wallet.Currency)
+	}
+	return fee, feeWalletID, nil
+}
+
+// chargeFee takes fee out of payer and returns the movement recording it. The
+// caller saves payer; saveMovements queues the fee for the house wallet.
+func chargeFee(ctx context.Context, op domain.FeeOperation, payer *domain.Wallet, feeWalletID string, fee float64) []*domain.Movement {
+	if fee == 0 {
+		return nil
+	}
+	payer.Balance -= fee
+	return []*domain.Movement{newMovement(ctx, payer, domain.MovementFee, -fee, &feeWalletID, string(op)+" fee")}
+}
+
+// saveMovements saves movements and queues the fees among them to be credited
+// to their house wallet.
+func (u *walletUsecase) saveMovements(ctx context.Context, movements []*domain.Movement) error {
+	for _, movement := range movements {
+		if err := u.movementRepo.Save(ctx, movement); err != nil {
+			return err
+		}
+		if movement.Type == domain.MovementFee {
+			if err := u.feeRepo.Save(ctx, &domain.FeeCredit{MovementID: movement.ID}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (u *walletUsecase) audit(ctx context.Context, action, walletID, reason string, details map[string]interface{}) error {
+	return u.auditRepo.Record(ctx, newAuditEntry(ctx, action, domain.AuditEntityWallet, walletID, reason, details))
+}
+
+// newMovement builds the ledger entry of a change just applied to wallet.
+func newMovement(ctx context.Context, wallet *domain.Wallet, typ domain.MovementType, amount float64, counterparty *string, reference string) *domain.Movement {
+	return &domain.Movement{
+		ID:                   uuid.New().String(),
+		WalletID:             wallet.ID,
+		Type:                 typ,
+		Amount:               amount,
+		BalanceAfter:         wallet.Balance,
+		CounterpartyWalletID: counterparty,
+		Reference:            reference,
+		Actor:                domain.ActorFromContext(ctx),
+	}
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wallet/internal/domain"
+	"wallet/internal/infrastructure/cache"
+	"wallet/internal/infrastructure/cache/cachetest"
+)
+
+// A transfer must decide on the balance stored in the database, never on a
+// cached one, even when the cache holds a stale wallet.
+func TestTransferNeverUsesCachedBalance(t *testing.T) {
+	f := newWalletFixture(100, 0)
+	cached := cache.NewCachedWalletRepository(cachetest.NewMemory(), f.wallets, cache.Options{TTL: time.Hour})
+	wallets := f.usecase(cached, nil)
+	ctx := domain.WithActor(context.Background(), domain.UserActor("alice"))
+
+	// Cache Alice's wallet with 100, then spend it behind the cache's back,
+	// as another instance would before its invalidation reaches us.
+	if w, err := wallets.GetWallet(ctx, "w-alice"); err != nil || w.Balance != 100 {
+		t.Fatalf("GetWallet = %v, %v; want a balance of 100", w, err)
+	}
+	stale := f.wallets.get("w-alice")
+	stale.Balance = 10
+	f.wallets.Save(ctx, &stale)
+	if w, _ := cached.FindByID(ctx, "w-alice"); w.Balance != 100 {
+		t.Fatalf("cached balance = %v, want the stale 100", w.Balance)
+	}
+
+	if err := wallets.Transfer(ctx, "w-alice", "w-bob", 50); !errors.Is(err, domain.ErrInsufficientFunds) {
+		t.Fatalf("Transfer of 50 = %v, want ErrInsufficientFunds from the stored balance of 10", err)
+	}
+
+	f.wallets.reads = nil
+	if err := wallets.Transfer(ctx, "w-alice", "w-bob", 5); err != nil {
+		t.Fatalf("Transfer of 5: %v", err)
+	}
+	var readInTx bool
+	for _, read := range f.wallets.reads {
+		if read.walletID == "w-alice" && read.inTransaction {
+			readInTx = true
+			if read.balance != 10 {
+				t.Errorf("balance read in the transaction = %v, want the stored 10", read.balance)
+			}
+		}
+	}
+	if !readInTx {
+		t.Fatal("the sender's wallet was not read from the repository inside the transaction")
+	}
+	if got := f.wallets.get("w-alice").Balance; got != 5 {
+		t.Errorf("stored balance = %v, want 5 (10 - 5), not computed from the cached 100", got)
+	}
+
+	// The commit invalidated the stale entry.
+	if w, _ := cached.FindByID(ctx, "w-alice"); w.Balance != 5 {
+		t.Errorf("balance after the transfer = %v, want 5", w.Balance)
+	}
+}
+
+// Callers who don't say who they are act on no wallet; operators and
+// background jobs act on any.
+func TestAnonymousCallersActOnNoWallet(t *testing.T) {
+	f := newWalletFixture(100, 0)
+	wallets := f.usecase(nil, nil)
+
+	anonymous := context.Background()
+	if _, err := wallets.GetWallet(anonymous, "w-alice"); !errors.Is(err, domain.ErrNotWalletMember) {
+		t.Errorf("anonymous GetWallet: %v, want ErrNotWalletMember", err)
+	}
+	if err := wallets.Transfer(anonymous, "w-alice", "w-bob", 10); !errors.Is(err, domain.ErrSpendNotAllowed) {
+		t.Errorf("anonymous Transfer: %v, want ErrSpendNotAllowed", err)
+	}
+	if got := f.wallets.get("w-alice").Balance; got != 100 {
+		t.Fatalf("balance = %v after an anonymous transfer, want 100", got)
+	}
+
+	for _, actor := range []string{domain.OperatorActor("carol"), "system:scheduler"} {
+		ctx := domain.WithActor(context.Background(), actor)
+		if _, err := wallets.GetWallet(ctx, "w-alice"); err != nil {
+			t.Errorf("GetWallet as %s: %v", actor, err)
+		}
+		if err := wallets.Transfer(ctx, "w-alice", "w-bob", 10); err != nil {
+			t.Errorf("Transfer as %s: %v", actor, err)
+		}
+	}
+}
+
+// Money only moves between wallets of the same currency.
+func TestTransferRefusesAnotherCurrency(t *testing.T) {
+	f := newWalletFixture(100, 0)
+	f.wallets.Save(context.Background(), &domain.Wallet{ID: "w-bob-eur", UserID: "bob", Currency: "EUR", Status: domain.WalletActive})
+	wallets := f.usecase(nil, nil)
+	ctx := domain.WithActor(context.Background(), domain.UserActor("alice"))
+
+	if err := wallets.Transfer(ctx, "w-alice", "w-bob-eur", 10); !errors.Is(err, domain.ErrCurrencyMismatch) {
+		t.Fatalf("Transfer from USD to EUR = %v, want ErrCurrencyMismatch", err)
+	}
+	if alice, bob := f.wallets.get("w-alice").Balance, f.wallets.get("w-bob-eur").Balance; alice != 100 || bob != 0 {
+		t.Errorf("balances = %v and %v after a refused transfer, want 100 and 0", alice, bob)
+	}
+	if len(f.movements.movements) != 0 {
+		t.Errorf("%d movements recorded for a refused transfer, want none", len(f.movements.movements))
+	}
+}
+
+// A fee quote depends on the owner's tier, so only the wallet's members, and
+// operators, may ask for one.
+func TestFeePreviewIsForMembers(t *testing.T) {
+	f := newWalletFixture(100, 0)
+	wallets := f.usecase(nil, nil)
+
+	tests := []struct {
+		actor string
+		want  error
+	}{
+		{domain.UserActor("alice"), nil},
+		{domain.OperatorActor("carol"), nil},
+		{domain.UserActor("bob"), domain.ErrNotWalletMember},
+		{domain.AnonymousActor, domain.ErrNotWalletMember},
+	}
+	for _, tt := range tests {
+		ctx := domain.WithActor(context.Background(), tt.actor)
+		quote, err := wallets.PreviewFee(ctx, "w-alice", domain.FeeTransfer, 10)
+		if !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+			t.Errorf("PreviewFee as %s = %v, want %v", tt.actor, err, tt.want)
+		}
+		if err != nil && quote != nil {
+			t.Errorf("PreviewFee as %s returned a quote with its error", tt.actor)
+		}
+	}
+}
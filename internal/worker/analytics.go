@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/usecase"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// AnalyticsWorker counts new ledger movements in the analytics rollups. Every
+// API instance runs one; each movement is counted by one of them.
+type AnalyticsWorker struct {
+	usecase  usecase.AnalyticsUsecase
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewAnalyticsWorker creates a worker that looks for new movements every interval.
+func NewAnalyticsWorker(uc usecase.AnalyticsUsecase, interval time.Duration, logger *slog.Logger) *AnalyticsWorker {
+	return &AnalyticsWorker{usecase: uc, interval: interval, logger: logger}
+}
+
+// Start processes movement events until ctx is done.
+func (w *AnalyticsWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.usecase.ProcessEvents(ctx); err != nil && ctx.Err() == nil {
+			w.logger.ErrorContext(ctx, "failed to update analytics rollups", "error", err)
+			sentry.CurrentHub().Clone().CaptureException(fmt.Errorf("analytics: %w", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/usecase"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// FeeCreditWorker credits the fees charged to payers to the house wallets.
+// Every API instance runs one; each fee is credited by one of them.
+type FeeCreditWorker struct {
+	usecase  usecase.FeeUsecase
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewFeeCreditWorker creates a worker that credits the queued fees every interval.
+func NewFeeCreditWorker(uc usecase.FeeUsecase, interval time.Duration, logger *slog.Logger) *FeeCreditWorker {
+	return &FeeCreditWorker{usecase: uc, interval: interval, logger: logger}
+}
+
+// Start credits fees until ctx is done.
+func (w *FeeCreditWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.usecase.CreditPending(ctx); err != nil && ctx.Err() == nil {
+			w.logger.ErrorContext(ctx, "failed to credit fees", "error", err)
+			sentry.CurrentHub().Clone().CaptureException(fmt.Errorf("fee credits: %w", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// interestActor is who interest payments are attributed to.
+const interestActor = "system:interest"
+
+// InterestWorker accrues the previous day's interest once a day at a fixed
+// UTC time of day, and pays it at the start of each month. Every API instance
+// runs the worker; accruals and payments are idempotent, so overlapping runs
+// are harmless.
+type InterestWorker struct {
+	usecase usecase.InterestUsecase
+	at      time.Duration // offset from midnight UTC
+	logger  *slog.Logger
+}
+
+func NewInterestWorker(uc usecase.InterestUsecase, at time.Duration, logger *slog.Logger) *InterestWorker {
+	return &InterestWorker{usecase: uc, at: at, logger: logger}
+}
+
+// Start runs the accrual every day until ctx is done. It runs right away
+// first, catching up on any day missed while no instance was up.
+func (w *InterestWorker) Start(ctx context.Context) {
+	ctx = domain.WithActor(ctx, interestActor)
+
+	now := time.Now().UTC()
+	day := now.Truncate(24 * time.Hour)
+	if now.Before(day.Add(w.at)) {
+		day = day.AddDate(0, 0, -1)
+	}
+	for {
+		// day is when the run is due; it accrues the day before.
+		if _, err := w.usecase.Run(ctx, day.AddDate(0, 0, -1)); err != nil && ctx.Err() == nil {
+			w.logger.ErrorContext(ctx, "interest run failed", "day", day.Format(time.DateOnly), "error", err)
+			sentry.CurrentHub().Clone().CaptureException(fmt.Errorf("interest: %w", err))
+		}
+
+		day = day.AddDate(0, 0, 1)
+		timer := time.NewTimer(time.Until(day.Add(w.at)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
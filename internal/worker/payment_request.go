@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/usecase"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// PaymentRequestExpiryWorker expires the payment requests nobody resolved in
+// time. Every API instance runs one; each expired request is claimed by one
+// of them.
+type PaymentRequestExpiryWorker struct {
+	usecase  usecase.PaymentRequestUsecase
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewPaymentRequestExpiryWorker creates a worker that expires requests every interval.
+func NewPaymentRequestExpiryWorker(uc usecase.PaymentRequestUsecase, interval time.Duration, logger *slog.Logger) *PaymentRequestExpiryWorker {
+	return &PaymentRequestExpiryWorker{usecase: uc, interval: interval, logger: logger}
+}
+
+// Start expires requests until ctx is done.
+func (w *PaymentRequestExpiryWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.usecase.ExpireDue(ctx); err != nil && ctx.Err() == nil {
+			w.logger.ErrorContext(ctx, "failed to expire payment requests", "error", err)
+			sentry.CurrentHub().Clone().CaptureException(fmt.Errorf("payment request expiry: %w", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Package worker holds the background jobs run by the API process.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/domain"
+	"wallet/internal/usecase"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// maxReportedDiscrepancies caps the Sentry events of a single run; the full
+// list is in the reconciliation_discrepancies table.
+const maxReportedDiscrepancies = 20
+
+// reconciliationActor is who scheduled runs are attributed to.
+const reconciliationActor = "system:reconciliation"
+
+// ReconciliationWorker runs the reconciliation once a day at a fixed UTC time
+// of day. Every API instance runs the worker; the first one to start a day's
+// run owns it and the others skip it.
+type ReconciliationWorker struct {
+	usecase usecase.ReconciliationUsecase
+	at      time.Duration // offset from midnight UTC
+	logger  *slog.Logger
+}
+
+func NewReconciliationWorker(uc usecase.ReconciliationUsecase, at time.Duration, logger *slog.Logger) *ReconciliationWorker {
+	return &ReconciliationWorker{usecase: uc, at: at, logger: logger}
+}
+
+// Start runs the reconciliation every day until ctx is done. If today's run
+// is due but hasn't happened yet, e.g. because no instance was up, it runs
+// right away.
+func (w *ReconciliationWorker) Start(ctx context.Context) {
+	ctx = domain.WithActor(ctx, reconciliationActor)
+
+	now := time.Now().UTC()
+	day := now.Truncate(24 * time.Hour)
+	if now.Before(day.Add(w.at)) {
+		day = day.AddDate(0, 0, -1)
+	}
+	for {
+		w.runScheduled(ctx, day)
+
+		day = day.AddDate(0, 0, 1)
+		timer := time.NewTimer(time.Until(day.Add(w.at)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (w *ReconciliationWorker) runScheduled(ctx context.Context, day time.Time) {
+	_, err := Reconcile(ctx, w.usecase, domain.ReconciliationScheduled, &day)
+	switch {
+	case errors.Is(err, domain.ErrReconciliationAlreadyRan):
+		w.logger.InfoContext(ctx, "reconciliation already ran", "day", day.Format(time.DateOnly))
+	case err != nil:
+		w.logger.ErrorContext(ctx, "reconciliation failed", "day", day.Format(time.DateOnly), "error", err)
+	}
+}
+
+// Reconcile runs the reconciliation and reports its discrepancies, or its
+// failure, to Sentry.
+func Reconcile(ctx context.Context, uc usecase.ReconciliationUsecase, trigger domain.ReconciliationTrigger, scheduledFor *time.Time) (*usecase.ReconciliationReport, error) {
+	report, err := uc.Run(ctx, trigger, scheduledFor)
+	if errors.Is(err, domain.ErrReconciliationAlreadyRan) {
+		return nil, err
+	}
+
+	hub := sentry.CurrentHub().Clone()
+	if report != nil {
+		hub.ConfigureScope(func(scope *sentry.Scope) {
+			scope.SetTag("reconciliation_run_id", report.Run.ID)
+		})
+		reportDiscrepancies(hub, report)
+	}
+	if err != nil {
+		hub.CaptureException(fmt.Errorf("reconciliation: %w", err))
+	}
+	return report, err
+}
+
+// reportDiscrepancies sends one Sentry event per discrepancy, fingerprinted
+// by wallet or currency so the same problem found day after day is grouped.
+func reportDiscrepancies(hub *sentry.Hub, report *usecase.ReconciliationReport) {
+	for i, d := range report.Discrepancies {
+		if i == maxReportedDiscrepancies {
+			hub.CaptureMessage(fmt.Sprintf("reconciliation: %d more discrepancies not reported, see run %s",
+				len(report.Discrepancies)-i, report.Run.ID))
+			return
+		}
+
+		subject := d.Currency
+		if d.WalletID != nil {
+			subject = *d.WalletID
+		}
+		hub.WithScope(func(scope *sentry.Scope) {
+			scope.SetLevel(sentry.LevelError)
+			scope.SetFingerprint([]string{"reconciliation", string(d.Kind), subject})
+			scope.SetTag("discrepancy_kind", string(d.Kind))
+			scope.SetTag("currency", d.Currency)
+			if d.WalletID != nil {
+				scope.SetTag("wallet_id", *d.WalletID)
+			}
+			scope.SetContext("discrepancy", sentry.Context{
+				"expected": d.Expected,
+				"actual":   d.Actual,
+			})
+			hub.CaptureMessage(fmt.Sprintf("reconciliation: %s discrepancy for %s: ledger %.2f, balance %.2f",
+				d.Kind, subject, d.Expected, d.Actual))
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/usecase"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// ScheduledTransferWorker runs due scheduled transfers. Every API instance
+// runs one; a due transfer is claimed by exactly one of them.
+type ScheduledTransferWorker struct {
+	usecase  usecase.ScheduledTransferUsecase
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewScheduledTransferWorker creates a worker that looks for due transfers every interval.
+func NewScheduledTransferWorker(uc usecase.ScheduledTransferUsecase, interval time.Duration, logger *slog.Logger) *ScheduledTransferWorker {
+	return &ScheduledTransferWorker{usecase: uc, interval: interval, logger: logger}
+}
+
+// Start runs due transfers until ctx is done.
+func (w *ScheduledTransferWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.usecase.RunDue(ctx); err != nil && ctx.Err() == nil {
+			w.logger.ErrorContext(ctx, "failed to run scheduled transfers", "error", err)
+			sentry.CurrentHub().Clone().CaptureException(fmt.Errorf("scheduled transfers: %w", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
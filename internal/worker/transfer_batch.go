@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"wallet/internal/usecase"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// TransferBatchWorker processes submitted transfer batches one at a time.
+// Every API instance runs one; they share the queue without overlapping.
+type TransferBatchWorker struct {
+	usecase  usecase.TransferBatchUsecase
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewTransferBatchWorker creates a worker that looks for new batches every
+// interval while the queue is empty.
+func NewTransferBatchWorker(uc usecase.TransferBatchUsecase, interval time.Duration, logger *slog.Logger) *TransferBatchWorker {
+	return &TransferBatchWorker{usecase: uc, interval: interval, logger: logger}
+}
+
+// Start processes batches until ctx is done.
+func (w *TransferBatchWorker) Start(ctx context.Context) {
+	for {
+		processed, err := w.usecase.ProcessNext(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.logger.ErrorContext(ctx, "failed to process transfer batch", "error", err)
+			sentry.CurrentHub().Clone().CaptureException(fmt.Errorf("transfer batch: %w", err))
+		}
+		if processed && err == nil {
+			continue
+		}
+
+		timer := time.NewTimer(w.interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}